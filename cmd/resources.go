@@ -13,6 +13,7 @@ import (
 	"github.com/webitel/webrtc_recorder/infra/auth"
 	"github.com/webitel/webrtc_recorder/infra/consul"
 	"github.com/webitel/webrtc_recorder/infra/grpc_srv"
+	"github.com/webitel/webrtc_recorder/infra/http_srv"
 	_ "github.com/webitel/webrtc_recorder/infra/resolver"
 	"github.com/webitel/webrtc_recorder/infra/sql"
 	"github.com/webitel/webrtc_recorder/infra/sql/pgsql"
@@ -20,15 +21,20 @@ import (
 	"github.com/webitel/webrtc_recorder/infra/webrtc"
 	"github.com/webitel/webrtc_recorder/internal/handler"
 	"github.com/webitel/webrtc_recorder/internal/model"
+	"github.com/webitel/webrtc_recorder/internal/service"
 )
 
 type handlers struct {
 	webrtcRecorder *handler.WebRTCRecorder
+	fileJobs       *handler.FileJobs
+	recovery       *service.Recovery
+	whip           *handler.WHIP
 }
 
 type resources struct {
 	log     *wlog.Logger
 	grpcSrv *grpc_srv.Server
+	httpSrv *http_srv.Server
 	cluster *consul.Cluster
 	store   sql.Store
 	webrtc  webrtc.API
@@ -50,6 +56,24 @@ func grpcSrv(cfg *config.Config, l *wlog.Logger, am auth.Manager) (*grpc_srv.Ser
 	}, nil
 }
 
+// httpSrv is nil when the WHIP ingest is disabled.
+func httpSrv(cfg *config.Config, l *wlog.Logger, am auth.Manager) (*http_srv.Server, func(), error) {
+	if cfg.Service.HTTPAddress == "" {
+		return nil, func() {}, nil
+	}
+
+	s, err := http_srv.New(cfg.Service.HTTPAddress, l, am)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, func() {
+		if err := s.Shutdown(); err != nil {
+			l.Error(err.Error(), wlog.Err(err))
+		}
+	}, nil
+}
+
 func log(cfg *config.Config) (*wlog.Logger, func(), error) {
 	logSettings := cfg.Log
 
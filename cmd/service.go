@@ -50,6 +50,14 @@ func (a *App) Run() (func(), error) {
 		return r.grpcSrv.Listen()
 	})
 
+	if r.httpSrv != nil {
+		a.eg.Go(func() error {
+			a.log.Info("listen http " + r.httpSrv.Addr)
+
+			return r.httpSrv.Listen()
+		})
+	}
+
 	return shutdown, nil
 }
 
@@ -105,6 +113,13 @@ func apiFlags(cfg *config.Config) []cli.Flag {
 			Aliases:     []string{"b"},
 			EnvVars:     []string{"BIND_ADDRESS"},
 		},
+		&cli.StringFlag{
+			Name:        "http-address",
+			Category:    "server",
+			Usage:       "address of the WHIP ingest endpoint (HTTP), empty disables it",
+			Destination: &cfg.Service.HTTPAddress,
+			EnvVars:     []string{"HTTP_ADDRESS"},
+		},
 		&cli.StringFlag{
 			Name:        "consul-discovery",
 			Category:    "server",
@@ -123,10 +138,11 @@ func apiFlags(cfg *config.Config) []cli.Flag {
 			Destination: &cfg.SQLSettings.DSN,
 		},
 		// vp8
+		// the other supported codecs change the negotiated SDP, so they are enabled explicitly
 		&cli.StringSliceFlag{
 			Name:        "webrtc-codecs",
 			Category:    "webrtc",
-			Usage:       "webrtc support codecs (video/VP9, video/H264, audio/opus)",
+			Usage:       "webrtc support codecs (video/VP8, video/VP9, video/AV1, video/H264, video/H265, audio/opus, audio/PCMU, audio/PCMA, audio/G722)",
 			Value:       cli.NewStringSlice("video/VP9", "video/H264", "audio/opus@48000", "audio/opus@16000"),
 			EnvVars:     []string{"WEBRTC_CODECS"},
 			Destination: &cfg.Rtc.Codecs,
@@ -171,6 +187,30 @@ func apiFlags(cfg *config.Config) []cli.Flag {
 			EnvVars:     []string{"WEBRTC_UDP_PORT_RANGE"},
 			Destination: &cfg.Rtc.EphemeralUDPPortRange,
 		},
+		&cli.DurationFlag{
+			Name:        "webrtc-segment-duration",
+			Category:    "webrtc",
+			Usage:       "roll the recording over to a new part every duration (0 - disabled)",
+			Value:       0,
+			EnvVars:     []string{"WEBRTC_SEGMENT_DURATION"},
+			Destination: &cfg.Rtc.SegmentDuration,
+		},
+		&cli.DurationFlag{
+			Name:        "webrtc-gap-threshold",
+			Category:    "webrtc",
+			Usage:       "min pause of the track filled with silence or the last frame (0 - disabled)",
+			Value:       time.Second,
+			EnvVars:     []string{"WEBRTC_GAP_THRESHOLD"},
+			Destination: &cfg.Rtc.GapThreshold,
+		},
+		&cli.DurationFlag{
+			Name:        "webrtc-reconnect-grace",
+			Category:    "webrtc",
+			Usage:       "time the disconnected session waits for the ICE restart or the reconnect with the same uuid before it is finalized (0 - disabled)",
+			Value:       time.Second * 30,
+			EnvVars:     []string{"WEBRTC_RECONNECT_GRACE"},
+			Destination: &cfg.Rtc.ReconnectGrace,
+		},
 		&cli.StringFlag{
 			Name:        "cache-dir",
 			Category:    "cache",
@@ -179,6 +219,43 @@ func apiFlags(cfg *config.Config) []cli.Flag {
 			EnvVars:     []string{"CACHE_TEMP_DIR"},
 			Destination: &cfg.TempDir,
 		},
+		&cli.DurationFlag{
+			Name:        "cache-max-age",
+			Category:    "cache",
+			Usage:       "remove temp files not referenced by any job after this age on startup (0 - disabled)",
+			Value:       time.Hour * 72,
+			EnvVars:     []string{"CACHE_TEMP_MAX_AGE"},
+			Destination: &cfg.TempMaxAge,
+		},
+		&cli.StringFlag{
+			Name:        "cache-quarantine-dir",
+			Category:    "cache",
+			Usage:       "dir for the files of failed jobs (default <cache-dir>/quarantine)",
+			EnvVars:     []string{"CACHE_QUARANTINE_DIR"},
+			Destination: &cfg.QuarantineDir,
+		},
+		&cli.BoolFlag{
+			Name:        "cache-shared",
+			Category:    "cache",
+			Usage:       "temp file cache dir is a volume shared by all instances",
+			EnvVars:     []string{"CACHE_SHARED"},
+			Destination: &cfg.SharedTemp,
+		},
+		&cli.DurationFlag{
+			Name:        "jobs-lease-ttl",
+			Category:    "jobs",
+			Usage:       "time the instance owns its jobs without renewing the lease",
+			Value:       time.Minute,
+			EnvVars:     []string{"JOBS_LEASE_TTL"},
+			Destination: &cfg.Jobs.LeaseTTL,
+		},
+		&cli.BoolFlag{
+			Name:        "jobs-steal",
+			Category:    "jobs",
+			Usage:       "claim jobs of the instances that are gone, requires cache-shared",
+			EnvVars:     []string{"JOBS_STEAL"},
+			Destination: &cfg.Jobs.Steal,
+		},
 		&cli.IntFlag{
 			Name:        "uploader-workers",
 			Category:    "uploader",
@@ -203,6 +280,110 @@ func apiFlags(cfg *config.Config) []cli.Flag {
 			EnvVars:     []string{"UPLOADER_MAX_RETRY"},
 			Destination: &cfg.Uploader.MaxRetry,
 		},
+		&cli.DurationFlag{
+			Name:        "uploader-backoff-min",
+			Category:    "uploader",
+			Usage:       "uploader delay before the second attempt",
+			Value:       time.Second * 5,
+			EnvVars:     []string{"UPLOADER_BACKOFF_MIN"},
+			Destination: &cfg.Uploader.Backoff.Min,
+		},
+		&cli.DurationFlag{
+			Name:        "uploader-backoff-max",
+			Category:    "uploader",
+			Usage:       "uploader max delay between attempts",
+			Value:       time.Minute * 10,
+			EnvVars:     []string{"UPLOADER_BACKOFF_MAX"},
+			Destination: &cfg.Uploader.Backoff.Max,
+		},
+		&cli.Float64Flag{
+			Name:        "uploader-backoff-factor",
+			Category:    "uploader",
+			Usage:       "uploader delay multiplier of each next attempt",
+			Value:       2,
+			EnvVars:     []string{"UPLOADER_BACKOFF_FACTOR"},
+			Destination: &cfg.Uploader.Backoff.Factor,
+		},
+		&cli.BoolFlag{
+			Name:        "uploader-backoff-jitter",
+			Category:    "uploader",
+			Usage:       "uploader randomize delay between attempts",
+			Value:       true,
+			EnvVars:     []string{"UPLOADER_BACKOFF_JITTER"},
+			Destination: &cfg.Uploader.Backoff.Jitter,
+		},
+		&cli.StringFlag{
+			Name:        "uploader-sink",
+			Category:    "uploader",
+			Usage:       "default upload destination (storage, local, s3)",
+			Value:       "storage",
+			EnvVars:     []string{"UPLOADER_SINK"},
+			Destination: &cfg.Uploader.Sink,
+		},
+		&cli.BoolFlag{
+			Name:        "uploader-resumable",
+			Category:    "uploader",
+			Usage:       "resume storage uploads of all files, the storage does not keep their uploaded_by and created_at",
+			EnvVars:     []string{"UPLOADER_RESUMABLE"},
+			Destination: &cfg.Uploader.Resumable,
+		},
+		&cli.StringFlag{
+			Name:        "uploader-local-dir",
+			Category:    "uploader",
+			Usage:       "root dir of the local upload destination",
+			EnvVars:     []string{"UPLOADER_LOCAL_DIR"},
+			Destination: &cfg.Uploader.LocalDir,
+		},
+		&cli.StringFlag{
+			Name:        "uploader-s3-endpoint",
+			Category:    "uploader",
+			Usage:       "S3 compatible storage endpoint (host:port)",
+			EnvVars:     []string{"UPLOADER_S3_ENDPOINT"},
+			Destination: &cfg.Uploader.S3.Endpoint,
+		},
+		&cli.StringFlag{
+			Name:        "uploader-s3-region",
+			Category:    "uploader",
+			Usage:       "S3 region",
+			EnvVars:     []string{"UPLOADER_S3_REGION"},
+			Destination: &cfg.Uploader.S3.Region,
+		},
+		&cli.StringFlag{
+			Name:        "uploader-s3-bucket",
+			Category:    "uploader",
+			Usage:       "S3 bucket",
+			EnvVars:     []string{"UPLOADER_S3_BUCKET"},
+			Destination: &cfg.Uploader.S3.Bucket,
+		},
+		&cli.StringFlag{
+			Name:        "uploader-s3-access-key",
+			Category:    "uploader",
+			Usage:       "S3 access key",
+			EnvVars:     []string{"UPLOADER_S3_ACCESS_KEY"},
+			Destination: &cfg.Uploader.S3.AccessKey,
+		},
+		&cli.StringFlag{
+			Name:        "uploader-s3-secret-key",
+			Category:    "uploader",
+			Usage:       "S3 secret key",
+			EnvVars:     []string{"UPLOADER_S3_SECRET_KEY"},
+			Destination: &cfg.Uploader.S3.SecretKey,
+		},
+		&cli.StringFlag{
+			Name:        "uploader-s3-prefix",
+			Category:    "uploader",
+			Usage:       "S3 object key prefix",
+			EnvVars:     []string{"UPLOADER_S3_PREFIX"},
+			Destination: &cfg.Uploader.S3.Prefix,
+		},
+		&cli.BoolFlag{
+			Name:        "uploader-s3-secure",
+			Category:    "uploader",
+			Usage:       "use https for S3",
+			Value:       true,
+			EnvVars:     []string{"UPLOADER_S3_SECURE"},
+			Destination: &cfg.Uploader.S3.Secure,
+		},
 
 		&cli.IntFlag{
 			Name:        "transcoding-workers",
@@ -228,5 +409,52 @@ func apiFlags(cfg *config.Config) []cli.Flag {
 			EnvVars:     []string{"TRANSCODING_MAX_RETRY"},
 			Destination: &cfg.Transcoding.MaxRetry,
 		},
+		&cli.DurationFlag{
+			Name:        "transcoding-backoff-min",
+			Category:    "transcoding",
+			Usage:       "transcoding delay before the second attempt",
+			Value:       time.Second * 5,
+			EnvVars:     []string{"TRANSCODING_BACKOFF_MIN"},
+			Destination: &cfg.Transcoding.Backoff.Min,
+		},
+		&cli.DurationFlag{
+			Name:        "transcoding-backoff-max",
+			Category:    "transcoding",
+			Usage:       "transcoding max delay between attempts",
+			Value:       time.Minute * 5,
+			EnvVars:     []string{"TRANSCODING_BACKOFF_MAX"},
+			Destination: &cfg.Transcoding.Backoff.Max,
+		},
+		&cli.Float64Flag{
+			Name:        "transcoding-backoff-factor",
+			Category:    "transcoding",
+			Usage:       "transcoding delay multiplier of each next attempt",
+			Value:       2,
+			EnvVars:     []string{"TRANSCODING_BACKOFF_FACTOR"},
+			Destination: &cfg.Transcoding.Backoff.Factor,
+		},
+		&cli.BoolFlag{
+			Name:        "transcoding-backoff-jitter",
+			Category:    "transcoding",
+			Usage:       "transcoding randomize delay between attempts",
+			Value:       true,
+			EnvVars:     []string{"TRANSCODING_BACKOFF_JITTER"},
+			Destination: &cfg.Transcoding.Backoff.Jitter,
+		},
+		&cli.StringFlag{
+			Name:        "transcoding-profile",
+			Category:    "transcoding",
+			Usage:       "transcoding profile of the recordings that do not set one (default, hd720, archive, audio, lossless)",
+			Value:       "default",
+			EnvVars:     []string{"TRANSCODING_PROFILE"},
+			Destination: &cfg.Transcoding.Profile,
+		},
+		&cli.StringSliceFlag{
+			Name:        "transcoding-domain-profiles",
+			Category:    "transcoding",
+			Usage:       "transcoding profile of the domain recordings (domain_id=profile)",
+			EnvVars:     []string{"TRANSCODING_DOMAIN_PROFILES"},
+			Destination: &cfg.Transcoding.DomainProfiles,
+		},
 	}
 }
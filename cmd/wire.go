@@ -9,13 +9,14 @@ import (
 	"github.com/google/wire"
 
 	"github.com/webitel/webrtc_recorder/config"
+	"github.com/webitel/webrtc_recorder/infra/consul"
 	"github.com/webitel/webrtc_recorder/internal/handler"
 	"github.com/webitel/webrtc_recorder/internal/service"
 	"github.com/webitel/webrtc_recorder/internal/store"
 )
 
 var wireAppResourceSet = wire.NewSet(
-	log, grpcSrv, setupCluster, setupSQL, webrtcAPI, authManager, storageClient,
+	log, grpcSrv, httpSrv, setupCluster, setupSQL, webrtcAPI, authManager, storageClient,
 )
 
 var wireAppHandlersSet = wire.NewSet(
@@ -23,25 +24,32 @@ var wireAppHandlersSet = wire.NewSet(
 	store.NewFileJobStore,
 
 	service.NewTempFileService,
+	service.NewSessionEvents,
+	service.NewJobLease,
 	service.NewUploader,
 	service.NewTranscoding, wire.Bind(new(service.FileJobStore), new(*store.FileJobStore)),
+	service.NewRecovery,
+	service.NewJobManager,
 
 	service.NewWebRtcRecorder, wire.Bind(new(service.SessionStore), new(*store.SessionStore)),
 
 	handler.NewWebRTCRecorder, wire.Bind(new(handler.WebRTCRecorderService), new(*service.WebRtcRecorder)),
+	handler.NewFileJobs, wire.Bind(new(handler.FileJobService), new(*service.JobManager)),
+	handler.NewWHIP, wire.Bind(new(handler.WHIPService), new(*service.WebRtcRecorder)),
 )
 
 func initAppResources(context.Context, *config.Config) (*resources, func(), error) {
 	wire.Build(wireAppResourceSet, wire.Struct(new(resources),
-		"log", "store", "grpcSrv", "cluster", "webrtc", "auth", "storage", "cfg"))
+		"log", "store", "grpcSrv", "httpSrv", "cluster", "webrtc", "auth", "storage", "cfg"))
 
 	return &resources{}, nil, nil
 }
 
 func initAppHandlers(context.Context, *resources) (*handlers, error) {
 	wire.Build(wireAppHandlersSet,
-		wire.FieldsOf(new(*resources), "log", "grpcSrv", "webrtc", "storage", "cfg", "store"),
-		wire.Struct(new(handlers), "webrtcRecorder"),
+		wire.FieldsOf(new(*resources), "log", "grpcSrv", "httpSrv", "webrtc", "storage", "cfg", "store", "cluster"),
+		wire.Bind(new(service.Cluster), new(*consul.Cluster)),
+		wire.Struct(new(handlers), "webrtcRecorder", "fileJobs", "recovery", "whip"),
 	)
 
 	return &handlers{}, nil
@@ -44,7 +44,7 @@ func initAppResources(contextContext context.Context, configConfig *config.Confi
 		cleanup()
 		return nil, nil, err
 	}
-	cluster, cleanup5, err := setupCluster(configConfig, server, logger)
+	http_srvServer, cleanup5, err := httpSrv(configConfig, logger, manager)
 	if err != nil {
 		cleanup4()
 		cleanup3()
@@ -52,7 +52,7 @@ func initAppResources(contextContext context.Context, configConfig *config.Confi
 		cleanup()
 		return nil, nil, err
 	}
-	api, cleanup6, err := webrtcAPI(logger, configConfig)
+	cluster, cleanup6, err := setupCluster(configConfig, server, logger)
 	if err != nil {
 		cleanup5()
 		cleanup4()
@@ -61,7 +61,7 @@ func initAppResources(contextContext context.Context, configConfig *config.Confi
 		cleanup()
 		return nil, nil, err
 	}
-	storage, cleanup7, err := storageClient(configConfig, logger)
+	api, cleanup7, err := webrtcAPI(logger, configConfig)
 	if err != nil {
 		cleanup6()
 		cleanup5()
@@ -71,10 +71,22 @@ func initAppResources(contextContext context.Context, configConfig *config.Confi
 		cleanup()
 		return nil, nil, err
 	}
+	storage, cleanup8, err := storageClient(configConfig, logger)
+	if err != nil {
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
 	cmdResources := &resources{
 		log:     logger,
 		store:   store,
 		grpcSrv: server,
+		httpSrv: http_srvServer,
 		cluster: cluster,
 		webrtc:  api,
 		auth:    manager,
@@ -82,6 +94,7 @@ func initAppResources(contextContext context.Context, configConfig *config.Confi
 		cfg:     configConfig,
 	}
 	return cmdResources, func() {
+		cleanup8()
 		cleanup7()
 		cleanup6()
 		cleanup5()
@@ -93,21 +106,41 @@ func initAppResources(contextContext context.Context, configConfig *config.Confi
 }
 
 func initAppHandlers(contextContext context.Context, cmdResources *resources) (*handlers, error) {
+	configConfig := cmdResources.cfg
 	logger := cmdResources.log
 	api := cmdResources.webrtc
 	sessionStore := store.NewSessionStore(logger)
-	configConfig := cmdResources.cfg
 	tempFileService := service.NewTempFileService(configConfig)
 	sqlStore := cmdResources.store
 	fileJobStore := store.NewFileJobStore(contextContext, logger, configConfig, sqlStore)
 	storage := cmdResources.storage
-	uploader := service.NewUploader(contextContext, configConfig, logger, fileJobStore, tempFileService, storage)
-	transcoding := service.NewTranscoding(contextContext, configConfig, logger, fileJobStore, tempFileService, uploader)
-	webRtcRecorder := service.NewWebRtcRecorder(logger, api, sessionStore, tempFileService, transcoding)
+	sessionEvents := service.NewSessionEvents(logger)
+	cluster := cmdResources.cluster
+	jobLease, err := service.NewJobLease(contextContext, configConfig, logger, fileJobStore, cluster)
+	if err != nil {
+		return nil, err
+	}
+	uploader, err := service.NewUploader(contextContext, configConfig, logger, fileJobStore, tempFileService, storage, sessionEvents, jobLease)
+	if err != nil {
+		return nil, err
+	}
+	transcoding, err := service.NewTranscoding(contextContext, configConfig, logger, fileJobStore, tempFileService, uploader, sessionEvents, jobLease)
+	if err != nil {
+		return nil, err
+	}
+	webRtcRecorder := service.NewWebRtcRecorder(configConfig, logger, api, sessionStore, tempFileService, transcoding, sessionEvents)
 	server := cmdResources.grpcSrv
 	webRTCRecorder := handler.NewWebRTCRecorder(webRtcRecorder, server, logger)
+	jobManager := service.NewJobManager(configConfig, logger, fileJobStore, tempFileService, uploader, sessionEvents)
+	fileJobs := handler.NewFileJobs(jobManager, server, logger)
+	recovery := service.NewRecovery(configConfig, logger, fileJobStore, tempFileService, transcoding)
+	http_srvServer := cmdResources.httpSrv
+	whip := handler.NewWHIP(webRtcRecorder, http_srvServer, logger)
 	cmdHandlers := &handlers{
 		webrtcRecorder: webRTCRecorder,
+		fileJobs:       fileJobs,
+		recovery:       recovery,
+		whip:           whip,
 	}
 	return cmdHandlers, nil
 }
@@ -115,7 +148,7 @@ func initAppHandlers(contextContext context.Context, cmdResources *resources) (*
 // wire.go:
 
 var wireAppResourceSet = wire.NewSet(
-	log, grpcSrv, setupCluster, setupSQL, webrtcAPI, authManager, storageClient,
+	log, grpcSrv, httpSrv, setupCluster, setupSQL, webrtcAPI, authManager, storageClient,
 )
 
-var wireAppHandlersSet = wire.NewSet(store.NewSessionStore, store.NewFileJobStore, service.NewTempFileService, service.NewUploader, service.NewTranscoding, wire.Bind(new(service.FileJobStore), new(*store.FileJobStore)), service.NewWebRtcRecorder, wire.Bind(new(service.SessionStore), new(*store.SessionStore)), handler.NewWebRTCRecorder, wire.Bind(new(handler.WebRTCRecorderService), new(*service.WebRtcRecorder)))
+var wireAppHandlersSet = wire.NewSet(store.NewSessionStore, store.NewFileJobStore, service.NewTempFileService, service.NewSessionEvents, service.NewJobLease, service.NewUploader, service.NewTranscoding, wire.Bind(new(service.FileJobStore), new(*store.FileJobStore)), service.NewRecovery, service.NewJobManager, service.NewWebRtcRecorder, wire.Bind(new(service.SessionStore), new(*store.SessionStore)), handler.NewWebRTCRecorder, wire.Bind(new(handler.WebRTCRecorderService), new(*service.WebRtcRecorder)), handler.NewFileJobs, wire.Bind(new(handler.FileJobService), new(*service.JobManager)), handler.NewWHIP, wire.Bind(new(handler.WHIPService), new(*service.WebRtcRecorder)))
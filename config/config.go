@@ -7,25 +7,63 @@ import (
 )
 
 type Config struct {
-	TempDir     string
-	Service     Service
-	Log         LogSettings
-	SQLSettings SQLSettings
-	Rtc         RtcSettings
-	Uploader    UploaderSettings
-	Transcoding TranscodingSettings
+	TempDir       string
+	TempMaxAge    time.Duration
+	QuarantineDir string
+	SharedTemp    bool
+	Jobs          JobSettings
+	Service       Service
+	Log           LogSettings
+	SQLSettings   SQLSettings
+	Rtc           RtcSettings
+	Uploader      UploaderSettings
+	Transcoding   TranscodingSettings
+}
+
+// JobSettings control the ownership of the jobs between the instances.
+type JobSettings struct {
+	LeaseTTL time.Duration
+	Steal    bool
 }
 
 type TranscodingSettings struct {
 	Workers  int
 	Queue    int
 	MaxRetry int
+	Backoff  BackoffSettings
+	// Profile is the default transcoding profile, DomainProfiles override it by domain_id=profile
+	Profile        string
+	DomainProfiles cli.StringSlice
 }
 
 type UploaderSettings struct {
 	Workers  int
 	Queue    int
 	MaxRetry int
+	Backoff  BackoffSettings
+	Sink     string
+	LocalDir string
+	S3       S3Settings
+	// Resumable uploads every file to the storage through SafeUploadFile, that does not keep uploaded_by and created_at
+	Resumable bool
+}
+
+// BackoffSettings delay the next attempt of a failed job exponentially.
+type BackoffSettings struct {
+	Min    time.Duration
+	Max    time.Duration
+	Factor float64
+	Jitter bool
+}
+
+type S3Settings struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Prefix    string
+	Secure    bool
 }
 
 type SQLSettings struct {
@@ -36,6 +74,8 @@ type Service struct {
 	ID      string
 	Address string
 	Consul  string
+	// HTTPAddress is the address of the WHIP ingest, empty disables it
+	HTTPAddress string
 }
 
 type RtcSettings struct {
@@ -47,6 +87,10 @@ type RtcSettings struct {
 		KeepAliveInterval   time.Duration
 	}
 	EphemeralUDPPortRange string // 10000-20000
+	SegmentDuration       time.Duration
+	GapThreshold          time.Duration
+	// ReconnectGrace keeps the disconnected session for the ICE restart or the reconnect with the same uuid
+	ReconnectGrace time.Duration
 }
 
 type LogSettings struct {
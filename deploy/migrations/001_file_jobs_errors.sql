@@ -0,0 +1,9 @@
+-- Failed attempts of the file jobs and the dead-letter state.
+alter table webrtc_rec.file_jobs
+    add column if not exists errors jsonb;
+
+-- 0 idle, 1 active, 2 failed
+alter table webrtc_rec.file_jobs
+    drop constraint if exists file_jobs_state_check;
+alter table webrtc_rec.file_jobs
+    add constraint file_jobs_state_check check (state in (0, 1, 2));
@@ -0,0 +1,8 @@
+-- Backoff of the failed attempts of the file jobs.
+alter table webrtc_rec.file_jobs
+    add column if not exists next_attempt_at timestamptz;
+
+-- the queue of the instance fetched by the workers
+create index if not exists file_jobs_fetch_idx
+    on webrtc_rec.file_jobs (instance, type, created_at, next_attempt_at)
+    where state = 0;
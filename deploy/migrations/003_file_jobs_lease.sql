@@ -0,0 +1,14 @@
+-- Leases of the file jobs taken over from the gone instances.
+alter table webrtc_rec.file_jobs
+    add column if not exists lease_until timestamptz;
+
+-- the jobs left active by the previous version expire from their last activity and can be claimed
+update webrtc_rec.file_jobs
+set lease_until = coalesce(activity_at, created_at, now())
+where state = 1
+    and lease_until isnull;
+
+-- the expired jobs claimed by the other instances
+create index if not exists file_jobs_lease_idx
+    on webrtc_rec.file_jobs (type, lease_until)
+    where state = 1;
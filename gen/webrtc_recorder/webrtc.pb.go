@@ -22,6 +22,82 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SessionEventType int32
+
+const (
+	SessionEventType_UnknownEvent      SessionEventType = 0
+	SessionEventType_TrackAdded        SessionEventType = 1
+	SessionEventType_IceStateChanged   SessionEventType = 2
+	SessionEventType_DataChannelOpened SessionEventType = 3
+	SessionEventType_SessionClosed     SessionEventType = 4
+	SessionEventType_JobStateChanged   SessionEventType = 5
+	SessionEventType_FileSaved         SessionEventType = 6
+	SessionEventType_TrackGap          SessionEventType = 7
+	SessionEventType_RecordingPaused   SessionEventType = 8
+	SessionEventType_RecordingResumed  SessionEventType = 9
+	SessionEventType_MarkerAdded       SessionEventType = 10
+	SessionEventType_TrackRemoved      SessionEventType = 11
+)
+
+// Enum value maps for SessionEventType.
+var (
+	SessionEventType_name = map[int32]string{
+		0:  "UnknownEvent",
+		1:  "TrackAdded",
+		2:  "IceStateChanged",
+		3:  "DataChannelOpened",
+		4:  "SessionClosed",
+		5:  "JobStateChanged",
+		6:  "FileSaved",
+		7:  "TrackGap",
+		8:  "RecordingPaused",
+		9:  "RecordingResumed",
+		10: "MarkerAdded",
+		11: "TrackRemoved",
+	}
+	SessionEventType_value = map[string]int32{
+		"UnknownEvent":      0,
+		"TrackAdded":        1,
+		"IceStateChanged":   2,
+		"DataChannelOpened": 3,
+		"SessionClosed":     4,
+		"JobStateChanged":   5,
+		"FileSaved":         6,
+		"TrackGap":          7,
+		"RecordingPaused":   8,
+		"RecordingResumed":  9,
+		"MarkerAdded":       10,
+		"TrackRemoved":      11,
+	}
+)
+
+func (x SessionEventType) Enum() *SessionEventType {
+	p := new(SessionEventType)
+	*p = x
+	return p
+}
+
+func (x SessionEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SessionEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_webrtc_proto_enumTypes[0].Descriptor()
+}
+
+func (SessionEventType) Type() protoreflect.EnumType {
+	return &file_webrtc_proto_enumTypes[0]
+}
+
+func (x SessionEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SessionEventType.Descriptor instead.
+func (SessionEventType) EnumDescriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{0}
+}
+
 type ICEServers struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -98,11 +174,26 @@ type UploadP2PVideoRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SdpOffer   string                    `protobuf:"bytes,1,opt,name=sdp_offer,json=sdpOffer,proto3" json:"sdp_offer,omitempty"`
-	Name       string                    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	SdpOffer string `protobuf:"bytes,1,opt,name=sdp_offer,json=sdpOffer,proto3" json:"sdp_offer,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// the session of the same user and uuid that waits in the reconnect grace period is continued,
+	// it keeps the name, room and transcoding settings it was started with
 	Uuid       string                    `protobuf:"bytes,3,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	IceServers []*ICEServers             `protobuf:"bytes,4,rep,name=ice_servers,json=iceServers,proto3" json:"ice_servers,omitempty"`
 	Channel    storage.UploadFileChannel `protobuf:"varint,5,opt,name=channel,proto3,enum=storage.UploadFileChannel" json:"channel,omitempty"`
+	// upload destination (storage, local, s3), empty uses the service default
+	Sink string `protobuf:"bytes,6,opt,name=sink,proto3" json:"sink,omitempty"`
+	// output of the transcoding, empty uses the domain default
+	Profile *TranscodingProfile `protobuf:"bytes,7,opt,name=profile,proto3" json:"profile,omitempty"`
+	// return the answer without waiting for the ICE gathering, the local candidates are streamed by
+	// WatchIceCandidates and the remote ones are added by AddIceCandidate
+	Trickle bool `protobuf:"varint,8,opt,name=trickle,proto3" json:"trickle,omitempty"`
+	// the sessions of the same room are recorded into one file, it is stored when the last participant leaves
+	RoomId string `protobuf:"bytes,9,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	// caption of the participant in the room recording, empty uses the user name
+	Participant string `protobuf:"bytes,10,opt,name=participant,proto3" json:"participant,omitempty"`
+	// layout of the composed video: grid (default), speaker, focus, pip, hstack or vstack
+	Layout string `protobuf:"bytes,11,opt,name=layout,proto3" json:"layout,omitempty"`
 }
 
 func (x *UploadP2PVideoRequest) Reset() {
@@ -172,6 +263,211 @@ func (x *UploadP2PVideoRequest) GetChannel() storage.UploadFileChannel {
 	return storage.UploadFileChannel(0)
 }
 
+func (x *UploadP2PVideoRequest) GetSink() string {
+	if x != nil {
+		return x.Sink
+	}
+	return ""
+}
+
+func (x *UploadP2PVideoRequest) GetProfile() *TranscodingProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *UploadP2PVideoRequest) GetTrickle() bool {
+	if x != nil {
+		return x.Trickle
+	}
+	return false
+}
+
+func (x *UploadP2PVideoRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *UploadP2PVideoRequest) GetParticipant() string {
+	if x != nil {
+		return x.Participant
+	}
+	return ""
+}
+
+func (x *UploadP2PVideoRequest) GetLayout() string {
+	if x != nil {
+		return x.Layout
+	}
+	return ""
+}
+
+// Zero fields keep the values of the named profile.
+type TranscodingProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// default, hd720, archive, audio, lossless; empty uses the domain default
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Width  int32  `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height int32  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	// frame rate cap
+	Fps int32 `protobuf:"varint,4,opt,name=fps,proto3" json:"fps,omitempty"`
+	// libx264, libx265, libvpx-vp9, libaom-av1
+	VideoCodec string `protobuf:"bytes,5,opt,name=video_codec,json=videoCodec,proto3" json:"video_codec,omitempty"`
+	// aac, libopus, flac, libmp3lame, pcm_s16le
+	AudioCodec string `protobuf:"bytes,6,opt,name=audio_codec,json=audioCodec,proto3" json:"audio_codec,omitempty"`
+	// 0-51 for libx264 and libx265, 0-63 for libvpx-vp9 and libaom-av1
+	Crf *int32 `protobuf:"varint,7,opt,name=crf,proto3,oneof" json:"crf,omitempty"`
+	// e.g. 800k, 2M
+	VideoBitrate string `protobuf:"bytes,8,opt,name=video_bitrate,json=videoBitrate,proto3" json:"video_bitrate,omitempty"`
+	AudioBitrate string `protobuf:"bytes,9,opt,name=audio_bitrate,json=audioBitrate,proto3" json:"audio_bitrate,omitempty"`
+	// mp4, mkv, webm, m4a
+	Container string `protobuf:"bytes,10,opt,name=container,proto3" json:"container,omitempty"`
+	AudioOnly bool   `protobuf:"varint,11,opt,name=audio_only,json=audioOnly,proto3" json:"audio_only,omitempty"`
+	// x264/x265 preset
+	Preset string `protobuf:"bytes,12,opt,name=preset,proto3" json:"preset,omitempty"`
+	// copy a single video with its audio without re-encoding when the codecs fit a container
+	// (VP9+Opus into webm, H264+Opus/AAC into mp4), the explicit parameters above turn it off
+	Passthrough *bool `protobuf:"varint,13,opt,name=passthrough,proto3,oneof" json:"passthrough,omitempty"`
+	// render the chat and the events of the data channels as the subtitle track (mp4, mkv, webm)
+	Subtitles bool `protobuf:"varint,14,opt,name=subtitles,proto3" json:"subtitles,omitempty"`
+}
+
+func (x *TranscodingProfile) Reset() {
+	*x = TranscodingProfile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TranscodingProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscodingProfile) ProtoMessage() {}
+
+func (x *TranscodingProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscodingProfile.ProtoReflect.Descriptor instead.
+func (*TranscodingProfile) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TranscodingProfile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TranscodingProfile) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *TranscodingProfile) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *TranscodingProfile) GetFps() int32 {
+	if x != nil {
+		return x.Fps
+	}
+	return 0
+}
+
+func (x *TranscodingProfile) GetVideoCodec() string {
+	if x != nil {
+		return x.VideoCodec
+	}
+	return ""
+}
+
+func (x *TranscodingProfile) GetAudioCodec() string {
+	if x != nil {
+		return x.AudioCodec
+	}
+	return ""
+}
+
+func (x *TranscodingProfile) GetCrf() int32 {
+	if x != nil && x.Crf != nil {
+		return *x.Crf
+	}
+	return 0
+}
+
+func (x *TranscodingProfile) GetVideoBitrate() string {
+	if x != nil {
+		return x.VideoBitrate
+	}
+	return ""
+}
+
+func (x *TranscodingProfile) GetAudioBitrate() string {
+	if x != nil {
+		return x.AudioBitrate
+	}
+	return ""
+}
+
+func (x *TranscodingProfile) GetContainer() string {
+	if x != nil {
+		return x.Container
+	}
+	return ""
+}
+
+func (x *TranscodingProfile) GetAudioOnly() bool {
+	if x != nil {
+		return x.AudioOnly
+	}
+	return false
+}
+
+func (x *TranscodingProfile) GetPreset() string {
+	if x != nil {
+		return x.Preset
+	}
+	return ""
+}
+
+func (x *TranscodingProfile) GetPassthrough() bool {
+	if x != nil && x.Passthrough != nil {
+		return *x.Passthrough
+	}
+	return false
+}
+
+func (x *TranscodingProfile) GetSubtitles() bool {
+	if x != nil {
+		return x.Subtitles
+	}
+	return false
+}
+
 type UploadP2PVideoResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -184,7 +480,7 @@ type UploadP2PVideoResponse struct {
 func (x *UploadP2PVideoResponse) Reset() {
 	*x = UploadP2PVideoResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_webrtc_proto_msgTypes[2]
+		mi := &file_webrtc_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -197,7 +493,7 @@ func (x *UploadP2PVideoResponse) String() string {
 func (*UploadP2PVideoResponse) ProtoMessage() {}
 
 func (x *UploadP2PVideoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_webrtc_proto_msgTypes[2]
+	mi := &file_webrtc_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -210,7 +506,7 @@ func (x *UploadP2PVideoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadP2PVideoResponse.ProtoReflect.Descriptor instead.
 func (*UploadP2PVideoResponse) Descriptor() ([]byte, []int) {
-	return file_webrtc_proto_rawDescGZIP(), []int{2}
+	return file_webrtc_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *UploadP2PVideoResponse) GetSdpAnswer() string {
@@ -238,7 +534,7 @@ type StopP2PVideoRequest struct {
 func (x *StopP2PVideoRequest) Reset() {
 	*x = StopP2PVideoRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_webrtc_proto_msgTypes[3]
+		mi := &file_webrtc_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -251,7 +547,7 @@ func (x *StopP2PVideoRequest) String() string {
 func (*StopP2PVideoRequest) ProtoMessage() {}
 
 func (x *StopP2PVideoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_webrtc_proto_msgTypes[3]
+	mi := &file_webrtc_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -264,7 +560,7 @@ func (x *StopP2PVideoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StopP2PVideoRequest.ProtoReflect.Descriptor instead.
 func (*StopP2PVideoRequest) Descriptor() ([]byte, []int) {
-	return file_webrtc_proto_rawDescGZIP(), []int{3}
+	return file_webrtc_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *StopP2PVideoRequest) GetId() string {
@@ -283,7 +579,7 @@ type StopP2PVideoResponse struct {
 func (x *StopP2PVideoResponse) Reset() {
 	*x = StopP2PVideoResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_webrtc_proto_msgTypes[4]
+		mi := &file_webrtc_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -296,7 +592,7 @@ func (x *StopP2PVideoResponse) String() string {
 func (*StopP2PVideoResponse) ProtoMessage() {}
 
 func (x *StopP2PVideoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_webrtc_proto_msgTypes[4]
+	mi := &file_webrtc_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -309,35 +605,34 @@ func (x *StopP2PVideoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StopP2PVideoResponse.ProtoReflect.Descriptor instead.
 func (*StopP2PVideoResponse) Descriptor() ([]byte, []int) {
-	return file_webrtc_proto_rawDescGZIP(), []int{4}
+	return file_webrtc_proto_rawDescGZIP(), []int{5}
 }
 
-type RenegotiateP2PVideoRequest struct {
+type PauseRecordingRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	SdpOffer string `protobuf:"bytes,2,opt,name=sdp_offer,json=sdpOffer,proto3" json:"sdp_offer,omitempty"`
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *RenegotiateP2PVideoRequest) Reset() {
-	*x = RenegotiateP2PVideoRequest{}
+func (x *PauseRecordingRequest) Reset() {
+	*x = PauseRecordingRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_webrtc_proto_msgTypes[5]
+		mi := &file_webrtc_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RenegotiateP2PVideoRequest) String() string {
+func (x *PauseRecordingRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenegotiateP2PVideoRequest) ProtoMessage() {}
+func (*PauseRecordingRequest) ProtoMessage() {}
 
-func (x *RenegotiateP2PVideoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_webrtc_proto_msgTypes[5]
+func (x *PauseRecordingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -348,50 +643,81 @@ func (x *RenegotiateP2PVideoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenegotiateP2PVideoRequest.ProtoReflect.Descriptor instead.
-func (*RenegotiateP2PVideoRequest) Descriptor() ([]byte, []int) {
-	return file_webrtc_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use PauseRecordingRequest.ProtoReflect.Descriptor instead.
+func (*PauseRecordingRequest) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *RenegotiateP2PVideoRequest) GetId() string {
+func (x *PauseRecordingRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *RenegotiateP2PVideoRequest) GetSdpOffer() string {
-	if x != nil {
-		return x.SdpOffer
+type PauseRecordingResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PauseRecordingResponse) Reset() {
+	*x = PauseRecordingResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-type RenegotiateP2PVideoResponse struct {
+func (x *PauseRecordingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseRecordingResponse) ProtoMessage() {}
+
+func (x *PauseRecordingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseRecordingResponse.ProtoReflect.Descriptor instead.
+func (*PauseRecordingResponse) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{7}
+}
+
+type ResumeRecordingRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SdpAnswer string `protobuf:"bytes,1,opt,name=sdp_answer,json=sdpAnswer,proto3" json:"sdp_answer,omitempty"`
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *RenegotiateP2PVideoResponse) Reset() {
-	*x = RenegotiateP2PVideoResponse{}
+func (x *ResumeRecordingRequest) Reset() {
+	*x = ResumeRecordingRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_webrtc_proto_msgTypes[6]
+		mi := &file_webrtc_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RenegotiateP2PVideoResponse) String() string {
+func (x *ResumeRecordingRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenegotiateP2PVideoResponse) ProtoMessage() {}
+func (*ResumeRecordingRequest) ProtoMessage() {}
 
-func (x *RenegotiateP2PVideoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_webrtc_proto_msgTypes[6]
+func (x *ResumeRecordingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -402,48 +728,1274 @@ func (x *RenegotiateP2PVideoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenegotiateP2PVideoResponse.ProtoReflect.Descriptor instead.
-func (*RenegotiateP2PVideoResponse) Descriptor() ([]byte, []int) {
-	return file_webrtc_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ResumeRecordingRequest.ProtoReflect.Descriptor instead.
+func (*ResumeRecordingRequest) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *RenegotiateP2PVideoResponse) GetSdpAnswer() string {
+func (x *ResumeRecordingRequest) GetId() string {
 	if x != nil {
-		return x.SdpAnswer
+		return x.Id
 	}
 	return ""
 }
 
-var File_webrtc_proto protoreflect.FileDescriptor
+type ResumeRecordingResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
 
-var file_webrtc_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f,
-	0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x1a,
-	0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f,
-	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0a, 0x66,
-	0x69, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x84, 0x01, 0x0a, 0x0a, 0x49, 0x43,
-	0x45, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x72, 0x6c, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x12, 0x1a, 0x0a, 0x08,
-	0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x72, 0x65, 0x64,
-	0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0e, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c,
-	0x22, 0xd0, 0x01, 0x0a, 0x15, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x32, 0x50, 0x56, 0x69,
-	0x64, 0x65, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x64,
-	0x70, 0x5f, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
-	0x64, 0x70, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75,
-	0x75, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12,
-	0x3c, 0x0a, 0x0b, 0x69, 0x63, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x04,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65,
-	0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x49, 0x43, 0x45, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x73, 0x52, 0x0a, 0x69, 0x63, 0x65, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x34, 0x0a,
+func (x *ResumeRecordingResponse) Reset() {
+	*x = ResumeRecordingResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResumeRecordingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeRecordingResponse) ProtoMessage() {}
+
+func (x *ResumeRecordingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeRecordingResponse.ProtoReflect.Descriptor instead.
+func (*ResumeRecordingResponse) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{9}
+}
+
+type AddMarkerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label string `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	// unix ms, the current time when empty
+	Timestamp int64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *AddMarkerRequest) Reset() {
+	*x = AddMarkerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddMarkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddMarkerRequest) ProtoMessage() {}
+
+func (x *AddMarkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddMarkerRequest.ProtoReflect.Descriptor instead.
+func (*AddMarkerRequest) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AddMarkerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AddMarkerRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *AddMarkerRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type AddMarkerResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Marker *Marker `protobuf:"bytes,1,opt,name=marker,proto3" json:"marker,omitempty"`
+}
+
+func (x *AddMarkerResponse) Reset() {
+	*x = AddMarkerResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddMarkerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddMarkerResponse) ProtoMessage() {}
+
+func (x *AddMarkerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddMarkerResponse.ProtoReflect.Descriptor instead.
+func (*AddMarkerResponse) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AddMarkerResponse) GetMarker() *Marker {
+	if x != nil {
+		return x.Marker
+	}
+	return nil
+}
+
+type RenegotiateP2PVideoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SdpOffer string `protobuf:"bytes,2,opt,name=sdp_offer,json=sdpOffer,proto3" json:"sdp_offer,omitempty"`
+}
+
+func (x *RenegotiateP2PVideoRequest) Reset() {
+	*x = RenegotiateP2PVideoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenegotiateP2PVideoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenegotiateP2PVideoRequest) ProtoMessage() {}
+
+func (x *RenegotiateP2PVideoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenegotiateP2PVideoRequest.ProtoReflect.Descriptor instead.
+func (*RenegotiateP2PVideoRequest) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RenegotiateP2PVideoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenegotiateP2PVideoRequest) GetSdpOffer() string {
+	if x != nil {
+		return x.SdpOffer
+	}
+	return ""
+}
+
+type RenegotiateP2PVideoResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SdpAnswer string `protobuf:"bytes,1,opt,name=sdp_answer,json=sdpAnswer,proto3" json:"sdp_answer,omitempty"`
+}
+
+func (x *RenegotiateP2PVideoResponse) Reset() {
+	*x = RenegotiateP2PVideoResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenegotiateP2PVideoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenegotiateP2PVideoResponse) ProtoMessage() {}
+
+func (x *RenegotiateP2PVideoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenegotiateP2PVideoResponse.ProtoReflect.Descriptor instead.
+func (*RenegotiateP2PVideoResponse) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RenegotiateP2PVideoResponse) GetSdpAnswer() string {
+	if x != nil {
+		return x.SdpAnswer
+	}
+	return ""
+}
+
+type SessionTrack struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// track id or data channel label
+	Id           string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MimeType     string `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	ClockRate    uint32 `protobuf:"varint,3,opt,name=clock_rate,json=clockRate,proto3" json:"clock_rate,omitempty"`
+	Packets      int64  `protobuf:"varint,4,opt,name=packets,proto3" json:"packets,omitempty"`
+	LostPackets  int64  `protobuf:"varint,5,opt,name=lost_packets,json=lostPackets,proto3" json:"lost_packets,omitempty"`
+	BytesWritten int64  `protobuf:"varint,6,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
+}
+
+func (x *SessionTrack) Reset() {
+	*x = SessionTrack{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionTrack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionTrack) ProtoMessage() {}
+
+func (x *SessionTrack) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionTrack.ProtoReflect.Descriptor instead.
+func (*SessionTrack) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SessionTrack) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SessionTrack) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *SessionTrack) GetClockRate() uint32 {
+	if x != nil {
+		return x.ClockRate
+	}
+	return 0
+}
+
+func (x *SessionTrack) GetPackets() int64 {
+	if x != nil {
+		return x.Packets
+	}
+	return 0
+}
+
+func (x *SessionTrack) GetLostPackets() int64 {
+	if x != nil {
+		return x.LostPackets
+	}
+	return 0
+}
+
+func (x *SessionTrack) GetBytesWritten() int64 {
+	if x != nil {
+		return x.BytesWritten
+	}
+	return 0
+}
+
+type Session struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Uuid     string          `protobuf:"bytes,3,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	DomainId int64           `protobuf:"varint,4,opt,name=domain_id,json=domainId,proto3" json:"domain_id,omitempty"`
+	UserId   int64           `protobuf:"varint,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IceState string          `protobuf:"bytes,6,opt,name=ice_state,json=iceState,proto3" json:"ice_state,omitempty"`
+	Tracks   []*SessionTrack `protobuf:"bytes,7,rep,name=tracks,proto3" json:"tracks,omitempty"`
+	// unix ms of the first media packet
+	StartTime int64 `protobuf:"varint,8,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	CreatedAt int64 `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// samples are not recorded until ResumeRecording
+	Paused bool `protobuf:"varint,10,opt,name=paused,proto3" json:"paused,omitempty"`
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Session) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Session) GetDomainId() int64 {
+	if x != nil {
+		return x.DomainId
+	}
+	return 0
+}
+
+func (x *Session) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Session) GetIceState() string {
+	if x != nil {
+		return x.IceState
+	}
+	return ""
+}
+
+func (x *Session) GetTracks() []*SessionTrack {
+	if x != nil {
+		return x.Tracks
+	}
+	return nil
+}
+
+func (x *Session) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *Session) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Session) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{16}
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*Session `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListSessionsResponse) GetItems() []*Session {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type GetSessionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetSessionRequest) Reset() {
+	*x = GetSessionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionRequest) ProtoMessage() {}
+
+func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetSessionRequest) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetSessionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type JobEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// transcoding or upload
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// active, retry, failed or done
+	State   string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Attempt int32  `protobuf:"varint,4,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Error   string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Segment int32  `protobuf:"varint,6,opt,name=segment,proto3" json:"segment,omitempty"`
+	// storage file id of the uploaded recording
+	FileId int64 `protobuf:"varint,7,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	// location of the recording in the upload destination
+	Location string `protobuf:"bytes,8,opt,name=location,proto3" json:"location,omitempty"`
+}
+
+func (x *JobEvent) Reset() {
+	*x = JobEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobEvent) ProtoMessage() {}
+
+func (x *JobEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobEvent.ProtoReflect.Descriptor instead.
+func (*JobEvent) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *JobEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *JobEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *JobEvent) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *JobEvent) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *JobEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobEvent) GetSegment() int32 {
+	if x != nil {
+		return x.Segment
+	}
+	return 0
+}
+
+func (x *JobEvent) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *JobEvent) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+// pause of the track filled with silence or the last frame
+type Gap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// position in the recorded media, ms
+	At int64 `protobuf:"varint,1,opt,name=at,proto3" json:"at,omitempty"`
+	// ms
+	Duration int64 `protobuf:"varint,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	// paused recording filled with the placeholder
+	Redacted bool `protobuf:"varint,3,opt,name=redacted,proto3" json:"redacted,omitempty"`
+}
+
+func (x *Gap) Reset() {
+	*x = Gap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Gap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Gap) ProtoMessage() {}
+
+func (x *Gap) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Gap.ProtoReflect.Descriptor instead.
+func (*Gap) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Gap) GetAt() int64 {
+	if x != nil {
+		return x.At
+	}
+	return 0
+}
+
+func (x *Gap) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *Gap) GetRedacted() bool {
+	if x != nil {
+		return x.Redacted
+	}
+	return false
+}
+
+type Marker struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Label string `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	// session clock, unix ms
+	Timestamp int64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *Marker) Reset() {
+	*x = Marker{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Marker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Marker) ProtoMessage() {}
+
+func (x *Marker) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Marker.ProtoReflect.Descriptor instead.
+func (*Marker) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Marker) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Marker) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type SessionEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId string           `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Type      SessionEventType `protobuf:"varint,2,opt,name=type,proto3,enum=webrtc_recorder.SessionEventType" json:"type,omitempty"`
+	// unix ms
+	Timestamp   int64         `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Track       *SessionTrack `protobuf:"bytes,4,opt,name=track,proto3" json:"track,omitempty"`
+	IceState    string        `protobuf:"bytes,5,opt,name=ice_state,json=iceState,proto3" json:"ice_state,omitempty"`
+	DataChannel string        `protobuf:"bytes,6,opt,name=data_channel,json=dataChannel,proto3" json:"data_channel,omitempty"`
+	Job         *JobEvent     `protobuf:"bytes,7,opt,name=job,proto3" json:"job,omitempty"`
+	Gap         *Gap          `protobuf:"bytes,8,opt,name=gap,proto3" json:"gap,omitempty"`
+	Marker      *Marker       `protobuf:"bytes,9,opt,name=marker,proto3" json:"marker,omitempty"`
+}
+
+func (x *SessionEvent) Reset() {
+	*x = SessionEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionEvent) ProtoMessage() {}
+
+func (x *SessionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionEvent.ProtoReflect.Descriptor instead.
+func (*SessionEvent) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SessionEvent) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionEvent) GetType() SessionEventType {
+	if x != nil {
+		return x.Type
+	}
+	return SessionEventType_UnknownEvent
+}
+
+func (x *SessionEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *SessionEvent) GetTrack() *SessionTrack {
+	if x != nil {
+		return x.Track
+	}
+	return nil
+}
+
+func (x *SessionEvent) GetIceState() string {
+	if x != nil {
+		return x.IceState
+	}
+	return ""
+}
+
+func (x *SessionEvent) GetDataChannel() string {
+	if x != nil {
+		return x.DataChannel
+	}
+	return ""
+}
+
+func (x *SessionEvent) GetJob() *JobEvent {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *SessionEvent) GetGap() *Gap {
+	if x != nil {
+		return x.Gap
+	}
+	return nil
+}
+
+func (x *SessionEvent) GetMarker() *Marker {
+	if x != nil {
+		return x.Marker
+	}
+	return nil
+}
+
+type WatchSessionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *WatchSessionRequest) Reset() {
+	*x = WatchSessionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSessionRequest) ProtoMessage() {}
+
+func (x *WatchSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSessionRequest.ProtoReflect.Descriptor instead.
+func (*WatchSessionRequest) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *WatchSessionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// The candidate attribute of the SDP ("candidate:..."), empty is the end of the gathering.
+type IceCandidate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Candidate string `protobuf:"bytes,1,opt,name=candidate,proto3" json:"candidate,omitempty"`
+	SdpMid    string `protobuf:"bytes,2,opt,name=sdp_mid,json=sdpMid,proto3" json:"sdp_mid,omitempty"`
+	// media section of the candidate without sdp_mid
+	SdpMlineIndex uint32 `protobuf:"varint,3,opt,name=sdp_mline_index,json=sdpMlineIndex,proto3" json:"sdp_mline_index,omitempty"`
+}
+
+func (x *IceCandidate) Reset() {
+	*x = IceCandidate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IceCandidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IceCandidate) ProtoMessage() {}
+
+func (x *IceCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IceCandidate.ProtoReflect.Descriptor instead.
+func (*IceCandidate) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *IceCandidate) GetCandidate() string {
+	if x != nil {
+		return x.Candidate
+	}
+	return ""
+}
+
+func (x *IceCandidate) GetSdpMid() string {
+	if x != nil {
+		return x.SdpMid
+	}
+	return ""
+}
+
+func (x *IceCandidate) GetSdpMlineIndex() uint32 {
+	if x != nil {
+		return x.SdpMlineIndex
+	}
+	return 0
+}
+
+type AddIceCandidateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string        `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Candidate *IceCandidate `protobuf:"bytes,2,opt,name=candidate,proto3" json:"candidate,omitempty"`
+}
+
+func (x *AddIceCandidateRequest) Reset() {
+	*x = AddIceCandidateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddIceCandidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddIceCandidateRequest) ProtoMessage() {}
+
+func (x *AddIceCandidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddIceCandidateRequest.ProtoReflect.Descriptor instead.
+func (*AddIceCandidateRequest) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AddIceCandidateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AddIceCandidateRequest) GetCandidate() *IceCandidate {
+	if x != nil {
+		return x.Candidate
+	}
+	return nil
+}
+
+type AddIceCandidateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AddIceCandidateResponse) Reset() {
+	*x = AddIceCandidateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddIceCandidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddIceCandidateResponse) ProtoMessage() {}
+
+func (x *AddIceCandidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddIceCandidateResponse.ProtoReflect.Descriptor instead.
+func (*AddIceCandidateResponse) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{26}
+}
+
+type WatchIceCandidatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *WatchIceCandidatesRequest) Reset() {
+	*x = WatchIceCandidatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_webrtc_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchIceCandidatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchIceCandidatesRequest) ProtoMessage() {}
+
+func (x *WatchIceCandidatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_webrtc_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchIceCandidatesRequest.ProtoReflect.Descriptor instead.
+func (*WatchIceCandidatesRequest) Descriptor() ([]byte, []int) {
+	return file_webrtc_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *WatchIceCandidatesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_webrtc_proto protoreflect.FileDescriptor
+
+var file_webrtc_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f,
+	0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x1a,
+	0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0a, 0x66,
+	0x69, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x84, 0x01, 0x0a, 0x0a, 0x49, 0x43,
+	0x45, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x72, 0x6c, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x72, 0x65, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0e, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c,
+	0x22, 0x90, 0x03, 0x0a, 0x15, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x32, 0x50, 0x56, 0x69,
+	0x64, 0x65, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x64,
+	0x70, 0x5f, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
+	0x64, 0x70, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x75, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12,
+	0x3c, 0x0a, 0x0b, 0x69, 0x63, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x49, 0x43, 0x45, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x73, 0x52, 0x0a, 0x69, 0x63, 0x65, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x34, 0x0a,
 	0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a,
 	0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46,
 	0x69, 0x6c, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e,
-	0x6e, 0x65, 0x6c, 0x22, 0x47, 0x0a, 0x16, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x32, 0x50,
+	0x6e, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x6e, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x73, 0x69, 0x6e, 0x6b, 0x12, 0x3d, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74,
+	0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x07, 0x70,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x72, 0x69, 0x63, 0x6b, 0x6c,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x74, 0x72, 0x69, 0x63, 0x6b, 0x6c, 0x65,
+	0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c,
+	0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61, 0x79,
+	0x6f, 0x75, 0x74, 0x22, 0xbd, 0x03, 0x0a, 0x12, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x6f, 0x64,
+	0x69, 0x6e, 0x67, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x66, 0x70, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x66, 0x70, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x12,
+	0x1f, 0x0a, 0x0b, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x43, 0x6f, 0x64, 0x65, 0x63,
+	0x12, 0x15, 0x0a, 0x03, 0x63, 0x72, 0x66, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52,
+	0x03, 0x63, 0x72, 0x66, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x69, 0x64, 0x65, 0x6f,
+	0x5f, 0x62, 0x69, 0x74, 0x72, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x76, 0x69, 0x64, 0x65, 0x6f, 0x42, 0x69, 0x74, 0x72, 0x61, 0x74, 0x65, 0x12, 0x23, 0x0a, 0x0d,
+	0x61, 0x75, 0x64, 0x69, 0x6f, 0x5f, 0x62, 0x69, 0x74, 0x72, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x42, 0x69, 0x74, 0x72, 0x61, 0x74,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x12,
+	0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x16,
+	0x0a, 0x06, 0x70, 0x72, 0x65, 0x73, 0x65, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x70, 0x72, 0x65, 0x73, 0x65, 0x74, 0x12, 0x25, 0x0a, 0x0b, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68,
+	0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x0b, 0x70,
+	0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a,
+	0x09, 0x73, 0x75, 0x62, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x73, 0x75, 0x62, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x5f,
+	0x63, 0x72, 0x66, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f,
+	0x75, 0x67, 0x68, 0x22, 0x47, 0x0a, 0x16, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x32, 0x50,
 	0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a,
 	0x0a, 0x73, 0x64, 0x70, 0x5f, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x09, 0x73, 0x64, 0x70, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02,
@@ -451,53 +2003,255 @@ var file_webrtc_proto_rawDesc = []byte{
 	0x53, 0x74, 0x6f, 0x70, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x71, 0x75,
 	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x02, 0x69, 0x64, 0x22, 0x16, 0x0a, 0x14, 0x53, 0x74, 0x6f, 0x70, 0x50, 0x32, 0x50, 0x56, 0x69,
-	0x64, 0x65, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x49, 0x0a, 0x1a, 0x52,
+	0x64, 0x65, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x27, 0x0a, 0x15, 0x50,
+	0x61, 0x75, 0x73, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x22, 0x18, 0x0a, 0x16, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x28,
+	0x0a, 0x16, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e,
+	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x19, 0x0a, 0x17, 0x52, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x56, 0x0a, 0x10, 0x41, 0x64, 0x64, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x1c, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x44, 0x0a, 0x11, 0x41,
+	0x64, 0x64, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2f, 0x0a, 0x06, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x2e, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x06, 0x6d, 0x61, 0x72, 0x6b, 0x65,
+	0x72, 0x22, 0x49, 0x0a, 0x1a, 0x52, 0x65, 0x6e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x65,
+	0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x1b, 0x0a, 0x09, 0x73, 0x64, 0x70, 0x5f, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x73, 0x64, 0x70, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x22, 0x3c, 0x0a, 0x1b,
+	0x52, 0x65, 0x6e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x65, 0x50, 0x32, 0x50, 0x56, 0x69,
+	0x64, 0x65, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x64, 0x70, 0x5f, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x64, 0x70, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x22, 0xbc, 0x01, 0x0a, 0x0c, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6d,
+	0x69, 0x6d, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x6d, 0x69, 0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x6f, 0x63,
+	0x6b, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x6c,
+	0x6f, 0x63, 0x6b, 0x52, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x65,
+	0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74,
+	0x73, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x6f, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6c, 0x6f, 0x73, 0x74, 0x50, 0x61, 0x63,
+	0x6b, 0x65, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x77, 0x72,
+	0x69, 0x74, 0x74, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x57, 0x72, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x22, 0xa1, 0x02, 0x0a, 0x07, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x63, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x35, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x52,
+	0x06, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x22, 0x15, 0x0a,
+	0x13, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x46, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x05,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x77, 0x65,
+	0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x23, 0x0a, 0x11,
+	0x47, 0x65, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x22, 0xc3, 0x01, 0x0a, 0x08, 0x4a, 0x6f, 0x62, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d,
+	0x70, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73, 0x65, 0x67, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4d, 0x0a, 0x03, 0x47, 0x61, 0x70, 0x12, 0x0e,
+	0x0a, 0x02, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x61, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x64, 0x61, 0x63, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65,
+	0x64, 0x61, 0x63, 0x74, 0x65, 0x64, 0x22, 0x3c, 0x0a, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x22, 0xfd, 0x02, 0x0a, 0x0c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x35, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x21, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x33, 0x0a, 0x05, 0x74, 0x72, 0x61,
+	0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74,
+	0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x1b,
+	0x0a, 0x09, 0x69, 0x63, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64,
+	0x61, 0x74, 0x61, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x2b,
+	0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x77, 0x65,
+	0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x4a, 0x6f,
+	0x62, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x12, 0x26, 0x0a, 0x03, 0x67,
+	0x61, 0x70, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74,
+	0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x47, 0x61, 0x70, 0x52, 0x03,
+	0x67, 0x61, 0x70, 0x12, 0x2f, 0x0a, 0x06, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x06, 0x6d, 0x61,
+	0x72, 0x6b, 0x65, 0x72, 0x22, 0x25, 0x0a, 0x13, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x6d, 0x0a, 0x0c, 0x49,
+	0x63, 0x65, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63,
+	0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x64, 0x70,
+	0x5f, 0x6d, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x64, 0x70, 0x4d,
+	0x69, 0x64, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x64, 0x70, 0x5f, 0x6d, 0x6c, 0x69, 0x6e, 0x65, 0x5f,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x73, 0x64, 0x70,
+	0x4d, 0x6c, 0x69, 0x6e, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x65, 0x0a, 0x16, 0x41, 0x64,
+	0x64, 0x49, 0x63, 0x65, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x3b, 0x0a, 0x09, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63,
+	0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x49, 0x63, 0x65, 0x43, 0x61, 0x6e,
+	0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x09, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x22, 0x19, 0x0a, 0x17, 0x41, 0x64, 0x64, 0x49, 0x63, 0x65, 0x43, 0x61, 0x6e, 0x64, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2b, 0x0a, 0x19,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x63, 0x65, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x2a, 0xf3, 0x01, 0x0a, 0x10, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10,
+	0x0a, 0x0c, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x10, 0x00,
+	0x12, 0x0e, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x41, 0x64, 0x64, 0x65, 0x64, 0x10, 0x01,
+	0x12, 0x13, 0x0a, 0x0f, 0x49, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x64, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x44, 0x61, 0x74, 0x61, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x4f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x10, 0x04, 0x12,
+	0x13, 0x0a, 0x0f, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x64, 0x10, 0x05, 0x12, 0x0d, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x61, 0x76, 0x65,
+	0x64, 0x10, 0x06, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x47, 0x61, 0x70, 0x10,
+	0x07, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61,
+	0x75, 0x73, 0x65, 0x64, 0x10, 0x08, 0x12, 0x14, 0x0a, 0x10, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x10, 0x09, 0x12, 0x0f, 0x0a, 0x0b,
+	0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x41, 0x64, 0x64, 0x65, 0x64, 0x10, 0x0a, 0x12, 0x10, 0x0a,
+	0x0c, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x10, 0x0b, 0x32,
+	0xa6, 0x0b, 0x0a, 0x0d, 0x57, 0x65, 0x62, 0x52, 0x54, 0x43, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x7b, 0x0a, 0x0e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x32, 0x50, 0x56, 0x69,
+	0x64, 0x65, 0x6f, 0x12, 0x26, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x32, 0x50, 0x56,
+	0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x77, 0x65,
+	0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x3a, 0x01, 0x2a, 0x22,
+	0x0d, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2f, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x12, 0x7a,
+	0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x70, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x12, 0x24,
+	0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x2e, 0x53, 0x74, 0x6f, 0x70, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x50, 0x32, 0x50, 0x56, 0x69,
+	0x64, 0x65, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x17, 0x3a, 0x01, 0x2a, 0x2a, 0x12, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2f,
+	0x76, 0x69, 0x64, 0x65, 0x6f, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x12, 0x8f, 0x01, 0x0a, 0x13, 0x52,
 	0x65, 0x6e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x65, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64,
-	0x65, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x64, 0x70,
-	0x5f, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x64,
-	0x70, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x22, 0x3c, 0x0a, 0x1b, 0x52, 0x65, 0x6e, 0x65, 0x67, 0x6f,
-	0x74, 0x69, 0x61, 0x74, 0x65, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x64, 0x70, 0x5f, 0x61, 0x6e, 0x73,
-	0x77, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x64, 0x70, 0x41, 0x6e,
-	0x73, 0x77, 0x65, 0x72, 0x32, 0x9a, 0x03, 0x0a, 0x0d, 0x57, 0x65, 0x62, 0x52, 0x54, 0x43, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x7b, 0x0a, 0x0e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
-	0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x12, 0x26, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74,
-	0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x27, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x65, 0x72, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65,
-	0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02,
-	0x12, 0x3a, 0x01, 0x2a, 0x22, 0x0d, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2f, 0x76, 0x69,
-	0x64, 0x65, 0x6f, 0x12, 0x7a, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x70, 0x50, 0x32, 0x50, 0x56, 0x69,
-	0x64, 0x65, 0x6f, 0x12, 0x24, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64,
-	0x65, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x77, 0x65, 0x62, 0x72,
-	0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x6f, 0x70,
-	0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x3a, 0x01, 0x2a, 0x2a, 0x12, 0x2f, 0x77, 0x65,
-	0x62, 0x72, 0x74, 0x63, 0x2f, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x12,
-	0x8f, 0x01, 0x0a, 0x13, 0x52, 0x65, 0x6e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x65, 0x50,
-	0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x12, 0x2b, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63,
-	0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x6e, 0x65, 0x67, 0x6f,
-	0x74, 0x69, 0x61, 0x74, 0x65, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65,
-	0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x6e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61,
-	0x74, 0x65, 0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x3a, 0x01, 0x2a, 0x1a, 0x12, 0x2f,
-	0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2f, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x2f, 0x7b, 0x69, 0x64,
-	0x7d, 0x42, 0xa5, 0x01, 0x0a, 0x13, 0x63, 0x6f, 0x6d, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63,
-	0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x0b, 0x57, 0x65, 0x62, 0x72, 0x74,
-	0x63, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x73, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72,
-	0x64, 0x65, 0x72, 0xa2, 0x02, 0x03, 0x57, 0x58, 0x58, 0xaa, 0x02, 0x0e, 0x57, 0x65, 0x62, 0x72,
-	0x74, 0x63, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0xca, 0x02, 0x0e, 0x57, 0x65, 0x62,
-	0x72, 0x74, 0x63, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0xe2, 0x02, 0x1a, 0x57, 0x65,
-	0x62, 0x72, 0x74, 0x63, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5c, 0x47, 0x50, 0x42,
-	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x0e, 0x57, 0x65, 0x62, 0x72, 0x74,
-	0x63, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x65, 0x6f, 0x12, 0x2b, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x6e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x65,
+	0x50, 0x32, 0x50, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2c, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x2e, 0x52, 0x65, 0x6e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x65, 0x50, 0x32, 0x50,
+	0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x17, 0x3a, 0x01, 0x2a, 0x1a, 0x12, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74,
+	0x63, 0x2f, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x12, 0x86, 0x01, 0x0a,
+	0x0e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x12,
+	0x26, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x2e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63,
+	0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x23, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1d, 0x3a, 0x01, 0x2a, 0x22, 0x18, 0x2f, 0x77, 0x65,
+	0x62, 0x72, 0x74, 0x63, 0x2f, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x2f,
+	0x70, 0x61, 0x75, 0x73, 0x65, 0x12, 0x8a, 0x01, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x27, 0x2e, 0x77, 0x65, 0x62, 0x72,
+	0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x28, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x24, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x1e, 0x3a, 0x01, 0x2a, 0x22, 0x19, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63,
+	0x2f, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x2f, 0x72, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x12, 0x79, 0x0a, 0x09, 0x41, 0x64, 0x64, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x12,
+	0x21, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x2e, 0x41, 0x64, 0x64, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x2e, 0x41, 0x64, 0x64, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x3a, 0x01,
+	0x2a, 0x22, 0x1a, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2f, 0x76, 0x69, 0x64, 0x65, 0x6f,
+	0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x2f, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x75, 0x0a,
+	0x0c, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x24, 0x2e,
+	0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x12, 0x12, 0x10, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2f, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x69, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x22, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x12, 0x15, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74,
+	0x63, 0x2f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x12,
+	0x7b, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x24, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x22, 0x24, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x12, 0x1c, 0x2f, 0x77,
+	0x65, 0x62, 0x72, 0x74, 0x63, 0x2f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x7b,
+	0x69, 0x64, 0x7d, 0x2f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x30, 0x01, 0x12, 0x8e, 0x01, 0x0a,
+	0x0f, 0x41, 0x64, 0x64, 0x49, 0x63, 0x65, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x12, 0x27, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x63, 0x65, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x77, 0x65, 0x62, 0x72,
+	0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x41, 0x64, 0x64, 0x49,
+	0x63, 0x65, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x28, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x22, 0x3a, 0x01, 0x2a, 0x22, 0x1d,
+	0x2f, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2f, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x2f, 0x7b, 0x69,
+	0x64, 0x7d, 0x2f, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x88, 0x01,
+	0x0a, 0x12, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x63, 0x65, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x73, 0x12, 0x2a, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x63, 0x65, 0x43,
+	0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1d, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x2e, 0x49, 0x63, 0x65, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x22,
+	0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x12, 0x1d, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63,
+	0x2f, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x2f, 0x63, 0x61, 0x6e, 0x64,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x30, 0x01, 0x42, 0xa5, 0x01, 0x0a, 0x13, 0x63, 0x6f, 0x6d,
+	0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x42, 0x0b, 0x57, 0x65, 0x62, 0x72, 0x74, 0x63, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a,
+	0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x65, 0x62, 0x69,
+	0x74, 0x65, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74,
+	0x63, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72, 0xa2, 0x02, 0x03, 0x57, 0x58, 0x58,
+	0xaa, 0x02, 0x0e, 0x57, 0x65, 0x62, 0x72, 0x74, 0x63, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0xca, 0x02, 0x0e, 0x57, 0x65, 0x62, 0x72, 0x74, 0x63, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0xe2, 0x02, 0x1a, 0x57, 0x65, 0x62, 0x72, 0x74, 0x63, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea,
+	0x02, 0x0e, 0x57, 0x65, 0x62, 0x72, 0x74, 0x63, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -512,31 +2266,80 @@ func file_webrtc_proto_rawDescGZIP() []byte {
 	return file_webrtc_proto_rawDescData
 }
 
-var file_webrtc_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_webrtc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_webrtc_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_webrtc_proto_goTypes = []interface{}{
-	(*ICEServers)(nil),                  // 0: webrtc_recorder.ICEServers
-	(*UploadP2PVideoRequest)(nil),       // 1: webrtc_recorder.UploadP2PVideoRequest
-	(*UploadP2PVideoResponse)(nil),      // 2: webrtc_recorder.UploadP2PVideoResponse
-	(*StopP2PVideoRequest)(nil),         // 3: webrtc_recorder.StopP2PVideoRequest
-	(*StopP2PVideoResponse)(nil),        // 4: webrtc_recorder.StopP2PVideoResponse
-	(*RenegotiateP2PVideoRequest)(nil),  // 5: webrtc_recorder.RenegotiateP2PVideoRequest
-	(*RenegotiateP2PVideoResponse)(nil), // 6: webrtc_recorder.RenegotiateP2PVideoResponse
-	(storage.UploadFileChannel)(0),      // 7: storage.UploadFileChannel
+	(SessionEventType)(0),               // 0: webrtc_recorder.SessionEventType
+	(*ICEServers)(nil),                  // 1: webrtc_recorder.ICEServers
+	(*UploadP2PVideoRequest)(nil),       // 2: webrtc_recorder.UploadP2PVideoRequest
+	(*TranscodingProfile)(nil),          // 3: webrtc_recorder.TranscodingProfile
+	(*UploadP2PVideoResponse)(nil),      // 4: webrtc_recorder.UploadP2PVideoResponse
+	(*StopP2PVideoRequest)(nil),         // 5: webrtc_recorder.StopP2PVideoRequest
+	(*StopP2PVideoResponse)(nil),        // 6: webrtc_recorder.StopP2PVideoResponse
+	(*PauseRecordingRequest)(nil),       // 7: webrtc_recorder.PauseRecordingRequest
+	(*PauseRecordingResponse)(nil),      // 8: webrtc_recorder.PauseRecordingResponse
+	(*ResumeRecordingRequest)(nil),      // 9: webrtc_recorder.ResumeRecordingRequest
+	(*ResumeRecordingResponse)(nil),     // 10: webrtc_recorder.ResumeRecordingResponse
+	(*AddMarkerRequest)(nil),            // 11: webrtc_recorder.AddMarkerRequest
+	(*AddMarkerResponse)(nil),           // 12: webrtc_recorder.AddMarkerResponse
+	(*RenegotiateP2PVideoRequest)(nil),  // 13: webrtc_recorder.RenegotiateP2PVideoRequest
+	(*RenegotiateP2PVideoResponse)(nil), // 14: webrtc_recorder.RenegotiateP2PVideoResponse
+	(*SessionTrack)(nil),                // 15: webrtc_recorder.SessionTrack
+	(*Session)(nil),                     // 16: webrtc_recorder.Session
+	(*ListSessionsRequest)(nil),         // 17: webrtc_recorder.ListSessionsRequest
+	(*ListSessionsResponse)(nil),        // 18: webrtc_recorder.ListSessionsResponse
+	(*GetSessionRequest)(nil),           // 19: webrtc_recorder.GetSessionRequest
+	(*JobEvent)(nil),                    // 20: webrtc_recorder.JobEvent
+	(*Gap)(nil),                         // 21: webrtc_recorder.Gap
+	(*Marker)(nil),                      // 22: webrtc_recorder.Marker
+	(*SessionEvent)(nil),                // 23: webrtc_recorder.SessionEvent
+	(*WatchSessionRequest)(nil),         // 24: webrtc_recorder.WatchSessionRequest
+	(*IceCandidate)(nil),                // 25: webrtc_recorder.IceCandidate
+	(*AddIceCandidateRequest)(nil),      // 26: webrtc_recorder.AddIceCandidateRequest
+	(*AddIceCandidateResponse)(nil),     // 27: webrtc_recorder.AddIceCandidateResponse
+	(*WatchIceCandidatesRequest)(nil),   // 28: webrtc_recorder.WatchIceCandidatesRequest
+	(storage.UploadFileChannel)(0),      // 29: storage.UploadFileChannel
 }
 var file_webrtc_proto_depIdxs = []int32{
-	0, // 0: webrtc_recorder.UploadP2PVideoRequest.ice_servers:type_name -> webrtc_recorder.ICEServers
-	7, // 1: webrtc_recorder.UploadP2PVideoRequest.channel:type_name -> storage.UploadFileChannel
-	1, // 2: webrtc_recorder.WebRTCService.UploadP2PVideo:input_type -> webrtc_recorder.UploadP2PVideoRequest
-	3, // 3: webrtc_recorder.WebRTCService.StopP2PVideo:input_type -> webrtc_recorder.StopP2PVideoRequest
-	5, // 4: webrtc_recorder.WebRTCService.RenegotiateP2PVideo:input_type -> webrtc_recorder.RenegotiateP2PVideoRequest
-	2, // 5: webrtc_recorder.WebRTCService.UploadP2PVideo:output_type -> webrtc_recorder.UploadP2PVideoResponse
-	4, // 6: webrtc_recorder.WebRTCService.StopP2PVideo:output_type -> webrtc_recorder.StopP2PVideoResponse
-	6, // 7: webrtc_recorder.WebRTCService.RenegotiateP2PVideo:output_type -> webrtc_recorder.RenegotiateP2PVideoResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	1,  // 0: webrtc_recorder.UploadP2PVideoRequest.ice_servers:type_name -> webrtc_recorder.ICEServers
+	29, // 1: webrtc_recorder.UploadP2PVideoRequest.channel:type_name -> storage.UploadFileChannel
+	3,  // 2: webrtc_recorder.UploadP2PVideoRequest.profile:type_name -> webrtc_recorder.TranscodingProfile
+	22, // 3: webrtc_recorder.AddMarkerResponse.marker:type_name -> webrtc_recorder.Marker
+	15, // 4: webrtc_recorder.Session.tracks:type_name -> webrtc_recorder.SessionTrack
+	16, // 5: webrtc_recorder.ListSessionsResponse.items:type_name -> webrtc_recorder.Session
+	0,  // 6: webrtc_recorder.SessionEvent.type:type_name -> webrtc_recorder.SessionEventType
+	15, // 7: webrtc_recorder.SessionEvent.track:type_name -> webrtc_recorder.SessionTrack
+	20, // 8: webrtc_recorder.SessionEvent.job:type_name -> webrtc_recorder.JobEvent
+	21, // 9: webrtc_recorder.SessionEvent.gap:type_name -> webrtc_recorder.Gap
+	22, // 10: webrtc_recorder.SessionEvent.marker:type_name -> webrtc_recorder.Marker
+	25, // 11: webrtc_recorder.AddIceCandidateRequest.candidate:type_name -> webrtc_recorder.IceCandidate
+	2,  // 12: webrtc_recorder.WebRTCService.UploadP2PVideo:input_type -> webrtc_recorder.UploadP2PVideoRequest
+	5,  // 13: webrtc_recorder.WebRTCService.StopP2PVideo:input_type -> webrtc_recorder.StopP2PVideoRequest
+	13, // 14: webrtc_recorder.WebRTCService.RenegotiateP2PVideo:input_type -> webrtc_recorder.RenegotiateP2PVideoRequest
+	7,  // 15: webrtc_recorder.WebRTCService.PauseRecording:input_type -> webrtc_recorder.PauseRecordingRequest
+	9,  // 16: webrtc_recorder.WebRTCService.ResumeRecording:input_type -> webrtc_recorder.ResumeRecordingRequest
+	11, // 17: webrtc_recorder.WebRTCService.AddMarker:input_type -> webrtc_recorder.AddMarkerRequest
+	17, // 18: webrtc_recorder.WebRTCService.ListSessions:input_type -> webrtc_recorder.ListSessionsRequest
+	19, // 19: webrtc_recorder.WebRTCService.GetSession:input_type -> webrtc_recorder.GetSessionRequest
+	24, // 20: webrtc_recorder.WebRTCService.WatchSession:input_type -> webrtc_recorder.WatchSessionRequest
+	26, // 21: webrtc_recorder.WebRTCService.AddIceCandidate:input_type -> webrtc_recorder.AddIceCandidateRequest
+	28, // 22: webrtc_recorder.WebRTCService.WatchIceCandidates:input_type -> webrtc_recorder.WatchIceCandidatesRequest
+	4,  // 23: webrtc_recorder.WebRTCService.UploadP2PVideo:output_type -> webrtc_recorder.UploadP2PVideoResponse
+	6,  // 24: webrtc_recorder.WebRTCService.StopP2PVideo:output_type -> webrtc_recorder.StopP2PVideoResponse
+	14, // 25: webrtc_recorder.WebRTCService.RenegotiateP2PVideo:output_type -> webrtc_recorder.RenegotiateP2PVideoResponse
+	8,  // 26: webrtc_recorder.WebRTCService.PauseRecording:output_type -> webrtc_recorder.PauseRecordingResponse
+	10, // 27: webrtc_recorder.WebRTCService.ResumeRecording:output_type -> webrtc_recorder.ResumeRecordingResponse
+	12, // 28: webrtc_recorder.WebRTCService.AddMarker:output_type -> webrtc_recorder.AddMarkerResponse
+	18, // 29: webrtc_recorder.WebRTCService.ListSessions:output_type -> webrtc_recorder.ListSessionsResponse
+	16, // 30: webrtc_recorder.WebRTCService.GetSession:output_type -> webrtc_recorder.Session
+	23, // 31: webrtc_recorder.WebRTCService.WatchSession:output_type -> webrtc_recorder.SessionEvent
+	27, // 32: webrtc_recorder.WebRTCService.AddIceCandidate:output_type -> webrtc_recorder.AddIceCandidateResponse
+	25, // 33: webrtc_recorder.WebRTCService.WatchIceCandidates:output_type -> webrtc_recorder.IceCandidate
+	23, // [23:34] is the sub-list for method output_type
+	12, // [12:23] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_webrtc_proto_init() }
@@ -570,7 +2373,7 @@ func file_webrtc_proto_init() {
 			}
 		}
 		file_webrtc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadP2PVideoResponse); i {
+			switch v := v.(*TranscodingProfile); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -582,7 +2385,7 @@ func file_webrtc_proto_init() {
 			}
 		}
 		file_webrtc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StopP2PVideoRequest); i {
+			switch v := v.(*UploadP2PVideoResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -594,7 +2397,7 @@ func file_webrtc_proto_init() {
 			}
 		}
 		file_webrtc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StopP2PVideoResponse); i {
+			switch v := v.(*StopP2PVideoRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -606,7 +2409,7 @@ func file_webrtc_proto_init() {
 			}
 		}
 		file_webrtc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RenegotiateP2PVideoRequest); i {
+			switch v := v.(*StopP2PVideoResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -618,6 +2421,90 @@ func file_webrtc_proto_init() {
 			}
 		}
 		file_webrtc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PauseRecordingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PauseRecordingResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResumeRecordingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResumeRecordingResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddMarkerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddMarkerResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenegotiateP2PVideoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RenegotiateP2PVideoResponse); i {
 			case 0:
 				return &v.state
@@ -629,19 +2516,189 @@ func file_webrtc_proto_init() {
 				return nil
 			}
 		}
+		file_webrtc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionTrack); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Session); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSessionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSessionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Gap); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Marker); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IceCandidate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddIceCandidateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddIceCandidateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_webrtc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchIceCandidatesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
+	file_webrtc_proto_msgTypes[2].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_webrtc_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      1,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_webrtc_proto_goTypes,
 		DependencyIndexes: file_webrtc_proto_depIdxs,
+		EnumInfos:         file_webrtc_proto_enumTypes,
 		MessageInfos:      file_webrtc_proto_msgTypes,
 	}.Build()
 	File_webrtc_proto = out.File
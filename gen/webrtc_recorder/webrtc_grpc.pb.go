@@ -22,6 +22,14 @@ const (
 	WebRTCService_UploadP2PVideo_FullMethodName      = "/webrtc_recorder.WebRTCService/UploadP2PVideo"
 	WebRTCService_StopP2PVideo_FullMethodName        = "/webrtc_recorder.WebRTCService/StopP2PVideo"
 	WebRTCService_RenegotiateP2PVideo_FullMethodName = "/webrtc_recorder.WebRTCService/RenegotiateP2PVideo"
+	WebRTCService_PauseRecording_FullMethodName      = "/webrtc_recorder.WebRTCService/PauseRecording"
+	WebRTCService_ResumeRecording_FullMethodName     = "/webrtc_recorder.WebRTCService/ResumeRecording"
+	WebRTCService_AddMarker_FullMethodName           = "/webrtc_recorder.WebRTCService/AddMarker"
+	WebRTCService_ListSessions_FullMethodName        = "/webrtc_recorder.WebRTCService/ListSessions"
+	WebRTCService_GetSession_FullMethodName          = "/webrtc_recorder.WebRTCService/GetSession"
+	WebRTCService_WatchSession_FullMethodName        = "/webrtc_recorder.WebRTCService/WatchSession"
+	WebRTCService_AddIceCandidate_FullMethodName     = "/webrtc_recorder.WebRTCService/AddIceCandidate"
+	WebRTCService_WatchIceCandidates_FullMethodName  = "/webrtc_recorder.WebRTCService/WatchIceCandidates"
 )
 
 // WebRTCServiceClient is the client API for WebRTCService service.
@@ -31,6 +39,20 @@ type WebRTCServiceClient interface {
 	UploadP2PVideo(ctx context.Context, in *UploadP2PVideoRequest, opts ...grpc.CallOption) (*UploadP2PVideoResponse, error)
 	StopP2PVideo(ctx context.Context, in *StopP2PVideoRequest, opts ...grpc.CallOption) (*StopP2PVideoResponse, error)
 	RenegotiateP2PVideo(ctx context.Context, in *RenegotiateP2PVideoRequest, opts ...grpc.CallOption) (*RenegotiateP2PVideoResponse, error)
+	// stops writing the samples while the peer connection stays alive, the paused interval is
+	// replaced by the placeholder frame and silence in the recording
+	PauseRecording(ctx context.Context, in *PauseRecordingRequest, opts ...grpc.CallOption) (*PauseRecordingResponse, error)
+	ResumeRecording(ctx context.Context, in *ResumeRecordingRequest, opts ...grpc.CallOption) (*ResumeRecordingResponse, error)
+	// bookmarks the moment of the recording, it is written as the chapter of the file
+	AddMarker(ctx context.Context, in *AddMarkerRequest, opts ...grpc.CallOption) (*AddMarkerResponse, error)
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*Session, error)
+	// Streams the session events until its recording is saved or abandoned
+	WatchSession(ctx context.Context, in *WatchSessionRequest, opts ...grpc.CallOption) (WebRTCService_WatchSessionClient, error)
+	// adds the remote candidate of the trickle ICE session
+	AddIceCandidate(ctx context.Context, in *AddIceCandidateRequest, opts ...grpc.CallOption) (*AddIceCandidateResponse, error)
+	// Streams the local candidates of the trickle ICE session from the first one until the session is closed
+	WatchIceCandidates(ctx context.Context, in *WatchIceCandidatesRequest, opts ...grpc.CallOption) (WebRTCService_WatchIceCandidatesClient, error)
 }
 
 type webRTCServiceClient struct {
@@ -68,6 +90,124 @@ func (c *webRTCServiceClient) RenegotiateP2PVideo(ctx context.Context, in *Reneg
 	return out, nil
 }
 
+func (c *webRTCServiceClient) PauseRecording(ctx context.Context, in *PauseRecordingRequest, opts ...grpc.CallOption) (*PauseRecordingResponse, error) {
+	out := new(PauseRecordingResponse)
+	err := c.cc.Invoke(ctx, WebRTCService_PauseRecording_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webRTCServiceClient) ResumeRecording(ctx context.Context, in *ResumeRecordingRequest, opts ...grpc.CallOption) (*ResumeRecordingResponse, error) {
+	out := new(ResumeRecordingResponse)
+	err := c.cc.Invoke(ctx, WebRTCService_ResumeRecording_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webRTCServiceClient) AddMarker(ctx context.Context, in *AddMarkerRequest, opts ...grpc.CallOption) (*AddMarkerResponse, error) {
+	out := new(AddMarkerResponse)
+	err := c.cc.Invoke(ctx, WebRTCService_AddMarker_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webRTCServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, WebRTCService_ListSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webRTCServiceClient) GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := c.cc.Invoke(ctx, WebRTCService_GetSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webRTCServiceClient) WatchSession(ctx context.Context, in *WatchSessionRequest, opts ...grpc.CallOption) (WebRTCService_WatchSessionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WebRTCService_ServiceDesc.Streams[0], WebRTCService_WatchSession_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &webRTCServiceWatchSessionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WebRTCService_WatchSessionClient interface {
+	Recv() (*SessionEvent, error)
+	grpc.ClientStream
+}
+
+type webRTCServiceWatchSessionClient struct {
+	grpc.ClientStream
+}
+
+func (x *webRTCServiceWatchSessionClient) Recv() (*SessionEvent, error) {
+	m := new(SessionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *webRTCServiceClient) AddIceCandidate(ctx context.Context, in *AddIceCandidateRequest, opts ...grpc.CallOption) (*AddIceCandidateResponse, error) {
+	out := new(AddIceCandidateResponse)
+	err := c.cc.Invoke(ctx, WebRTCService_AddIceCandidate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webRTCServiceClient) WatchIceCandidates(ctx context.Context, in *WatchIceCandidatesRequest, opts ...grpc.CallOption) (WebRTCService_WatchIceCandidatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WebRTCService_ServiceDesc.Streams[1], WebRTCService_WatchIceCandidates_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &webRTCServiceWatchIceCandidatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WebRTCService_WatchIceCandidatesClient interface {
+	Recv() (*IceCandidate, error)
+	grpc.ClientStream
+}
+
+type webRTCServiceWatchIceCandidatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *webRTCServiceWatchIceCandidatesClient) Recv() (*IceCandidate, error) {
+	m := new(IceCandidate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // WebRTCServiceServer is the server API for WebRTCService service.
 // All implementations must embed UnimplementedWebRTCServiceServer
 // for forward compatibility
@@ -75,6 +215,20 @@ type WebRTCServiceServer interface {
 	UploadP2PVideo(context.Context, *UploadP2PVideoRequest) (*UploadP2PVideoResponse, error)
 	StopP2PVideo(context.Context, *StopP2PVideoRequest) (*StopP2PVideoResponse, error)
 	RenegotiateP2PVideo(context.Context, *RenegotiateP2PVideoRequest) (*RenegotiateP2PVideoResponse, error)
+	// stops writing the samples while the peer connection stays alive, the paused interval is
+	// replaced by the placeholder frame and silence in the recording
+	PauseRecording(context.Context, *PauseRecordingRequest) (*PauseRecordingResponse, error)
+	ResumeRecording(context.Context, *ResumeRecordingRequest) (*ResumeRecordingResponse, error)
+	// bookmarks the moment of the recording, it is written as the chapter of the file
+	AddMarker(context.Context, *AddMarkerRequest) (*AddMarkerResponse, error)
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	GetSession(context.Context, *GetSessionRequest) (*Session, error)
+	// Streams the session events until its recording is saved or abandoned
+	WatchSession(*WatchSessionRequest, WebRTCService_WatchSessionServer) error
+	// adds the remote candidate of the trickle ICE session
+	AddIceCandidate(context.Context, *AddIceCandidateRequest) (*AddIceCandidateResponse, error)
+	// Streams the local candidates of the trickle ICE session from the first one until the session is closed
+	WatchIceCandidates(*WatchIceCandidatesRequest, WebRTCService_WatchIceCandidatesServer) error
 	mustEmbedUnimplementedWebRTCServiceServer()
 }
 
@@ -91,6 +245,30 @@ func (UnimplementedWebRTCServiceServer) StopP2PVideo(context.Context, *StopP2PVi
 func (UnimplementedWebRTCServiceServer) RenegotiateP2PVideo(context.Context, *RenegotiateP2PVideoRequest) (*RenegotiateP2PVideoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RenegotiateP2PVideo not implemented")
 }
+func (UnimplementedWebRTCServiceServer) PauseRecording(context.Context, *PauseRecordingRequest) (*PauseRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseRecording not implemented")
+}
+func (UnimplementedWebRTCServiceServer) ResumeRecording(context.Context, *ResumeRecordingRequest) (*ResumeRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeRecording not implemented")
+}
+func (UnimplementedWebRTCServiceServer) AddMarker(context.Context, *AddMarkerRequest) (*AddMarkerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddMarker not implemented")
+}
+func (UnimplementedWebRTCServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedWebRTCServiceServer) GetSession(context.Context, *GetSessionRequest) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
+}
+func (UnimplementedWebRTCServiceServer) WatchSession(*WatchSessionRequest, WebRTCService_WatchSessionServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSession not implemented")
+}
+func (UnimplementedWebRTCServiceServer) AddIceCandidate(context.Context, *AddIceCandidateRequest) (*AddIceCandidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddIceCandidate not implemented")
+}
+func (UnimplementedWebRTCServiceServer) WatchIceCandidates(*WatchIceCandidatesRequest, WebRTCService_WatchIceCandidatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchIceCandidates not implemented")
+}
 func (UnimplementedWebRTCServiceServer) mustEmbedUnimplementedWebRTCServiceServer() {}
 
 // UnsafeWebRTCServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -158,6 +336,156 @@ func _WebRTCService_RenegotiateP2PVideo_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WebRTCService_PauseRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebRTCServiceServer).PauseRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebRTCService_PauseRecording_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebRTCServiceServer).PauseRecording(ctx, req.(*PauseRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebRTCService_ResumeRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebRTCServiceServer).ResumeRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebRTCService_ResumeRecording_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebRTCServiceServer).ResumeRecording(ctx, req.(*ResumeRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebRTCService_AddMarker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddMarkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebRTCServiceServer).AddMarker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebRTCService_AddMarker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebRTCServiceServer).AddMarker(ctx, req.(*AddMarkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebRTCService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebRTCServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebRTCService_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebRTCServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebRTCService_GetSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebRTCServiceServer).GetSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebRTCService_GetSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebRTCServiceServer).GetSession(ctx, req.(*GetSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebRTCService_WatchSession_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSessionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WebRTCServiceServer).WatchSession(m, &webRTCServiceWatchSessionServer{stream})
+}
+
+type WebRTCService_WatchSessionServer interface {
+	Send(*SessionEvent) error
+	grpc.ServerStream
+}
+
+type webRTCServiceWatchSessionServer struct {
+	grpc.ServerStream
+}
+
+func (x *webRTCServiceWatchSessionServer) Send(m *SessionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WebRTCService_AddIceCandidate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddIceCandidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebRTCServiceServer).AddIceCandidate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebRTCService_AddIceCandidate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebRTCServiceServer).AddIceCandidate(ctx, req.(*AddIceCandidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebRTCService_WatchIceCandidates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchIceCandidatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WebRTCServiceServer).WatchIceCandidates(m, &webRTCServiceWatchIceCandidatesServer{stream})
+}
+
+type WebRTCService_WatchIceCandidatesServer interface {
+	Send(*IceCandidate) error
+	grpc.ServerStream
+}
+
+type webRTCServiceWatchIceCandidatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *webRTCServiceWatchIceCandidatesServer) Send(m *IceCandidate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // WebRTCService_ServiceDesc is the grpc.ServiceDesc for WebRTCService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -177,7 +505,42 @@ var WebRTCService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RenegotiateP2PVideo",
 			Handler:    _WebRTCService_RenegotiateP2PVideo_Handler,
 		},
+		{
+			MethodName: "PauseRecording",
+			Handler:    _WebRTCService_PauseRecording_Handler,
+		},
+		{
+			MethodName: "ResumeRecording",
+			Handler:    _WebRTCService_ResumeRecording_Handler,
+		},
+		{
+			MethodName: "AddMarker",
+			Handler:    _WebRTCService_AddMarker_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _WebRTCService_ListSessions_Handler,
+		},
+		{
+			MethodName: "GetSession",
+			Handler:    _WebRTCService_GetSession_Handler,
+		},
+		{
+			MethodName: "AddIceCandidate",
+			Handler:    _WebRTCService_AddIceCandidate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchSession",
+			Handler:       _WebRTCService_WatchSession_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchIceCandidates",
+			Handler:       _WebRTCService_WatchIceCandidates_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "webrtc.proto",
 }
@@ -29,6 +29,10 @@ const (
 	PermissionResetActiveAttempts   = "reset_active_attempts"
 )
 
+const (
+	ScopeRecordFile = "record_file"
+)
+
 func (p PermissionAccess) Value() uint32 {
 	return [...]uint32{8, 4, 2, 1}[p]
 }
@@ -41,6 +45,21 @@ func (s *Session) Domain(_ int64) int64 {
 	return s.DomainID
 }
 
+func (s SessionPermission) Can(acc PermissionAccess) bool {
+	switch acc {
+	case PERMISSION_ACCESS_CREATE:
+		return s.CanCreate()
+	case PERMISSION_ACCESS_READ:
+		return s.CanRead()
+	case PERMISSION_ACCESS_UPDATE:
+		return s.CanUpdate()
+	case PERMISSION_ACCESS_DELETE:
+		return s.CanDelete()
+	default:
+		return false
+	}
+}
+
 func (s SessionPermission) CanCreate() bool {
 	if s.Obac || s.rbac {
 		return s.Access&PERMISSION_ACCESS_CREATE.Value() == PERMISSION_ACCESS_CREATE.Value()
@@ -9,3 +9,8 @@ type Agent interface {
 	PassTTL(checkID, note string) error
 	FailTTL(checkID, note string) error
 }
+
+//go:generate mockery --name Health --output mocks --outpkg mocks --case underscore
+type Health interface {
+	Service(service, tag string, passingOnly bool, q *api.QueryOptions) ([]*api.ServiceEntry, *api.QueryMeta, error)
+}
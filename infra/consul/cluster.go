@@ -64,6 +64,11 @@ func (c *Cluster) Start(serviceInstanceID, host string, port int) error {
 	return nil
 }
 
+// Instances returns the ids of the healthy instances of the cluster.
+func (c *Cluster) Instances() ([]string, error) {
+	return c.discovery.Instances(c.name)
+}
+
 func (c *Cluster) Stop() {
 	c.discovery.Shutdown()
 }
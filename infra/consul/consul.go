@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/consul/api"
@@ -16,6 +17,7 @@ type CheckFunction func() error
 type Consul struct {
 	id                string
 	agent             Agent
+	health            Health
 	stop              chan struct{}
 	check             CheckFunction
 	checkID           string
@@ -57,6 +59,7 @@ func NewConsul(id, consulAgentAddr string, log *wlog.Logger, check CheckFunction
 		id:      id,
 		log:     log,
 		agent:   cli.Agent(),
+		health:  cli.Health(),
 		stop:    make(chan struct{}),
 		check:   check,
 		checkID: fmt.Sprintf("service:%s", id), // CheckID завжди має префікс "service:"
@@ -160,6 +163,21 @@ func (c *Consul) handleTTLUpdateError(err error) {
 	}
 }
 
+// Instances returns the ids of the healthy instances of the service.
+func (c *Consul) Instances(name string) ([]string, error) {
+	entries, _, err := c.health.Service(name, "", true, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instances of service %s from Consul: %w", name, err)
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, strings.TrimPrefix(e.Service.ID, name+"-"))
+	}
+
+	return ids, nil
+}
+
 func (c *Consul) IsReady() bool {
 	return c.ready
 }
@@ -183,3 +183,41 @@ func TestConsul_handleTTLUpdateError(t *testing.T) {
 		mockAgent.AssertNotCalled(t, "ServiceRegister", mock.Anything)
 	})
 }
+
+func TestConsul_Instances(t *testing.T) {
+	t.Run("Returns instance ids of healthy services", func(t *testing.T) {
+		// Arrange
+		mockHealth := new(mocks.Health)
+		consul := newTestConsul(new(mocks.Agent), nil)
+		consul.health = mockHealth
+
+		mockHealth.On("Service", "test-service", "", true, (*api.QueryOptions)(nil)).Return([]*api.ServiceEntry{
+			{Service: &api.AgentService{ID: "test-service-1"}},
+			{Service: &api.AgentService{ID: "test-service-node-b"}},
+		}, nil, nil).Once()
+
+		// Act
+		ids, err := consul.Instances("test-service")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "node-b"}, ids)
+		mockHealth.AssertExpectations(t)
+	})
+
+	t.Run("Returns error when Consul is unavailable", func(t *testing.T) {
+		// Arrange
+		mockHealth := new(mocks.Health)
+		consul := newTestConsul(new(mocks.Agent), nil)
+		consul.health = mockHealth
+
+		mockHealth.On("Service", "test-service", "", true, (*api.QueryOptions)(nil)).Return(nil, nil, errors.New("unavailable")).Once()
+
+		// Act
+		_, err := consul.Instances("test-service")
+
+		// Assert
+		require.Error(t, err)
+		mockHealth.AssertExpectations(t)
+	})
+}
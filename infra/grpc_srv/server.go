@@ -23,7 +23,10 @@ const RequestContextName = "grpc_ctx"
 
 type RequestContextSessionKey struct{}
 
-var ErrUnauthenticated = status.Error(codes.Unauthenticated, "Unauthenticated")
+var (
+	ErrUnauthenticated = status.Error(codes.Unauthenticated, "Unauthenticated")
+	ErrForbidden       = status.Error(codes.PermissionDenied, "Forbidden")
+)
 
 type Server struct {
 	*grpc.Server
@@ -41,6 +44,7 @@ func New(addr string, log *wlog.Logger, am auth.Manager) (*Server, error) {
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(),
 		grpc.UnaryInterceptor(unaryInterceptor(am, log)),
+		grpc.StreamInterceptor(streamInterceptor(am, log)),
 	)
 
 	l, err := net.Listen("tcp", addr)
@@ -163,6 +167,44 @@ func unaryInterceptor(am auth.Manager, log *wlog.Logger) grpc.UnaryServerInterce
 	}
 }
 
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func streamInterceptor(am auth.Manager, log *wlog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		ctx := ss.Context()
+
+		_, session, err := getSessionFromCtx(am, ctx)
+		if err != nil {
+			return err
+		}
+
+		err = handler(srv, &serverStream{
+			ServerStream: ss,
+			ctx:          context.WithValue(ctx, RequestContextSessionKey{}, session),
+		})
+
+		l := log.With(wlog.String("method", info.FullMethod))
+
+		if err != nil {
+			l.Error(err.Error(), wlog.Float64("duration_ms", float64(time.Since(start).Microseconds())/float64(1000)))
+		} else {
+			l.Debug(fmt.Sprintf("[OK] %s", info.FullMethod), wlog.Float64("duration_ms", float64(time.Since(start).Microseconds())/float64(1000)))
+		}
+
+		return err
+	}
+}
+
 func getSessionFromCtx(am auth.Manager, ctx context.Context) (metadata.MD, *auth.Session, error) {
 	var (
 		session *auth.Session
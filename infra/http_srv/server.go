@@ -0,0 +1,162 @@
+package http_srv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/infra/auth"
+	"github.com/webitel/webrtc_recorder/infra/grpc_client"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+type RequestContextSessionKey struct{}
+
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Server is the HTTP server of the ingest endpoints, every request except the CORS preflight
+// is authenticated with the bearer token of the auth manager.
+type Server struct {
+	*http.ServeMux
+
+	Addr     string
+	log      *wlog.Logger
+	auth     auth.Manager
+	srv      *http.Server
+	listener net.Listener
+}
+
+// New provides a new HTTP server.
+func New(addr string, log *wlog.Logger, am auth.Manager) (*Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		ServeMux: http.NewServeMux(),
+		Addr:     l.Addr().String(),
+		log:      log,
+		auth:     am,
+		listener: l,
+	}
+
+	s.srv = &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s, nil
+}
+
+func (s *Server) Listen() error {
+	err := s.srv.Serve(s.listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *Server) Shutdown() error {
+	s.log.Debug("receive shutdown http")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return s.srv.Shutdown(ctx)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	// the browsers publish from the other origins
+	h := w.Header()
+	h.Set("Access-Control-Allow-Origin", "*")
+	h.Set("Access-Control-Expose-Headers", "Location, ETag, Link")
+
+	if r.Method == http.MethodOptions {
+		h.Set("Access-Control-Allow-Methods", "POST, PATCH, DELETE, OPTIONS")
+		h.Set("Access-Control-Allow-Headers", "Authorization, Content-Type, If-Match, "+grpc_client.TokenHeaderName)
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	rw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	l := s.log.With(wlog.String("method", r.Method+" "+r.URL.Path))
+
+	session, err := s.session(r)
+	if err != nil {
+		h.Set("WWW-Authenticate", "Bearer")
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+	} else {
+		s.ServeMux.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), RequestContextSessionKey{}, session)))
+	}
+
+	duration := wlog.Float64("duration_ms", float64(time.Since(start).Microseconds())/float64(1000))
+	if rw.status >= http.StatusBadRequest {
+		l.Error(fmt.Sprintf("[%d] %s", rw.status, http.StatusText(rw.status)), duration)
+	} else {
+		l.Debug(fmt.Sprintf("[%d] %s %s", rw.status, r.Method, r.URL.Path), duration)
+	}
+}
+
+func (s *Server) session(r *http.Request) (*auth.Session, error) {
+	token := requestToken(r)
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	session, err := s.auth.GetSession(r.Context(), token)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.IsExpired() {
+		return nil, ErrUnauthenticated
+	}
+
+	return session, nil
+}
+
+// requestToken is the bearer token of the request or the access header of the gRPC clients.
+func requestToken(r *http.Request) string {
+	if t := r.Header.Get(grpc_client.TokenHeaderName); t != "" {
+		return t
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+func SessionFromCtx(ctx context.Context) (*auth.Session, error) {
+	sess, ok := ctx.Value(RequestContextSessionKey{}).(*auth.Session)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	return sess, nil
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
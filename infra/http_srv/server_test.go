@@ -0,0 +1,80 @@
+package http_srv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/infra/auth"
+)
+
+type authManager struct {
+	auth.Manager
+
+	sessions map[string]*auth.Session
+}
+
+func (am *authManager) GetSession(_ context.Context, token string) (*auth.Session, error) {
+	if s, ok := am.sessions[token]; ok {
+		return s, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
+func TestServer(t *testing.T) {
+	am := &authManager{sessions: map[string]*auth.Session{
+		"valid":   {ID: "1", DomainID: 1, Expire: time.Now().Add(time.Hour).Unix()},
+		"expired": {ID: "2", DomainID: 1, Expire: time.Now().Add(-time.Hour).Unix()},
+	}}
+
+	s, err := New("127.0.0.1:0", wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false}), am)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.listener.Close() })
+
+	s.HandleFunc("POST /whip", func(w http.ResponseWriter, r *http.Request) {
+		sess, err := SessionFromCtx(r.Context())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), sess.DomainID)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	for _, c := range []struct {
+		name   string
+		header map[string]string
+		status int
+	}{
+		{"bearer", map[string]string{"Authorization": "Bearer valid"}, http.StatusCreated},
+		{"access header", map[string]string{"X-Webitel-Access": "valid"}, http.StatusCreated},
+		{"no token", nil, http.StatusUnauthorized},
+		{"basic", map[string]string{"Authorization": "Basic valid"}, http.StatusUnauthorized},
+		{"expired", map[string]string{"Authorization": "Bearer expired"}, http.StatusUnauthorized},
+		{"unknown", map[string]string{"Authorization": "Bearer unknown"}, http.StatusUnauthorized},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/whip", nil)
+			for k, v := range c.header {
+				r.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+			assert.Equal(t, c.status, w.Code)
+			assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+
+	// the preflight of the browsers has no token
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/whip", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "PATCH")
+}
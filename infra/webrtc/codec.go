@@ -0,0 +1,112 @@
+package webrtc
+
+import (
+	"strings"
+
+	"github.com/pion/webrtc/v4"
+)
+
+// codecParams are the SDP parameters the codec is registered with.
+type codecParams struct {
+	mimeType  string
+	clockRate uint32
+	channels  uint16
+	// fmtp lines of the codec variants, each one gets its own payload type
+	fmtp     []string
+	feedback []webrtc.RTCPFeedback
+	// static payload type of RFC 3551
+	static      bool
+	payloadType webrtc.PayloadType
+}
+
+var (
+	videoFeedback = []webrtc.RTCPFeedback{
+		{Type: webrtc.TypeRTCPFBGoogREMB},
+		{Type: webrtc.TypeRTCPFBCCM, Parameter: "fir"},
+		{Type: webrtc.TypeRTCPFBNACK},
+		{Type: webrtc.TypeRTCPFBNACK, Parameter: "pli"},
+	}
+
+	codecs = map[string]codecParams{
+		"video/vp8": {
+			mimeType:  webrtc.MimeTypeVP8,
+			clockRate: 90000,
+			fmtp:      []string{""},
+			feedback:  videoFeedback,
+		},
+		"video/vp9": {
+			mimeType:  webrtc.MimeTypeVP9,
+			clockRate: 90000,
+			fmtp:      []string{"profile-id=0"},
+			feedback:  videoFeedback,
+		},
+		"video/h264": {
+			mimeType:  webrtc.MimeTypeH264,
+			clockRate: 90000,
+			fmtp: []string{
+				"level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42e01f",
+				"level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42001f",
+				"level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=640c1f",
+			},
+			feedback: videoFeedback,
+		},
+		"video/h265": {
+			mimeType:  webrtc.MimeTypeH265,
+			clockRate: 90000,
+			fmtp:      []string{"level-id=93;profile-id=1;tier-flag=0;tx-mode=SRST"},
+			feedback:  videoFeedback,
+		},
+		"video/av1": {
+			mimeType:  webrtc.MimeTypeAV1,
+			clockRate: 90000,
+			fmtp:      []string{"level-idx=5;profile=0;tier=0"},
+			feedback:  videoFeedback,
+		},
+		"audio/pcmu": {
+			mimeType:  webrtc.MimeTypePCMU,
+			clockRate: 8000,
+			fmtp:      []string{""},
+			static:    true,
+		},
+		"audio/pcma": {
+			mimeType:    webrtc.MimeTypePCMA,
+			clockRate:   8000,
+			fmtp:        []string{""},
+			static:      true,
+			payloadType: 8,
+		},
+		// the RTP clock of G.722 is 8000 while it samples 16 kHz
+		"audio/g722": {
+			mimeType:    webrtc.MimeTypeG722,
+			clockRate:   8000,
+			fmtp:        []string{""},
+			static:      true,
+			payloadType: 9,
+		},
+		"audio/opus": {
+			mimeType:  webrtc.MimeTypeOpus,
+			clockRate: 48000,
+			channels:  2,
+			fmtp:      []string{"minptime=10;useinbandfec=1"},
+		},
+	}
+)
+
+// lookupCodec returns the SDP parameters of the codec, the unknown ones are registered as they are.
+func lookupCodec(mimeType string) codecParams {
+	if c, ok := codecs[strings.ToLower(mimeType)]; ok {
+		return c
+	}
+
+	c := codecParams{
+		mimeType:  mimeType,
+		clockRate: 90000,
+		fmtp:      []string{""},
+	}
+
+	if strings.HasPrefix(mimeType, "audio") {
+		c.clockRate = 48000
+	}
+
+	return c
+}
@@ -21,6 +21,9 @@ type (
 	PeerConnection     = webrtc.PeerConnection
 )
 
+// AudioLevelURI is the RTP header extension of the audio level (RFC 6464), it finds the active speaker of the room.
+const AudioLevelURI = "urn:ietf:params:rtp-hdrext:ssrc-audio-level"
+
 type API interface {
 	NewPeerConnection(configuration webrtc.Configuration) (*webrtc.PeerConnection, error)
 }
@@ -69,21 +72,20 @@ func NewAPI(log *wlog.Logger, cfg *Settings) API {
 		log.Debug(fmt.Sprintf("set udp port range: %v/%v", cfg.EphemeralUDPPortRange.Min, cfg.EphemeralUDPPortRange.Max))
 	}
 
-	var payloadType webrtc.PayloadType = 97
+	// dynamic payload types
+	var payloadType webrtc.PayloadType = 96
 
 	for _, v := range cfg.Codecs {
-		var clRate = uint32(90000)
-
 		var typeCodec webrtc.RTPCodecType
 		if strings.HasPrefix(v, "audio") {
 			typeCodec = webrtc.RTPCodecTypeAudio
-			clRate = 48000
 		} else if strings.HasPrefix(v, "video") {
 			typeCodec = webrtc.RTPCodecTypeVideo
 		} else {
 			panic("unsupported codec")
 		}
 
+		var clRate uint32
 		if idx := strings.Index(v, "@"); idx > -1 {
 			tmp, _ := strconv.Atoi(v[idx+1:])
 			if tmp > 0 && tmp <= math.MaxUint32 {
@@ -92,21 +94,45 @@ func NewAPI(log *wlog.Logger, cfg *Settings) API {
 			v = v[:idx]
 		}
 
-		if err = mediaEngine.RegisterCodec(webrtc.RTPCodecParameters{
-			RTPCodecCapability: webrtc.RTPCodecCapability{
-				MimeType:     v,
-				ClockRate:    clRate,
-				Channels:     0,
-				SDPFmtpLine:  "",
-				RTCPFeedback: nil,
-			},
-			PayloadType: payloadType,
-		}, typeCodec); err != nil {
-			panic(err)
+		codec := lookupCodec(v)
+		if clRate == 0 {
+			clRate = codec.clockRate
+		}
+
+		for _, fmtp := range codec.fmtp {
+			pt := codec.payloadType
+
+			// the static payload types are defined for the default clock rate only
+			if !codec.static || clRate != codec.clockRate {
+				if payloadType > 127 {
+					panic("too many codecs")
+				}
+
+				pt = payloadType
+				payloadType++
+			}
+
+			if err = mediaEngine.RegisterCodec(webrtc.RTPCodecParameters{
+				RTPCodecCapability: webrtc.RTPCodecCapability{
+					MimeType:     codec.mimeType,
+					ClockRate:    clRate,
+					Channels:     codec.channels,
+					SDPFmtpLine:  fmtp,
+					RTCPFeedback: codec.feedback,
+				},
+				PayloadType: pt,
+			}, typeCodec); err != nil {
+				panic(err)
+			}
+
+			log.Debug(fmt.Sprintf("register codec: %s (PayloadType=%d, ClockRate=%d, fmtp=%s)", codec.mimeType, pt,
+				clRate, fmtp))
 		}
+	}
 
-		log.Debug(fmt.Sprintf("register codec: %s (PayloadType=%d, ClockRate=%d)", v, payloadType, clRate))
-		payloadType--
+	if err = mediaEngine.RegisterHeaderExtension(webrtc.RTPHeaderExtensionCapability{URI: AudioLevelURI},
+		webrtc.RTPCodecTypeAudio); err != nil {
+		panic(err)
 	}
 
 	registry := &interceptor.Registry{}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/gen/webrtc_recorder"
+	"github.com/webitel/webrtc_recorder/infra/auth"
+	"github.com/webitel/webrtc_recorder/infra/grpc_srv"
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+const (
+	defaultJobPageSize = 40
+	maxJobPageSize     = 1000
+)
+
+type FileJobService interface {
+	SearchJobs(filter *model.JobFilter) ([]*model.Job, bool, error)
+	ReadJob(domainID, id int) (*model.Job, error)
+	RetryJob(domainID, id int) (*model.Job, error)
+	TranscodeJob(domainID, id int) (*model.Job, error)
+	DeleteJob(domainID, id int) (*model.Job, error)
+}
+
+type FileJobs struct {
+	webrtc_recorder.UnimplementedFileJobServiceServer
+
+	log *wlog.Logger
+	svc FileJobService
+}
+
+func NewFileJobs(svc FileJobService, s *grpc_srv.Server, l *wlog.Logger) *FileJobs {
+	h := &FileJobs{
+		svc: svc,
+		log: l,
+	}
+	webrtc_recorder.RegisterFileJobServiceServer(s, h)
+
+	return h
+}
+
+// authorize checks the access of the caller to the recording files.
+func (h *FileJobs) authorize(ctx context.Context, acc auth.PermissionAccess) (*auth.Session, error) {
+	authUser, err := grpc_srv.SessionFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !authUser.GetPermission(auth.ScopeRecordFile).Can(acc) {
+		return nil, grpc_srv.ErrForbidden
+	}
+
+	return authUser, nil
+}
+
+func (h *FileJobs) SearchFileJobs(ctx context.Context, in *webrtc_recorder.SearchFileJobsRequest) (*webrtc_recorder.SearchFileJobsResponse, error) {
+	authUser, err := h.authorize(ctx, auth.PERMISSION_ACCESS_READ)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &model.JobFilter{
+		DomainID: int(authUser.DomainID),
+		Type:     in.GetType(),
+		Instance: in.GetInstance(),
+		HasError: in.HasError,
+		Page:     int(in.GetPage()),
+		Size:     int(in.GetSize()),
+	}
+
+	for _, st := range in.GetState() {
+		filter.State = append(filter.State, model.JobState(st))
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+
+	if filter.Size < 1 {
+		filter.Size = defaultJobPageSize
+	} else if filter.Size > maxJobPageSize {
+		filter.Size = maxJobPageSize
+	}
+
+	list, next, err := h.svc.SearchJobs(filter)
+	if err != nil {
+		return nil, jobError(err)
+	}
+
+	items := make([]*webrtc_recorder.FileJob, 0, len(list))
+	for _, j := range list {
+		items = append(items, toFileJob(j))
+	}
+
+	return &webrtc_recorder.SearchFileJobsResponse{
+		Next:  next,
+		Items: items,
+	}, nil
+}
+
+func (h *FileJobs) ReadFileJob(ctx context.Context, in *webrtc_recorder.ReadFileJobRequest) (*webrtc_recorder.FileJob, error) {
+	authUser, err := h.authorize(ctx, auth.PERMISSION_ACCESS_READ)
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := h.svc.ReadJob(int(authUser.DomainID), int(in.GetId()))
+	if err != nil {
+		return nil, jobError(err)
+	}
+
+	return toFileJob(j), nil
+}
+
+func (h *FileJobs) RetryFileJob(ctx context.Context, in *webrtc_recorder.RetryFileJobRequest) (*webrtc_recorder.FileJob, error) {
+	authUser, err := h.authorize(ctx, auth.PERMISSION_ACCESS_UPDATE)
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := h.svc.RetryJob(int(authUser.DomainID), int(in.GetId()))
+	if err != nil {
+		return nil, jobError(err)
+	}
+
+	return toFileJob(j), nil
+}
+
+func (h *FileJobs) TranscodeFileJob(ctx context.Context, in *webrtc_recorder.TranscodeFileJobRequest) (*webrtc_recorder.FileJob, error) {
+	authUser, err := h.authorize(ctx, auth.PERMISSION_ACCESS_UPDATE)
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := h.svc.TranscodeJob(int(authUser.DomainID), int(in.GetId()))
+	if err != nil {
+		return nil, jobError(err)
+	}
+
+	return toFileJob(j), nil
+}
+
+func (h *FileJobs) DeleteFileJob(ctx context.Context, in *webrtc_recorder.DeleteFileJobRequest) (*webrtc_recorder.FileJob, error) {
+	authUser, err := h.authorize(ctx, auth.PERMISSION_ACCESS_DELETE)
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := h.svc.DeleteJob(int(authUser.DomainID), int(in.GetId()))
+	if err != nil {
+		return nil, jobError(err)
+	}
+
+	return toFileJob(j), nil
+}
+
+// jobError maps the errors of the job manager to the gRPC status.
+func jobError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrJobNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, model.ErrJobActive), errors.Is(err, model.ErrJobOtherInstance),
+		errors.Is(err, model.ErrJobNoTracks):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
+
+func toFileJob(j *model.Job) *webrtc_recorder.FileJob {
+	res := &webrtc_recorder.FileJob{
+		Id:        int64(j.ID),
+		Type:      j.Type,
+		Instance:  j.Instance,
+		State:     webrtc_recorder.FileJobState(j.State),
+		Retry:     int32(j.Retry),
+		CreatedAt: j.CreatedAt.UnixMilli(),
+	}
+
+	if j.Error != nil {
+		res.Error = *j.Error
+	}
+
+	if j.ActivityAt != nil {
+		res.ActivityAt = j.ActivityAt.UnixMilli()
+	}
+
+	if j.NextAttemptAt != nil {
+		res.NextAttemptAt = j.NextAttemptAt.UnixMilli()
+	}
+
+	if j.File != nil {
+		res.DomainId = int64(j.File.DomainID)
+		res.Name = j.File.Name
+		res.Uuid = j.File.UUID
+		res.SessionId = j.File.SessionID
+		res.Segment = int32(j.File.Segment)
+	}
+
+	if j.Config != nil {
+		res.Sink = j.Config.Sink
+	}
+
+	for _, e := range j.Errors {
+		res.Errors = append(res.Errors, &webrtc_recorder.FileJobError{
+			Attempt:   int32(e.Attempt),
+			Type:      e.Type,
+			Error:     e.Error,
+			CreatedAt: int64(e.CreatedAt),
+		})
+	}
+
+	return res
+}
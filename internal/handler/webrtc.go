@@ -2,6 +2,11 @@ package handler
 
 import (
 	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/webitel/wlog"
 
 	spb "github.com/webitel/webrtc_recorder/gen/storage"
@@ -12,9 +17,17 @@ import (
 )
 
 type WebRTCRecorderService interface {
-	UploadP2PVideo(sdpOffer string, file model.File, ice []webrtci.ICEServer) (model.RtcUploadVideoSession, error)
+	UploadP2PVideo(sdpOffer string, file model.File, cfg model.JobConfig, ice []webrtci.ICEServer, trickle bool) (model.RtcUploadVideoSession, error)
 	CloseP2P(id string) error
 	RenegotiateP2P(id, sdpOffer string) (model.RtcUploadVideoSession, error)
+	PauseRecording(domainID int64, id string) error
+	ResumeRecording(domainID int64, id string) error
+	AddMarker(domainID int64, id, label string, ts int) (*model.Marker, error)
+	ListSessions(domainID int64) []*model.SessionInfo
+	GetSession(domainID int64, id string) (*model.SessionInfo, error)
+	WatchSession(domainID int64, id string) (<-chan *model.SessionEvent, func(), error)
+	AddIceCandidate(domainID int64, id string, c model.IceCandidate) error
+	WatchIceCandidates(domainID int64, id string) (<-chan model.IceCandidate, func(), error)
 }
 
 type WebRTCRecorder struct {
@@ -62,11 +75,25 @@ func (w *WebRTCRecorder) UploadP2PVideo(ctx context.Context, in *webrtc_recorder
 		UploadedBy: int(authUser.UserID),
 		CreatedAt:  model.GetMillis(),
 		Channel:    getChannel(in.GetChannel()),
+		RoomID:     in.GetRoomId(),
+	}
+
+	if file.RoomID != "" {
+		file.Participant = in.GetParticipant()
+		if file.Participant == "" {
+			file.Participant = authUser.Name
+		}
 	}
 
-	sess, err := w.svc.UploadP2PVideo(in.GetSdpOffer(), file, i)
+	cfg := model.JobConfig{
+		Sink:    in.GetSink(),
+		Profile: toTranscodingProfile(in.GetProfile()),
+		Layout:  in.GetLayout(),
+	}
+
+	sess, err := w.svc.UploadP2PVideo(in.GetSdpOffer(), file, cfg, i, in.GetTrickle())
 	if err != nil {
-		return nil, err
+		return nil, sessionError(err)
 	}
 
 	return &webrtc_recorder.UploadP2PVideoResponse{
@@ -78,7 +105,7 @@ func (w *WebRTCRecorder) UploadP2PVideo(ctx context.Context, in *webrtc_recorder
 func (w *WebRTCRecorder) StopP2PVideo(ctx context.Context, in *webrtc_recorder.StopP2PVideoRequest) (*webrtc_recorder.StopP2PVideoResponse, error) {
 	e := w.svc.CloseP2P(in.GetId())
 	if e != nil {
-		return nil, e
+		return nil, sessionError(e)
 	}
 
 	return &webrtc_recorder.StopP2PVideoResponse{}, nil
@@ -87,7 +114,7 @@ func (w *WebRTCRecorder) StopP2PVideo(ctx context.Context, in *webrtc_recorder.S
 func (w *WebRTCRecorder) RenegotiateP2PVideo(ctx context.Context, in *webrtc_recorder.RenegotiateP2PVideoRequest) (*webrtc_recorder.RenegotiateP2PVideoResponse, error) {
 	s, err := w.svc.RenegotiateP2P(in.GetId(), in.GetSdpOffer())
 	if err != nil {
-		return nil, err
+		return nil, sessionError(err)
 	}
 
 	return &webrtc_recorder.RenegotiateP2PVideoResponse{
@@ -95,6 +122,255 @@ func (w *WebRTCRecorder) RenegotiateP2PVideo(ctx context.Context, in *webrtc_rec
 	}, nil
 }
 
+func (w *WebRTCRecorder) PauseRecording(ctx context.Context, in *webrtc_recorder.PauseRecordingRequest) (*webrtc_recorder.PauseRecordingResponse, error) {
+	authUser, err := grpc_srv.SessionFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.svc.PauseRecording(authUser.DomainID, in.GetId()); err != nil {
+		return nil, sessionError(err)
+	}
+
+	return &webrtc_recorder.PauseRecordingResponse{}, nil
+}
+
+func (w *WebRTCRecorder) ResumeRecording(ctx context.Context, in *webrtc_recorder.ResumeRecordingRequest) (*webrtc_recorder.ResumeRecordingResponse, error) {
+	authUser, err := grpc_srv.SessionFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.svc.ResumeRecording(authUser.DomainID, in.GetId()); err != nil {
+		return nil, sessionError(err)
+	}
+
+	return &webrtc_recorder.ResumeRecordingResponse{}, nil
+}
+
+func (w *WebRTCRecorder) AddMarker(ctx context.Context, in *webrtc_recorder.AddMarkerRequest) (*webrtc_recorder.AddMarkerResponse, error) {
+	authUser, err := grpc_srv.SessionFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := w.svc.AddMarker(authUser.DomainID, in.GetId(), in.GetLabel(), int(in.GetTimestamp()))
+	if err != nil {
+		return nil, sessionError(err)
+	}
+
+	return &webrtc_recorder.AddMarkerResponse{
+		Marker: toMarker(m),
+	}, nil
+}
+
+func (w *WebRTCRecorder) ListSessions(ctx context.Context, _ *webrtc_recorder.ListSessionsRequest) (*webrtc_recorder.ListSessionsResponse, error) {
+	authUser, err := grpc_srv.SessionFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := w.svc.ListSessions(authUser.DomainID)
+	items := make([]*webrtc_recorder.Session, 0, len(list))
+
+	for _, s := range list {
+		items = append(items, toSession(s))
+	}
+
+	return &webrtc_recorder.ListSessionsResponse{
+		Items: items,
+	}, nil
+}
+
+func (w *WebRTCRecorder) GetSession(ctx context.Context, in *webrtc_recorder.GetSessionRequest) (*webrtc_recorder.Session, error) {
+	authUser, err := grpc_srv.SessionFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := w.svc.GetSession(authUser.DomainID, in.GetId())
+	if err != nil {
+		return nil, sessionError(err)
+	}
+
+	return toSession(s), nil
+}
+
+func (w *WebRTCRecorder) WatchSession(in *webrtc_recorder.WatchSessionRequest, stream webrtc_recorder.WebRTCService_WatchSessionServer) error {
+	authUser, err := grpc_srv.SessionFromCtx(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	events, cancel, err := w.svc.WatchSession(authUser.DomainID, in.GetId())
+	if err != nil {
+		return sessionError(err)
+	}
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			if err = stream.Send(toSessionEvent(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *WebRTCRecorder) AddIceCandidate(ctx context.Context, in *webrtc_recorder.AddIceCandidateRequest) (*webrtc_recorder.AddIceCandidateResponse, error) {
+	authUser, err := grpc_srv.SessionFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := model.IceCandidate{
+		Candidate:  in.GetCandidate().GetCandidate(),
+		Mid:        in.GetCandidate().GetSdpMid(),
+		MLineIndex: uint16(in.GetCandidate().GetSdpMlineIndex()), //nolint:gosec
+	}
+
+	if err = w.svc.AddIceCandidate(authUser.DomainID, in.GetId(), c); err != nil {
+		return nil, sessionError(err)
+	}
+
+	return &webrtc_recorder.AddIceCandidateResponse{}, nil
+}
+
+func (w *WebRTCRecorder) WatchIceCandidates(in *webrtc_recorder.WatchIceCandidatesRequest, stream webrtc_recorder.WebRTCService_WatchIceCandidatesServer) error {
+	authUser, err := grpc_srv.SessionFromCtx(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	candidates, cancel, err := w.svc.WatchIceCandidates(authUser.DomainID, in.GetId())
+	if err != nil {
+		return sessionError(err)
+	}
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case c, ok := <-candidates:
+			if !ok {
+				return nil
+			}
+
+			err = stream.Send(&webrtc_recorder.IceCandidate{
+				Candidate:     c.Candidate,
+				SdpMid:        c.Mid,
+				SdpMlineIndex: uint32(c.MLineIndex),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sessionError maps the errors of the recorder to the gRPC status, the same way the WHIP endpoint does.
+func sessionError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrSessionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, model.ErrIceMismatch), errors.Is(err, model.ErrTrickleDisabled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, model.ErrInvalidIceFragment), errors.Is(err, model.ErrUnknownProfile),
+		errors.Is(err, model.ErrInvalidProfile), errors.Is(err, model.ErrInvalidLayout),
+		errors.Is(err, model.ErrInvalidMarker):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
+}
+
+func toSessionEvent(ev *model.SessionEvent) *webrtc_recorder.SessionEvent {
+	res := &webrtc_recorder.SessionEvent{
+		SessionId:   ev.SessionID,
+		Type:        webrtc_recorder.SessionEventType(ev.Type),
+		Timestamp:   int64(ev.Timestamp),
+		IceState:    ev.ICEState,
+		DataChannel: ev.DataChannel,
+	}
+
+	if ev.Track != nil {
+		res.Track = toSessionTrack(ev.Track)
+	}
+
+	if ev.Gap != nil {
+		res.Gap = &webrtc_recorder.Gap{
+			At:       int64(ev.Gap.At),
+			Duration: int64(ev.Gap.Duration),
+			Redacted: ev.Gap.Redacted,
+		}
+	}
+
+	if ev.Marker != nil {
+		res.Marker = toMarker(ev.Marker)
+	}
+
+	if ev.Job != nil {
+		res.Job = &webrtc_recorder.JobEvent{
+			Id:       int64(ev.Job.ID),
+			Type:     ev.Job.Type,
+			State:    ev.Job.State,
+			Attempt:  int32(ev.Job.Attempt),
+			Error:    ev.Job.Error,
+			Segment:  int32(ev.Job.Segment),
+			FileId:   ev.Job.FileID,
+			Location: ev.Job.Location,
+		}
+	}
+
+	return res
+}
+
+func toMarker(m *model.Marker) *webrtc_recorder.Marker {
+	return &webrtc_recorder.Marker{
+		Label:     m.Label,
+		Timestamp: int64(m.Time),
+	}
+}
+
+func toSessionTrack(t *model.TrackInfo) *webrtc_recorder.SessionTrack {
+	return &webrtc_recorder.SessionTrack{
+		Id:           t.ID,
+		MimeType:     t.MimeType,
+		ClockRate:    t.ClockRate,
+		Packets:      t.Packets,
+		LostPackets:  t.LostPackets,
+		BytesWritten: t.Bytes,
+	}
+}
+
+func toSession(s *model.SessionInfo) *webrtc_recorder.Session {
+	tracks := make([]*webrtc_recorder.SessionTrack, 0, len(s.Tracks))
+	for _, t := range s.Tracks {
+		tracks = append(tracks, toSessionTrack(t))
+	}
+
+	return &webrtc_recorder.Session{
+		Id:        s.ID,
+		Name:      s.Name,
+		Uuid:      s.UUID,
+		DomainId:  int64(s.DomainID),
+		UserId:    int64(s.UserID),
+		IceState:  s.ICEState,
+		Paused:    s.Paused,
+		Tracks:    tracks,
+		StartTime: int64(s.StartTime),
+		CreatedAt: int64(s.CreatedAt),
+	}
+}
+
 func getChannel(ch spb.UploadFileChannel) int {
 	switch ch { // TODO allow other
 	case spb.UploadFileChannel_CallChannel:
@@ -103,3 +379,36 @@ func getChannel(ch spb.UploadFileChannel) int {
 		return int(spb.UploadFileChannel_ScreenRecordingChannel)
 	}
 }
+
+func toTranscodingProfile(p *webrtc_recorder.TranscodingProfile) *model.TranscodingProfile {
+	if p == nil {
+		return nil
+	}
+
+	res := &model.TranscodingProfile{
+		Name:         p.GetName(),
+		Width:        int(p.GetWidth()),
+		Height:       int(p.GetHeight()),
+		FPS:          int(p.GetFps()),
+		VideoCodec:   p.GetVideoCodec(),
+		AudioCodec:   p.GetAudioCodec(),
+		VideoBitrate: p.GetVideoBitrate(),
+		AudioBitrate: p.GetAudioBitrate(),
+		Preset:       p.GetPreset(),
+		Container:    p.GetContainer(),
+		AudioOnly:    p.GetAudioOnly(),
+		Subtitles:    p.GetSubtitles(),
+	}
+
+	if p.Crf != nil {
+		crf := int(p.GetCrf())
+		res.CRF = &crf
+	}
+
+	if p.Passthrough != nil {
+		passthrough := p.GetPassthrough()
+		res.Passthrough = &passthrough
+	}
+
+	return res
+}
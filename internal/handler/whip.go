@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/webitel/wlog"
+
+	spb "github.com/webitel/webrtc_recorder/gen/storage"
+	"github.com/webitel/webrtc_recorder/infra/http_srv"
+	webrtci "github.com/webitel/webrtc_recorder/infra/webrtc"
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+const (
+	whipPath = "/whip"
+
+	sdpMimeType     = "application/sdp"
+	sdpFragMimeType = "application/trickle-ice-sdpfrag"
+
+	maxSDPSize = 64 * 1024
+)
+
+type WHIPService interface {
+	UploadP2PVideo(sdpOffer string, file model.File, cfg model.JobConfig, ice []webrtci.ICEServer, trickle bool) (model.RtcUploadVideoSession, error)
+	PatchICE(domainID int64, id, ufrag string, frag *model.IceFragment) (*model.IceFragment, bool, error)
+	StopSession(domainID int64, id string) error
+}
+
+// WHIP is the WebRTC-HTTP ingestion endpoint (RFC 9725): POST of the SDP offer starts the recording,
+// PATCH of the trickle ICE fragment adds the candidates or restarts ICE and DELETE stops it.
+type WHIP struct {
+	log *wlog.Logger
+	svc WHIPService
+}
+
+// NewWHIP registers the endpoint on the HTTP server, the server is nil when it is disabled.
+func NewWHIP(svc WHIPService, s *http_srv.Server, l *wlog.Logger) *WHIP {
+	h := &WHIP{
+		svc: svc,
+		log: l,
+	}
+
+	if s != nil {
+		s.HandleFunc("POST "+whipPath, h.publish)
+		s.HandleFunc("PATCH "+whipPath+"/{id}", h.patch)
+		s.HandleFunc("DELETE "+whipPath+"/{id}", h.stop)
+	}
+
+	return h
+}
+
+// publish starts the recording, the query may set name, uuid, sink, profile and layout of the recording, room and
+// participant join it to the room recording.
+func (h *WHIP) publish(w http.ResponseWriter, r *http.Request) {
+	authUser, err := http_srv.SessionFromCtx(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	offer, ok := readBody(w, r, sdpMimeType)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+
+	name := q.Get("name")
+	if name == "" {
+		name = model.NewID()
+	}
+
+	file := model.File{
+		Name:       name,
+		UUID:       q.Get("uuid"),
+		DomainID:   int(authUser.DomainID),
+		UploadedBy: int(authUser.UserID),
+		CreatedAt:  model.GetMillis(),
+		Channel:    int(spb.UploadFileChannel_ScreenRecordingChannel),
+		RoomID:     q.Get("room"),
+	}
+
+	if file.RoomID != "" {
+		file.Participant = q.Get("participant")
+		if file.Participant == "" {
+			file.Participant = authUser.Name
+		}
+	}
+
+	cfg := model.JobConfig{
+		Sink:   q.Get("sink"),
+		Layout: q.Get("layout"),
+	}
+
+	if p := q.Get("profile"); p != "" {
+		cfg.Profile = &model.TranscodingProfile{Name: p}
+	}
+
+	sess, err := h.svc.UploadP2PVideo(offer, file, cfg, nil, false)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	answer := sess.AnswerSDP()
+
+	w.Header().Set("Content-Type", sdpMimeType)
+	w.Header().Set("Location", whipPath+"/"+sess.ID())
+	if local, err := model.ParseIceFragment(answer); err == nil && local.Ufrag != "" {
+		w.Header().Set("ETag", etag(local.Ufrag))
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	_, _ = io.WriteString(w, answer)
+}
+
+// patch adds the trickled candidates of the client, the new credentials restart ICE and the response
+// carries the local fragment of the restarted session.
+func (h *WHIP) patch(w http.ResponseWriter, r *http.Request) {
+	authUser, err := http_srv.SessionFromCtx(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	body, ok := readBody(w, r, sdpFragMimeType)
+	if !ok {
+		return
+	}
+
+	frag, err := model.ParseIceFragment(body)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// the restart is requested with "*", the trickle with the ETag of the session
+	ufrag := strings.Trim(r.Header.Get("If-Match"), `"`)
+	if ufrag == "*" {
+		ufrag = ""
+	}
+
+	local, restarted, err := h.svc.PatchICE(authUser.DomainID, r.PathValue("id"), ufrag, frag)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	if !restarted {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", sdpFragMimeType)
+	w.Header().Set("ETag", etag(local.Ufrag))
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, local.String())
+}
+
+func (h *WHIP) stop(w http.ResponseWriter, r *http.Request) {
+	authUser, err := http_srv.SessionFromCtx(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err = h.svc.StopSession(authUser.DomainID, r.PathValue("id")); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// readBody reads the body of the expected content type, the error response is written otherwise.
+func readBody(w http.ResponseWriter, r *http.Request, mimeType string) (string, bool) {
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != mimeType {
+		http.Error(w, "content type must be "+mimeType, http.StatusUnsupportedMediaType)
+		return "", false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSDPSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return "", false
+	}
+
+	return string(body), true
+}
+
+func writeError(w http.ResponseWriter, err error, status int) {
+	switch {
+	case errors.Is(err, model.ErrSessionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, model.ErrIceMismatch):
+		status = http.StatusPreconditionFailed
+	case errors.Is(err, model.ErrInvalidIceFragment), errors.Is(err, model.ErrUnknownProfile),
+		errors.Is(err, model.ErrInvalidProfile), errors.Is(err, model.ErrInvalidLayout):
+		status = http.StatusBadRequest
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
+func etag(ufrag string) string {
+	return `"` + ufrag + `"`
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// DataMimeType is the data channel track, its file keeps the parsed messages as JSON lines.
+const DataMimeType = "application/x-ndjson"
+
+// types of the data channel messages
+const (
+	DataEvent    = "event"
+	DataMetadata = "metadata"
+	DataCursor   = "cursor"
+	DataClick    = "click"
+	DataChat     = "chat"
+	DataMarker   = "marker"
+)
+
+const (
+	maxDataMessage = 64 * 1024
+	maxChatText    = 4096
+)
+
+var ErrInvalidDataMessage = errors.New("invalid data channel message")
+
+// DataMessage is the message of the data channel protocol. Timestamp is the session clock (unix ms),
+// the received time when the client does not send it, At is the position in the recording set by the transcoder.
+type DataMessage struct {
+	Type      string          `json:"type"`
+	Timestamp int             `json:"timestamp"`
+	At        int             `json:"at,omitempty"`
+	Channel   string          `json:"channel,omitempty"`
+	Label     string          `json:"label,omitempty"`
+	Text      string          `json:"text,omitempty"`
+	From      string          `json:"from,omitempty"`
+	X         *float64        `json:"x,omitempty"`
+	Y         *float64        `json:"y,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// ParseDataMessage decodes and checks the message of the data channel.
+func ParseDataMessage(b []byte) (*DataMessage, error) {
+	if len(b) > maxDataMessage {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrInvalidDataMessage, maxDataMessage)
+	}
+
+	var m DataMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDataMessage, err.Error())
+	}
+
+	m.At = 0
+
+	switch m.Type {
+	case DataEvent, DataMarker:
+		if m.Label == "" {
+			return nil, fmt.Errorf("%w: %s without label", ErrInvalidDataMessage, m.Type)
+		}
+	case DataMetadata:
+		if !bytes.HasPrefix(bytes.TrimSpace(m.Data), []byte("{")) {
+			return nil, fmt.Errorf("%w: metadata must be an object", ErrInvalidDataMessage)
+		}
+	case DataCursor, DataClick:
+		if !unitRange(m.X) || !unitRange(m.Y) {
+			return nil, fmt.Errorf("%w: %s position must be in 0..1", ErrInvalidDataMessage, m.Type)
+		}
+	case DataChat:
+		if m.Text == "" || utf8.RuneCountInString(m.Text) > maxChatText {
+			return nil, fmt.Errorf("%w: chat text must be 1-%d characters", ErrInvalidDataMessage, maxChatText)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidDataMessage, m.Type)
+	}
+
+	now := GetMillis()
+	if m.Timestamp <= 0 || m.Timestamp > now {
+		m.Timestamp = now
+	}
+
+	return &m, nil
+}
+
+func (m *DataMessage) JSON() []byte {
+	js, _ := json.Marshal(m)
+
+	return js
+}
+
+func unitRange(v *float64) bool {
+	return v != nil && *v >= 0 && *v <= 1
+}
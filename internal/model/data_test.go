@@ -0,0 +1,41 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDataMessage(t *testing.T) {
+	m, err := ParseDataMessage([]byte(`{"type":"click","timestamp":1000,"x":0.5,"y":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1000, m.Timestamp)
+	assert.InDelta(t, 0.5, *m.X, 0)
+
+	m, err = ParseDataMessage([]byte(`{"type":"metadata","data":{"ticket":42}}`))
+	require.NoError(t, err)
+	assert.Positive(t, m.Timestamp)
+	assert.JSONEq(t, `{"ticket":42}`, string(m.Data))
+
+	// the future time of the client is the received time
+	m, err = ParseDataMessage([]byte(`{"type":"event","label":"hold","timestamp":99999999999999}`))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, m.Timestamp, GetMillis())
+
+	for _, b := range []string{
+		`payment step`,
+		`{"type":"unknown"}`,
+		`{"type":"event"}`,
+		`{"type":"cursor","x":0.5}`,
+		`{"type":"cursor","x":0.5,"y":1.5}`,
+		`{"type":"metadata","data":[1]}`,
+		`{"type":"chat","text":""}`,
+		`{"type":"chat","text":"` + strings.Repeat("a", 4097) + `"}`,
+		`{"type":"event","label":"` + strings.Repeat("a", 64*1024) + `"}`,
+	} {
+		_, err = ParseDataMessage([]byte(b))
+		assert.ErrorIs(t, err, ErrInvalidDataMessage, b[:min(len(b), 40)])
+	}
+}
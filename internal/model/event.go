@@ -0,0 +1,47 @@
+package model
+
+type SessionEventType int
+
+const (
+	EventTrackAdded SessionEventType = iota + 1
+	EventIceStateChanged
+	EventDataChannelOpened
+	EventSessionClosed
+	EventJobStateChanged
+	EventFileSaved
+	EventTrackGap
+	EventRecordingPaused
+	EventRecordingResumed
+	EventMarkerAdded
+	EventTrackRemoved
+)
+
+const (
+	JobEventActive = "active"
+	JobEventRetry  = "retry"
+	JobEventFailed = "failed"
+	JobEventDone   = "done"
+)
+
+type SessionEvent struct {
+	SessionID   string           `json:"session_id"`
+	Type        SessionEventType `json:"type"`
+	Timestamp   int              `json:"timestamp"`
+	Track       *TrackInfo       `json:"track,omitempty"`
+	ICEState    string           `json:"ice_state,omitempty"`
+	DataChannel string           `json:"data_channel,omitempty"`
+	Job         *JobEvent        `json:"job,omitempty"`
+	Gap         *Gap             `json:"gap,omitempty"`
+	Marker      *Marker          `json:"marker,omitempty"`
+}
+
+type JobEvent struct {
+	ID       int    `json:"id"`
+	Type     string `json:"type"`
+	State    string `json:"state"`
+	Attempt  int    `json:"attempt"`
+	Error    string `json:"error,omitempty"`
+	Segment  int    `json:"segment,omitempty"`
+	FileID   int64  `json:"file_id,omitempty"`
+	Location string `json:"location,omitempty"`
+}
@@ -2,6 +2,8 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 )
 
 //-f avfoundation -framerate 15  -i "1:none" -f avfoundation -filter_complex "[0:v]scale=1280:720[v0];[1:v]scale=1280:720[v1];[v0][v1] hstack=inputs=2" -c:v libx264 -y ./1.mp4
@@ -18,13 +20,253 @@ type File struct {
 	Channel    int            `json:"channel"`
 	StartTime  int            `json:"start_time"`
 	EndTime    int            `json:"end_time"`
+	Segment    int            `json:"segment,omitempty"`
+	SessionID  string         `json:"session_id,omitempty"`
+	// Pauses are the intervals redacted from the recording
+	Pauses []Pause `json:"pauses,omitempty"`
+	// Markers are the chapters of the recording
+	Markers []Marker `json:"markers,omitempty"`
+	// RoomID joins the recordings of the participants into one, Participant is the caption of the participant
+	RoomID      string `json:"room_id,omitempty"`
+	Participant string `json:"participant,omitempty"`
+}
+
+// Pause is the interval the recording was paused, unix ms, End is 0 while it lasts.
+type Pause struct {
+	Start int `json:"start"`
+	End   int `json:"end,omitempty"`
+}
+
+// Paused tells whether the recording is paused now.
+func (f *File) Paused() bool {
+	n := len(f.Pauses)
+
+	return n > 0 && f.Pauses[n-1].End == 0
+}
+
+// PausedSince tells whether the recording was paused after t.
+func (f *File) PausedSince(t int) bool {
+	n := len(f.Pauses)
+
+	return n > 0 && f.Pauses[n-1].Start >= t
 }
 
 type MediaChannel struct {
-	Path       string `json:"path"`
-	MimeType   string `json:"mime_type"`
+	Path     string `json:"path"`
+	MimeType string `json:"mime_type"`
+	// Label of the data channel
+	Label      string `json:"label,omitempty"`
 	FirstRtpTs uint32 `json:"first_rtp_ts,omitempty"`
 	LastRtpTs  uint32 `json:"last_rtp_ts,omitempty"`
+	ClockRate  uint32 `json:"clock_rate,omitempty"`
+	// FirstPacketAt is the arrival of the first packet, the timing of the tracks without sender reports
+	FirstPacketAt int `json:"first_packet_at,omitempty"`
+	// Clock maps the RTP timestamps of the track to the wall clock
+	Clock []RtpClock `json:"clock,omitempty"`
+	// Offset of the track from the start of the recording and its Duration, ms
+	Offset   int `json:"offset,omitempty"`
+	Duration int `json:"duration,omitempty"`
+	// LeftAt is the time the track was removed from the session by the renegotiation, unix ms,
+	// Leave is its offset from the start of the recording, ms
+	LeftAt int `json:"left_at,omitempty"`
+	Leave  int `json:"leave,omitempty"`
+	// Gaps are the pauses of the sender the track file does not keep
+	Gaps []Gap `json:"gaps,omitempty"`
+	// Peer is the session of the room participant that sent the track and Participant is its caption
+	Peer        string `json:"peer,omitempty"`
+	Participant string `json:"participant,omitempty"`
+	// Speech are the intervals the participant of the audio track talks
+	Speech []Speech `json:"speech,omitempty"`
+	// Role of the video track is the screen share or the camera, it takes the slot of the layout
+	Role string `json:"role,omitempty"`
+}
+
+// Gap is the pause of the track, At is the position in the recorded media and Duration is the missed time, ms.
+// The Redacted gap is the paused recording, it is filled with the placeholder instead of the last frame.
+type Gap struct {
+	At       int  `json:"at"`
+	Duration int  `json:"duration"`
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// GapsDuration is the time of the track missing from its file, ms.
+func (c *MediaChannel) GapsDuration() int {
+	var d int
+	for _, g := range c.Gaps {
+		d += g.Duration
+	}
+
+	return d
+}
+
+// RtpClock is the wall clock (unix ms) of the RTP timestamp taken from an RTCP sender report, ReceivedAt is the
+// arrival of the report by the server clock.
+type RtpClock struct {
+	RtpTs      uint32 `json:"rtp_ts"`
+	Time       int    `json:"time"`
+	ReceivedAt int    `json:"received_at,omitempty"`
+}
+
+// clockInterval is the min distance of the kept points of the timing map.
+const clockInterval = 60 * 1000
+
+// AddClock adds the sender report to the timing map, the reports closer than a minute to the previous
+// point replace the last one so the map stays small.
+func (c *MediaChannel) AddClock(p RtpClock) {
+	if n := len(c.Clock); n >= 2 && p.Time-c.Clock[n-2].Time < clockInterval {
+		c.Clock[n-1] = p
+
+		return
+	}
+
+	c.Clock = append(c.Clock, p)
+}
+
+// WallTime maps the RTP timestamp of the track to the wall clock, the rate between the reports
+// keeps the drift of the sender clock.
+func (c *MediaChannel) WallTime(ts uint32) (int, bool) {
+	if len(c.Clock) == 0 || c.ClockRate == 0 {
+		return 0, false
+	}
+
+	// the nearest pair of the reports
+	a := c.Clock[0]
+	b := a
+
+	for i := 1; i < len(c.Clock); i++ {
+		a, b = c.Clock[i-1], c.Clock[i]
+		if int32(ts-b.RtpTs) <= 0 { //nolint:gosec
+			break
+		}
+	}
+
+	msPerTick := 1000 / float64(c.ClockRate)
+	if ticks := int32(b.RtpTs - a.RtpTs); ticks > 0 && b.Time > a.Time { //nolint:gosec
+		msPerTick = float64(b.Time-a.Time) / float64(ticks)
+	}
+
+	return a.Time + int(float64(int32(ts-a.RtpTs))*msPerTick), true //nolint:gosec
+}
+
+// skew is the distance from the sender clock to the server clock, the report with the shortest way to the server
+// keeps the least of the network delay.
+func (c *MediaChannel) skew() int {
+	var (
+		skew int
+		ok   bool
+	)
+
+	for _, p := range c.Clock {
+		if p.ReceivedAt == 0 {
+			continue
+		}
+
+		if d := p.ReceivedAt - p.Time; !ok || d < skew {
+			skew, ok = d, true
+		}
+	}
+
+	return skew
+}
+
+// ServerTime maps the RTP timestamp of the track to the server clock, the arrival of the packets, the markers
+// and the speech are taken from.
+func (c *MediaChannel) ServerTime(ts uint32) (int, bool) {
+	t, ok := c.WallTime(ts)
+	if !ok {
+		return 0, false
+	}
+
+	return t + c.skew(), true
+}
+
+// IsMedia tells whether the track is the audio or the video.
+func (c *MediaChannel) IsMedia() bool {
+	return strings.HasPrefix(c.MimeType, "audio") || strings.HasPrefix(c.MimeType, "video")
+}
+
+// span returns the server clock of the first and the last packet of the track.
+func (c *MediaChannel) span() (int, int, bool) {
+	if c.FirstPacketAt == 0 || c.ClockRate == 0 {
+		return 0, 0, false
+	}
+
+	start, ok := c.ServerTime(c.FirstRtpTs)
+	if !ok {
+		start = c.FirstPacketAt
+	}
+
+	end, ok := c.ServerTime(c.LastRtpTs)
+	if !ok {
+		end = start + int(int64(c.LastRtpTs-c.FirstRtpTs)*1000/int64(c.ClockRate))
+	}
+
+	return start, end, true
+}
+
+// Align sets the offsets of the tracks from the earliest one and their durations from the sender reports,
+// the tracks without the timing keep zero values. The removed tracks leave at the end of their media, the markers
+// and the speech are placed from the same start. The sender reports are moved to the server clock, so the tracks
+// of the participants with the skewed clocks share one timeline with the server events.
+func (f *File) Align() {
+	var first int
+
+	for i := range f.Track {
+		if start, _, ok := f.Track[i].span(); ok && (first == 0 || start < first) {
+			first = start
+		}
+	}
+
+	for i := range f.Track {
+		ch := &f.Track[i]
+		if start, end, ok := ch.span(); ok {
+			ch.Offset = start - first
+			ch.Duration = end - start
+		}
+	}
+
+	if first == 0 {
+		first = f.StartTime
+	}
+
+	for i := range f.Track {
+		// the data channel is placed by its first message
+		if ch := &f.Track[i]; ch.MimeType == DataMimeType && ch.FirstPacketAt > 0 {
+			ch.Offset = ch.FirstPacketAt - first
+		}
+	}
+
+	for i := range f.Track {
+		ch := &f.Track[i]
+		switch {
+		case ch.LeftAt == 0:
+		case ch.Duration > 0:
+			ch.Leave = ch.Offset + ch.Duration
+		default:
+			ch.Leave = max(ch.LeftAt-first, ch.Offset)
+		}
+	}
+
+	for i := range f.Markers {
+		f.Markers[i].At = max(f.Markers[i].Time-first, 0)
+	}
+
+	for i := range f.Track {
+		for j := range f.Track[i].Speech {
+			sp := &f.Track[i].Speech[j]
+			sp.At = max(sp.Start-first, 0)
+			sp.Duration = sp.End - sp.Start
+		}
+	}
+}
+
+// SegmentName is the name of the uploaded part of a segmented recording.
+func (f *File) SegmentName() string {
+	if f.Segment == 0 {
+		return f.Name
+	}
+
+	return fmt.Sprintf("%s_part%03d", f.Name, f.Segment)
 }
 
 func (f *File) JSON() []byte {
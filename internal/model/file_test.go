@@ -0,0 +1,138 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlign(t *testing.T) {
+	f := File{StartTime: 9000, Markers: []Marker{{Label: "early", Time: 9500}, {Label: "escalated", Time: 15000}}, Track: []MediaChannel{
+		// the sender clock runs 1% fast
+		{
+			MimeType: "video/VP8", ClockRate: 90000, FirstPacketAt: 1000,
+			FirstRtpTs: 0, LastRtpTs: 909000,
+			Clock: []RtpClock{{RtpTs: 0, Time: 10000}, {RtpTs: 909000, Time: 20000}},
+		},
+		// joined later, the wraparound of the RTP timestamp
+		{
+			MimeType: "audio/opus", ClockRate: 48000, FirstPacketAt: 1000,
+			FirstRtpTs: 0xFFFFFFFF - 47999, LastRtpTs: 48000 * 7,
+			Clock:  []RtpClock{{RtpTs: 0, Time: 13000}},
+			Speech: []Speech{{Start: 12500, End: 14000}},
+		},
+		// without the sender reports, removed by the renegotiation
+		{MimeType: "audio/opus", ClockRate: 48000, FirstPacketAt: 14500, FirstRtpTs: 100, LastRtpTs: 100 + 48000, LeftAt: 16000},
+		{MimeType: "text/plain", LeftAt: 16000},
+	}}
+
+	f.Align()
+
+	assert.Equal(t, 0, f.Track[0].Offset)
+	assert.Equal(t, 10000, f.Track[0].Duration)
+	assert.Equal(t, 2000, f.Track[1].Offset)
+	assert.Equal(t, 8000, f.Track[1].Duration)
+	assert.Equal(t, 4500, f.Track[2].Offset)
+	assert.Equal(t, 1000, f.Track[2].Duration)
+	assert.Equal(t, 0, f.Track[3].Duration)
+
+	// the tracks stay till the end unless they were removed
+	assert.Equal(t, 0, f.Track[0].Leave)
+	assert.Equal(t, 5500, f.Track[2].Leave)
+	assert.Equal(t, 6000, f.Track[3].Leave)
+
+	// from the start of the earliest track
+	assert.Equal(t, 0, f.Markers[0].At)
+	assert.Equal(t, 5000, f.Markers[1].At)
+	assert.Equal(t, Speech{Start: 12500, End: 14000, At: 2500, Duration: 1500}, f.Track[1].Speech[0])
+}
+
+func TestAlignClockSkew(t *testing.T) {
+	const hour = 3600 * 1000
+
+	f := File{StartTime: 9000, Markers: []Marker{{Label: "escalated", Time: 15000}}, Track: []MediaChannel{
+		// the clock of the first participant runs an hour ahead, the reports reach the server in 20-50 ms
+		{
+			MimeType: "audio/opus", ClockRate: 48000, FirstPacketAt: 10020,
+			FirstRtpTs: 0, LastRtpTs: 48000 * 10,
+			Clock: []RtpClock{
+				{RtpTs: 0, Time: hour + 10000, ReceivedAt: 10050},
+				{RtpTs: 48000 * 5, Time: hour + 15000, ReceivedAt: 15020},
+			},
+			Speech: []Speech{{Start: 12000, End: 13000}},
+		},
+		// the clock of the second one is behind the server
+		{
+			MimeType: "video/VP8", ClockRate: 90000, FirstPacketAt: 12020,
+			FirstRtpTs: 0, LastRtpTs: 90000 * 4,
+			Clock: []RtpClock{{RtpTs: 0, Time: 2000, ReceivedAt: 12020}},
+		},
+		{MimeType: DataMimeType, FirstPacketAt: 14020},
+	}}
+
+	f.Align()
+
+	assert.Equal(t, 0, f.Track[0].Offset)
+	assert.Equal(t, 10000, f.Track[0].Duration)
+	assert.Equal(t, 2000, f.Track[1].Offset)
+	assert.Equal(t, 4000, f.Track[1].Duration)
+	assert.Equal(t, 4000, f.Track[2].Offset)
+	assert.Equal(t, 4980, f.Markers[0].At)
+	assert.Equal(t, 1980, f.Track[0].Speech[0].At)
+}
+
+func TestNewMarker(t *testing.T) {
+	_, err := NewMarker("  ", 0)
+	assert.ErrorIs(t, err, ErrInvalidMarker)
+
+	m, err := NewMarker(" payment step ", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, &Marker{Label: "payment step", Time: 1000}, m)
+
+	// no markers in the future
+	m, err = NewMarker("late", GetMillis()+60000)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, m.Time, GetMillis())
+}
+
+func TestAddClock(t *testing.T) {
+	var ch MediaChannel
+
+	for i := 0; i < 100; i++ {
+		ch.AddClock(RtpClock{RtpTs: uint32(i * 45000), Time: i * 5000})
+	}
+
+	// a point a minute
+	assert.Len(t, ch.Clock, 10)
+	assert.Equal(t, 495000, ch.Clock[len(ch.Clock)-1].Time)
+}
+
+func TestSegmentName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		file     File
+		expected string
+	}{
+		{
+			name:     "whole recording",
+			file:     File{Name: "call"},
+			expected: "call",
+		},
+		{
+			name:     "first segment",
+			file:     File{Name: "call", Segment: 1},
+			expected: "call_part001",
+		},
+		{
+			name:     "segment over the padding",
+			file:     File{Name: "call", Segment: 1234},
+			expected: "call_part1234",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.file.SegmentName())
+		})
+	}
+}
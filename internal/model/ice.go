@@ -0,0 +1,133 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidIceFragment = errors.New("invalid ice fragment")
+	ErrTrickleDisabled    = errors.New("session does not trickle ice candidates")
+	// ErrIceMismatch is the request for the ICE session that was restarted since
+	ErrIceMismatch = errors.New("ice session does not match")
+)
+
+// IceCandidate is the candidate line of the SDP without the "a=" prefix, Mid is its media section or
+// MLineIndex when there is no mid. The empty candidate is the end of the gathering.
+type IceCandidate struct {
+	Candidate  string `json:"candidate"`
+	Mid        string `json:"mid,omitempty"`
+	MLineIndex uint16 `json:"mline_index,omitempty"`
+}
+
+// IceFragment is the trickle ICE SDP fragment (RFC 8840): the ICE credentials and the candidates.
+type IceFragment struct {
+	Ufrag      string
+	Pwd        string
+	Candidates []IceCandidate
+	// EndOfCandidates tells the gathering is complete
+	EndOfCandidates bool
+}
+
+// ParseIceFragment reads the credentials and the candidates of the SDP fragment or of the whole SDP.
+func ParseIceFragment(sdp string) (*IceFragment, error) {
+	var (
+		f   IceFragment
+		mid string
+	)
+
+	for _, line := range strings.Split(sdp, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "m="):
+			mid = ""
+		case strings.HasPrefix(line, "a=mid:"):
+			mid = strings.TrimPrefix(line, "a=mid:")
+		case strings.HasPrefix(line, "a=ice-ufrag:"):
+			if f.Ufrag == "" {
+				f.Ufrag = strings.TrimPrefix(line, "a=ice-ufrag:")
+			}
+		case strings.HasPrefix(line, "a=ice-pwd:"):
+			if f.Pwd == "" {
+				f.Pwd = strings.TrimPrefix(line, "a=ice-pwd:")
+			}
+		case strings.HasPrefix(line, "a=candidate:"):
+			f.Candidates = append(f.Candidates, IceCandidate{Candidate: line[2:], Mid: mid})
+		case line == "a=end-of-candidates":
+			f.EndOfCandidates = true
+		case len(line) < 2 || line[1] != '=':
+			return nil, fmt.Errorf("%w: line %q", ErrInvalidIceFragment, line)
+		}
+	}
+
+	if (f.Ufrag == "") != (f.Pwd == "") {
+		return nil, fmt.Errorf("%w: ice-ufrag without ice-pwd", ErrInvalidIceFragment)
+	}
+
+	return &f, nil
+}
+
+// String is the SDP fragment, the candidates are grouped by their media sections.
+func (f *IceFragment) String() string {
+	var b strings.Builder
+
+	if f.Ufrag != "" {
+		b.WriteString("a=ice-ufrag:" + f.Ufrag + "\r\n")
+		b.WriteString("a=ice-pwd:" + f.Pwd + "\r\n")
+	}
+
+	var mids []string
+
+	byMid := make(map[string][]string)
+	for _, c := range f.Candidates {
+		if _, ok := byMid[c.Mid]; !ok {
+			mids = append(mids, c.Mid)
+		}
+
+		byMid[c.Mid] = append(byMid[c.Mid], c.Candidate)
+	}
+
+	for _, mid := range mids {
+		b.WriteString("m=audio 9 RTP/AVP 0\r\n")
+		if mid != "" {
+			b.WriteString("a=mid:" + mid + "\r\n")
+		}
+
+		for _, c := range byMid[mid] {
+			b.WriteString("a=" + c + "\r\n")
+		}
+	}
+
+	if f.EndOfCandidates {
+		b.WriteString("a=end-of-candidates\r\n")
+	}
+
+	return b.String()
+}
+
+// WithIceCredentials replaces the ICE credentials of the SDP and drops its candidates, the offer
+// of the restarted ICE session.
+func WithIceCredentials(sdp, ufrag, pwd string) string {
+	lines := strings.SplitAfter(sdp, "\n")
+	res := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "a=ice-ufrag:"):
+			line = "a=ice-ufrag:" + ufrag + "\r\n"
+		case strings.HasPrefix(trimmed, "a=ice-pwd:"):
+			line = "a=ice-pwd:" + pwd + "\r\n"
+		case strings.HasPrefix(trimmed, "a=candidate:"), trimmed == "a=end-of-candidates":
+			continue
+		}
+
+		res = append(res, line)
+	}
+
+	return strings.Join(res, "")
+}
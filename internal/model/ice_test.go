@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIceFragment(t *testing.T) {
+	f, err := ParseIceFragment("a=ice-ufrag:EsAw\r\na=ice-pwd:P2uYro0UCOQ4zxjKXaWCBui1\r\n" +
+		"m=audio 9 RTP/AVP 0\r\na=mid:0\r\n" +
+		"a=candidate:1387637174 1 udp 2122260223 192.0.2.1 61764 typ host generation 0\r\n" +
+		"a=end-of-candidates\r\n")
+	require.NoError(t, err)
+	assert.Equal(t, &IceFragment{
+		Ufrag:           "EsAw",
+		Pwd:             "P2uYro0UCOQ4zxjKXaWCBui1",
+		Candidates:      []IceCandidate{{Candidate: "candidate:1387637174 1 udp 2122260223 192.0.2.1 61764 typ host generation 0", Mid: "0"}},
+		EndOfCandidates: true,
+	}, f)
+
+	assert.Equal(t, "a=ice-ufrag:EsAw\r\na=ice-pwd:P2uYro0UCOQ4zxjKXaWCBui1\r\n"+
+		"m=audio 9 RTP/AVP 0\r\na=mid:0\r\n"+
+		"a=candidate:1387637174 1 udp 2122260223 192.0.2.1 61764 typ host generation 0\r\n"+
+		"a=end-of-candidates\r\n", f.String())
+
+	_, err = ParseIceFragment("a=ice-ufrag:EsAw\r\n")
+	require.ErrorIs(t, err, ErrInvalidIceFragment)
+
+	_, err = ParseIceFragment("candidate:1 1 udp 1 192.0.2.1 1 typ host")
+	require.ErrorIs(t, err, ErrInvalidIceFragment)
+}
+
+func TestWithIceCredentials(t *testing.T) {
+	offer := "v=0\r\na=group:BUNDLE 0 1\r\nm=audio 9 UDP/TLS/RTP/SAVPF 111\r\na=ice-ufrag:old\r\na=ice-pwd:oldpwd\r\n" +
+		"a=candidate:1 1 udp 1 192.0.2.1 5000 typ host\r\na=end-of-candidates\r\na=mid:0\r\n" +
+		"m=video 9 UDP/TLS/RTP/SAVPF 96\r\na=ice-ufrag:old\r\na=ice-pwd:oldpwd\r\na=mid:1\r\n"
+
+	assert.Equal(t, "v=0\r\na=group:BUNDLE 0 1\r\nm=audio 9 UDP/TLS/RTP/SAVPF 111\r\na=ice-ufrag:new\r\na=ice-pwd:newpwd\r\n"+
+		"a=mid:0\r\n"+
+		"m=video 9 UDP/TLS/RTP/SAVPF 96\r\na=ice-ufrag:new\r\na=ice-pwd:newpwd\r\na=mid:1\r\n",
+		WithIceCredentials(offer, "new", "newpwd"))
+}
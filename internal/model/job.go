@@ -1,22 +1,82 @@
 package model
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrJobNotFound      = errors.New("job not found")
+	ErrJobActive        = errors.New("job is running")
+	ErrJobOtherInstance = errors.New("job belongs to another instance")
+	ErrJobNoTracks      = errors.New("raw tracks of the job do not exist")
+)
 
 type JobState uint
 
 const (
 	JobIdle JobState = iota
 	JobActive
+	// JobFailed jobs ran out of attempts, their files are kept in quarantine until retried or deleted
+	JobFailed
 )
 
-type JobConfig struct{}
+// JobError is the failure of one attempt of the job.
+type JobError struct {
+	Attempt   int    `json:"attempt"`
+	Type      string `json:"type"`
+	Error     string `json:"error"`
+	CreatedAt int    `json:"created_at"`
+}
+
+// UploadState keeps the upload of the sink so an interrupted upload can resume: the storage SafeUploadFile session,
+// or the partial file of the local sink with the size of the uploaded file.
+type UploadState struct {
+	ID     string `json:"id"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size,omitempty"`
+}
+
+// UploadedFile is the result of the delivered recording.
+type UploadedFile struct {
+	ID       int64  `json:"id,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+type JobConfig struct {
+	Sink   string       `json:"sink,omitempty"`
+	Upload *UploadState `json:"upload,omitempty"`
+	// Profile is resolved when the recording starts, nil uses the domain default
+	Profile *TranscodingProfile `json:"profile,omitempty"`
+	// Layout of the composed video of several tracks, see LayoutGrid
+	Layout string `json:"layout,omitempty"`
+}
 
 type Job struct {
-	ID     int        `json:"id" db:"id"`
-	Type   string     `json:"type" db:"type"`
-	File   *File      `json:"file" db:"file"`
-	Config *JobConfig `json:"config" db:"config"`
-	Retry  int        `json:"retry" db:"retry"`
+	ID            int        `json:"id" db:"id"`
+	Type          string     `json:"type" db:"type"`
+	Instance      string     `json:"instance,omitempty" db:"instance"`
+	State         JobState   `json:"state" db:"state"`
+	File          *File      `json:"file" db:"file"`
+	Config        *JobConfig `json:"config" db:"config"`
+	Retry         int        `json:"retry" db:"retry"`
+	Error         *string    `json:"error,omitempty" db:"error"`
+	Errors        []JobError `json:"errors,omitempty" db:"errors"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	ActivityAt    *time.Time `json:"activity_at,omitempty" db:"activity_at"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+}
+
+// JobFilter selects the jobs of the domain, empty fields match any job.
+type JobFilter struct {
+	DomainID int
+	Type     string
+	State    []JobState
+	Instance string
+	HasError *bool
+	Page     int
+	Size     int
 }
 
 func (j *JobConfig) JSON() []byte {
@@ -24,3 +84,9 @@ func (j *JobConfig) JSON() []byte {
 
 	return js
 }
+
+func (e *JobError) JSON() []byte {
+	js, _ := json.Marshal(e)
+
+	return js
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// LayoutGrid shows the video tracks in the grid of equal windows
+	LayoutGrid = "grid"
+	// LayoutSpeaker shows the video of the participant that talks, the grid until somebody talks
+	LayoutSpeaker = "speaker"
+	// LayoutFocus shows the screen share or the active speaker large and the other tracks in the filmstrip
+	LayoutFocus = "focus"
+	// LayoutPiP shows the camera in the corner over the screen share
+	LayoutPiP = "pip"
+	// LayoutHStack and LayoutVStack put the tracks side by side and one above the other
+	LayoutHStack = "hstack"
+	LayoutVStack = "vstack"
+)
+
+const (
+	RoleCamera = "camera"
+	RoleScreen = "screen"
+)
+
+var (
+	ErrInvalidLayout = errors.New("invalid layout")
+
+	screenIDs = []string{"screen", "display", "desktop"}
+)
+
+// ValidateLayout checks the layout of the composed video, empty is the grid.
+func (c *JobConfig) ValidateLayout() error {
+	switch c.Layout {
+	case "", LayoutGrid, LayoutSpeaker, LayoutFocus, LayoutPiP, LayoutHStack, LayoutVStack:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrInvalidLayout, c.Layout)
+}
+
+// TrackRole is the role of the video track by its msid: the stream or the track id of the screen capture
+// names the screen, the display or the desktop, the other tracks are the cameras.
+func TrackRole(streamID, trackID string) string {
+	for _, id := range []string{streamID, trackID} {
+		id = strings.ToLower(id)
+		for _, s := range screenIDs {
+			if strings.Contains(id, s) {
+				return RoleScreen
+			}
+		}
+	}
+
+	return RoleCamera
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLayout(t *testing.T) {
+	for _, l := range []string{"", LayoutGrid, LayoutSpeaker, LayoutFocus, LayoutPiP, LayoutHStack, LayoutVStack} {
+		assert.NoError(t, (&JobConfig{Layout: l}).ValidateLayout())
+	}
+
+	assert.ErrorIs(t, (&JobConfig{Layout: "mosaic"}).ValidateLayout(), ErrInvalidLayout)
+	assert.Equal(t, "1/standup", RoomKey(1, "standup"))
+}
+
+func TestTrackRole(t *testing.T) {
+	assert.Equal(t, RoleScreen, TrackRole("{4f1c}", "screen-0"))
+	assert.Equal(t, RoleScreen, TrackRole("Display capture", "{9a2e}"))
+	assert.Equal(t, RoleCamera, TrackRole("{4f1c}", "{9a2e}"))
+}
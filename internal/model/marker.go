@@ -0,0 +1,36 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxMarkerLabel = 256
+
+var ErrInvalidMarker = errors.New("invalid marker")
+
+// Marker is the bookmark of the moment of the recording, Time is the session clock (unix ms)
+// and At is the position in the recording set by File.Align.
+type Marker struct {
+	Label string `json:"label"`
+	Time  int    `json:"time"`
+	At    int    `json:"at"`
+}
+
+// NewMarker checks the label of the marker, zero ts is now.
+func NewMarker(label string, ts int) (*Marker, error) {
+	label = strings.TrimSpace(label)
+	if label == "" || utf8.RuneCountInString(label) > maxMarkerLabel {
+		return nil, fmt.Errorf("%w: label must be 1-%d characters", ErrInvalidMarker, maxMarkerLabel)
+	}
+
+	now := GetMillis()
+	if ts <= 0 || ts > now {
+		// the session clock has no future
+		ts = now
+	}
+
+	return &Marker{Label: label, Time: ts}, nil
+}
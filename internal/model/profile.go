@@ -0,0 +1,329 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+const DefaultTranscodingProfile = "default"
+
+var (
+	ErrUnknownProfile = errors.New("unknown transcoding profile")
+	ErrInvalidProfile = errors.New("invalid transcoding profile")
+
+	bitrateRegex = regexp.MustCompile(`^[0-9]+[kM]?$`)
+)
+
+// TranscodingProfile is the output of the transcoding, zero fields keep the values of the named profile.
+type TranscodingProfile struct {
+	Name         string `json:"name,omitempty"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	FPS          int    `json:"fps,omitempty"`
+	VideoCodec   string `json:"video_codec,omitempty"`
+	AudioCodec   string `json:"audio_codec,omitempty"`
+	CRF          *int   `json:"crf,omitempty"`
+	VideoBitrate string `json:"video_bitrate,omitempty"`
+	AudioBitrate string `json:"audio_bitrate,omitempty"`
+	Preset       string `json:"preset,omitempty"`
+	Tune         string `json:"tune,omitempty"`
+	Container    string `json:"container,omitempty"`
+	AudioOnly    bool   `json:"audio_only,omitempty"`
+	// Passthrough remuxes the tracks without re-encoding when their codecs fit a container
+	Passthrough *bool `json:"passthrough,omitempty"`
+	// Subtitles renders the chat and the events of the data channels as the subtitle track
+	Subtitles bool `json:"subtitles,omitempty"`
+}
+
+type container struct {
+	format    string
+	mimeType  string
+	audioOnly bool
+	video     []string
+	audio     []string
+	subtitles string
+}
+
+var (
+	containers = map[string]container{
+		"mp4": {
+			format:    "mp4",
+			mimeType:  "video/mp4",
+			video:     []string{"libx264", "libx265", "libaom-av1"},
+			audio:     []string{"aac", "libopus", "libmp3lame"},
+			subtitles: "mov_text",
+		},
+		"mkv": {
+			format:    "matroska",
+			mimeType:  "video/x-matroska",
+			video:     []string{"libx264", "libx265", "libvpx-vp9", "libaom-av1"},
+			audio:     []string{"aac", "libopus", "flac", "pcm_s16le"},
+			subtitles: "webvtt",
+		},
+		"webm": {
+			format:    "webm",
+			mimeType:  "video/webm",
+			video:     []string{"libvpx-vp9", "libaom-av1"},
+			audio:     []string{"libopus"},
+			subtitles: "webvtt",
+		},
+		"m4a": {
+			format:    "ipod",
+			mimeType:  "audio/mp4",
+			audioOnly: true,
+			audio:     []string{"aac"},
+		},
+	}
+
+	// maxCRF is the worst quality of the constant quality mode of the video encoder
+	maxCRF = map[string]int{
+		"libx264":    51,
+		"libx265":    51,
+		"libvpx-vp9": 63,
+		"libaom-av1": 63,
+	}
+
+	presets = []string{"ultrafast", "superfast", "veryfast", "faster", "fast", "medium", "slow", "slower", "veryslow"}
+	tunes   = []string{"film", "animation", "grain", "stillimage", "fastdecode", "zerolatency"}
+)
+
+// TranscodingProfiles are the named profiles the recording may refer to.
+var TranscodingProfiles = map[string]TranscodingProfile{
+	DefaultTranscodingProfile: {
+		Width:        1920,
+		Height:       1080,
+		VideoCodec:   "libx264",
+		AudioCodec:   "aac",
+		AudioBitrate: "192k",
+		Preset:       "fast",
+		Tune:         "animation",
+		Container:    "mp4",
+		Passthrough:  boolPtr(true),
+	},
+	// agent screens
+	"hd720": {
+		Width:        1280,
+		Height:       720,
+		FPS:          30,
+		VideoCodec:   "libx264",
+		AudioCodec:   "aac",
+		CRF:          intPtr(23),
+		AudioBitrate: "128k",
+		Preset:       "fast",
+		Tune:         "stillimage",
+		Container:    "mp4",
+	},
+	// low bitrate archive
+	"archive": {
+		Width:        854,
+		Height:       480,
+		FPS:          15,
+		VideoCodec:   "libx264",
+		AudioCodec:   "aac",
+		CRF:          intPtr(32),
+		AudioBitrate: "64k",
+		Preset:       "slow",
+		Container:    "mp4",
+	},
+	"audio": {
+		AudioCodec:   "aac",
+		AudioBitrate: "96k",
+		Container:    "m4a",
+		AudioOnly:    true,
+	},
+	// lossless audit
+	"lossless": {
+		Width:      1920,
+		Height:     1080,
+		VideoCodec: "libx264",
+		AudioCodec: "flac",
+		CRF:        intPtr(0),
+		Preset:     "medium",
+		Container:  "mkv",
+	},
+}
+
+// With returns the copy of the profile overridden by the non-zero fields of o.
+func (p TranscodingProfile) With(o *TranscodingProfile) *TranscodingProfile {
+	if o == nil {
+		return &p
+	}
+
+	// the explicit output parameters need the encoder
+	if o.Width > 0 || o.FPS > 0 || o.VideoCodec != "" || o.AudioCodec != "" || o.CRF != nil ||
+		o.VideoBitrate != "" || o.AudioBitrate != "" || o.Container != "" || o.AudioOnly {
+		p.Passthrough = nil
+	}
+
+	if o.Passthrough != nil {
+		p.Passthrough = o.Passthrough
+	}
+
+	if o.Container != "" && o.Container != p.Container {
+		p.Container = o.Container
+		// codecs of the named profile the container does not support fall back to its own
+		if c, ok := containers[o.Container]; ok {
+			if !slices.Contains(c.video, p.VideoCodec) && len(c.video) > 0 {
+				p.VideoCodec = c.video[0]
+				p.Preset, p.Tune = "", ""
+			}
+
+			if !slices.Contains(c.audio, p.AudioCodec) {
+				p.AudioCodec = c.audio[0]
+				p.AudioBitrate = ""
+			}
+
+			p.AudioOnly = p.AudioOnly || c.audioOnly
+		}
+	}
+
+	if o.Width > 0 && o.Height > 0 {
+		p.Width, p.Height = o.Width, o.Height
+	}
+
+	if o.FPS > 0 {
+		p.FPS = o.FPS
+	}
+
+	if o.VideoCodec != "" {
+		p.VideoCodec = o.VideoCodec
+		// presets of the other codec do not apply
+		p.Preset, p.Tune = "", ""
+	}
+
+	if o.AudioCodec != "" {
+		p.AudioCodec = o.AudioCodec
+		p.AudioBitrate = ""
+	}
+
+	if o.CRF != nil {
+		p.CRF = o.CRF
+		p.VideoBitrate = ""
+	}
+
+	if o.VideoBitrate != "" {
+		p.VideoBitrate = o.VideoBitrate
+	}
+
+	if o.AudioBitrate != "" {
+		p.AudioBitrate = o.AudioBitrate
+	}
+
+	if o.Preset != "" {
+		p.Preset = o.Preset
+	}
+
+	if o.Tune != "" {
+		p.Tune = o.Tune
+	}
+
+	if o.AudioOnly {
+		p.AudioOnly = true
+	}
+
+	if o.Subtitles {
+		p.Subtitles = true
+	}
+
+	return &p
+}
+
+// Validate checks the profile is supported by the transcoder.
+func (p *TranscodingProfile) Validate() error {
+	c, ok := containers[p.Container]
+	if !ok {
+		return fmt.Errorf("%w: container %q", ErrInvalidProfile, p.Container)
+	}
+
+	if c.audioOnly && !p.AudioOnly {
+		return fmt.Errorf("%w: container %s is audio only", ErrInvalidProfile, p.Container)
+	}
+
+	if !slices.Contains(c.audio, p.AudioCodec) {
+		return fmt.Errorf("%w: audio codec %q in %s", ErrInvalidProfile, p.AudioCodec, p.Container)
+	}
+
+	if !bitrateValid(p.AudioBitrate) {
+		return fmt.Errorf("%w: audio bitrate %q", ErrInvalidProfile, p.AudioBitrate)
+	}
+
+	if p.AudioOnly {
+		return nil
+	}
+
+	if !slices.Contains(c.video, p.VideoCodec) {
+		return fmt.Errorf("%w: video codec %q in %s", ErrInvalidProfile, p.VideoCodec, p.Container)
+	}
+
+	if p.Width <= 0 || p.Height <= 0 || p.Width%2 != 0 || p.Height%2 != 0 {
+		return fmt.Errorf("%w: resolution %dx%d", ErrInvalidProfile, p.Width, p.Height)
+	}
+
+	if p.FPS < 0 || p.FPS > 60 {
+		return fmt.Errorf("%w: fps %d", ErrInvalidProfile, p.FPS)
+	}
+
+	if p.CRF != nil && (*p.CRF < 0 || *p.CRF > maxCRF[p.VideoCodec]) {
+		return fmt.Errorf("%w: crf %d of %s", ErrInvalidProfile, *p.CRF, p.VideoCodec)
+	}
+
+	if !bitrateValid(p.VideoBitrate) {
+		return fmt.Errorf("%w: video bitrate %q", ErrInvalidProfile, p.VideoBitrate)
+	}
+
+	if p.Preset != "" && !slices.Contains(presets, p.Preset) {
+		return fmt.Errorf("%w: preset %q", ErrInvalidProfile, p.Preset)
+	}
+
+	if p.Tune != "" && !slices.Contains(tunes, p.Tune) {
+		return fmt.Errorf("%w: tune %q", ErrInvalidProfile, p.Tune)
+	}
+
+	return nil
+}
+
+// Remux tells whether the tracks may be copied without re-encoding.
+func (p *TranscodingProfile) Remux() bool {
+	return p.Passthrough != nil && *p.Passthrough
+}
+
+// SubtitleCodec is the encoder of the subtitle track, empty when the profile has none.
+func (p *TranscodingProfile) SubtitleCodec() string {
+	if !p.Subtitles || p.AudioOnly {
+		return ""
+	}
+
+	return containers[p.Container].subtitles
+}
+
+// Format is the ffmpeg muxer of the container.
+func (p *TranscodingProfile) Format() string {
+	return containers[p.Container].format
+}
+
+// Ext is the extension of the transcoded file.
+func (p *TranscodingProfile) Ext() string {
+	return p.Container
+}
+
+func (p *TranscodingProfile) MimeType() string {
+	if p.AudioOnly && p.Container == "mp4" {
+		return "audio/mp4"
+	}
+
+	return containers[p.Container].mimeType
+}
+
+func bitrateValid(b string) bool {
+	return b == "" || bitrateRegex.MatchString(b)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscodingProfile_ValidateCRF(t *testing.T) {
+	testCases := []struct {
+		name  string
+		codec string
+		crf   int
+		valid bool
+	}{
+		{name: "lossless h264", codec: "libx264", crf: 0, valid: true},
+		{name: "worst h264", codec: "libx264", crf: 51, valid: true},
+		{name: "out of h264 range", codec: "libx264", crf: 52},
+		{name: "out of h265 range", codec: "libx265", crf: 63},
+		{name: "worst vp9", codec: "libvpx-vp9", crf: 63, valid: true},
+		{name: "out of av1 range", codec: "libaom-av1", crf: 64},
+		{name: "negative", codec: "libx264", crf: -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := TranscodingProfiles["lossless"].With(&TranscodingProfile{VideoCodec: tc.codec, CRF: intPtr(tc.crf)})
+
+			err := p.Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidProfile)
+			}
+		})
+	}
+}
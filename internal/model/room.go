@@ -0,0 +1,17 @@
+package model
+
+import "fmt"
+
+// Speech is the interval the participant talks, Start and End are the session clock (unix ms),
+// At and Duration are its position in the recording set by File.Align.
+type Speech struct {
+	Start    int `json:"start"`
+	End      int `json:"end"`
+	At       int `json:"at,omitempty"`
+	Duration int `json:"duration,omitempty"`
+}
+
+// RoomKey is the key of the room recording the participants of the domain join.
+func RoomKey(domainID int, roomID string) string {
+	return fmt.Sprintf("%d/%s", domainID, roomID)
+}
@@ -1,6 +1,50 @@
 package model
 
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
 type RtcUploadVideoSession interface {
 	ID() string
 	AnswerSDP() string
+	Info() *SessionInfo
+}
+
+type SessionInfo struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	UUID      string       `json:"uuid"`
+	DomainID  int          `json:"domain_id"`
+	UserID    int          `json:"user_id"`
+	ICEState  string       `json:"ice_state"`
+	Paused    bool         `json:"paused"`
+	StartTime int          `json:"start_time"`
+	CreatedAt int          `json:"created_at"`
+	Tracks    []*TrackInfo `json:"tracks"`
+}
+
+type TrackInfo struct {
+	ID          string `json:"id"`
+	MimeType    string `json:"mime_type"`
+	ClockRate   uint32 `json:"clock_rate"`
+	Packets     int64  `json:"packets"`
+	LostPackets int64  `json:"lost_packets"`
+	Bytes       int64  `json:"bytes"`
+}
+
+// SessionManifest is kept in the temp dir while the session records, so its files can be recovered after a crash.
+type SessionManifest struct {
+	ID       string     `json:"id"`
+	Instance string     `json:"instance"`
+	File     *File      `json:"file"`
+	Config   *JobConfig `json:"config"`
+}
+
+func (m *SessionManifest) JSON() []byte {
+	js, _ := json.Marshal(m)
+
+	return js
 }
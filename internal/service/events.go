@@ -0,0 +1,144 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+const eventBufferSize = 64
+
+// SessionEvents fans out the events of a recording session to its watchers. The feed of the session
+// lives until it is closed and every job of its recording is finished.
+type SessionEvents struct {
+	mu     sync.Mutex
+	log    *wlog.Logger
+	topics map[string]*eventTopic
+}
+
+type eventTopic struct {
+	domainID int
+	closed   bool
+	pending  int
+	seq      int
+	subs     map[int]chan *model.SessionEvent
+}
+
+func NewSessionEvents(log *wlog.Logger) *SessionEvents {
+	return &SessionEvents{
+		log:    log.With(wlog.String("service", "events")),
+		topics: make(map[string]*eventTopic),
+	}
+}
+
+func (e *SessionEvents) open(id string, domainID int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.topics[id] = &eventTopic{
+		domainID: domainID,
+		subs:     make(map[int]chan *model.SessionEvent),
+	}
+}
+
+// Publish sends the event to the watchers of the session, a watcher that does not keep up misses it.
+func (e *SessionEvents) Publish(ev *model.SessionEvent) {
+	if ev.Timestamp == 0 {
+		ev.Timestamp = model.GetMillis()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, ok := e.topics[ev.SessionID]
+	if !ok {
+		return
+	}
+
+	for _, ch := range t.subs {
+		select {
+		case ch <- ev:
+		default:
+			e.log.Warn("watcher is too slow, event dropped", wlog.String("session", ev.SessionID))
+		}
+	}
+}
+
+// Subscribe returns the event feed of the session, initial events are delivered first.
+// The channel is closed when the feed ends or cancel is called.
+func (e *SessionEvents) Subscribe(id string, domainID int, initial []*model.SessionEvent) (<-chan *model.SessionEvent, func(), error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, ok := e.topics[id]
+	if !ok || t.domainID != domainID {
+		return nil, nil, model.ErrSessionNotFound
+	}
+
+	ch := make(chan *model.SessionEvent, eventBufferSize+len(initial))
+	for _, ev := range initial {
+		ch <- ev
+	}
+
+	t.seq++
+	key := t.seq
+	t.subs[key] = ch
+
+	cancel := func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		if c, ok := t.subs[key]; ok {
+			delete(t.subs, key)
+			close(c)
+		}
+	}
+
+	return ch, cancel, nil
+}
+
+// jobCreated keeps the feed open until the job is finished.
+func (e *SessionEvents) jobCreated(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if t, ok := e.topics[id]; ok {
+		t.pending++
+	}
+}
+
+func (e *SessionEvents) jobFinished(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if t, ok := e.topics[id]; ok {
+		t.pending--
+		e.release(id, t)
+	}
+}
+
+func (e *SessionEvents) sessionClosed(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if t, ok := e.topics[id]; ok {
+		t.closed = true
+		e.release(id, t)
+	}
+}
+
+// release ends the feed once nothing can happen to the session anymore, e.mu must be held.
+func (e *SessionEvents) release(id string, t *eventTopic) {
+	if !t.closed || t.pending > 0 {
+		return
+	}
+
+	for key, ch := range t.subs {
+		delete(t.subs, key)
+		close(ch)
+	}
+
+	delete(e.topics, id)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func drain(ch <-chan *model.SessionEvent) []model.SessionEventType {
+	var types []model.SessionEventType
+	for ev := range ch {
+		types = append(types, ev.Type)
+	}
+
+	return types
+}
+
+func TestSessionEvents(t *testing.T) {
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+
+	t.Run("Unknown session or domain", func(t *testing.T) {
+		e := NewSessionEvents(log)
+		e.open("s1", 1)
+
+		_, _, err := e.Subscribe("s2", 1, nil)
+		require.ErrorIs(t, err, model.ErrSessionNotFound)
+
+		_, _, err = e.Subscribe("s1", 2, nil)
+		require.ErrorIs(t, err, model.ErrSessionNotFound)
+	})
+
+	t.Run("Feed ends after the last job", func(t *testing.T) {
+		e := NewSessionEvents(log)
+		e.open("s1", 1)
+
+		ch, cancel, err := e.Subscribe("s1", 1, []*model.SessionEvent{{SessionID: "s1", Type: model.EventTrackAdded}})
+		require.NoError(t, err)
+		defer cancel()
+
+		e.jobCreated("s1")
+		e.Publish(&model.SessionEvent{SessionID: "s1", Type: model.EventSessionClosed})
+		e.sessionClosed("s1")
+		e.Publish(&model.SessionEvent{SessionID: "s1", Type: model.EventFileSaved})
+		e.jobFinished("s1")
+
+		assert.Equal(t, []model.SessionEventType{
+			model.EventTrackAdded, model.EventSessionClosed, model.EventFileSaved,
+		}, drain(ch))
+
+		_, _, err = e.Subscribe("s1", 1, nil)
+		require.ErrorIs(t, err, model.ErrSessionNotFound)
+	})
+
+	t.Run("Cancel closes the channel", func(t *testing.T) {
+		e := NewSessionEvents(log)
+		e.open("s1", 1)
+
+		ch, cancel, err := e.Subscribe("s1", 1, nil)
+		require.NoError(t, err)
+
+		cancel()
+		assert.Empty(t, drain(ch))
+
+		e.sessionClosed("s1")
+		cancel()
+	})
+}
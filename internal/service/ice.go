@@ -0,0 +1,90 @@
+package service
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pion/webrtc/v4"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+// iceCandidates keeps the local candidates of the trickle ICE session, so the watcher that comes
+// after the answer gets all of them.
+type iceCandidates struct {
+	mu   sync.Mutex
+	list []model.IceCandidate
+	// changed is closed and replaced by the next candidate
+	changed chan struct{}
+}
+
+func newIceCandidates() *iceCandidates {
+	return &iceCandidates{changed: make(chan struct{})}
+}
+
+// add appends the gathered candidate, nil is the end of the gathering.
+func (c *iceCandidates) add(cand *webrtc.ICECandidate) {
+	var ic model.IceCandidate
+	if cand != nil {
+		js := cand.ToJSON()
+		ic = model.IceCandidate{Candidate: candidateLine(js.Candidate), Mid: cand.SDPMid, MLineIndex: cand.SDPMLineIndex}
+	}
+
+	c.mu.Lock()
+	c.list = append(c.list, ic)
+	close(c.changed)
+	c.changed = make(chan struct{})
+	c.mu.Unlock()
+}
+
+// since returns the candidates after the first n and the channel closed by the next one.
+func (c *iceCandidates) since(n int) ([]model.IceCandidate, <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.list[min(n, len(c.list)):], c.changed
+}
+
+// watch sends the candidates from the first one until stop or done is closed.
+func (c *iceCandidates) watch(stop, done <-chan struct{}) <-chan model.IceCandidate {
+	ch := make(chan model.IceCandidate)
+
+	go func() {
+		defer close(ch)
+
+		n := 0
+		for {
+			list, changed := c.since(n)
+			for _, cand := range list {
+				select {
+				case ch <- cand:
+				case <-stop:
+					return
+				case <-done:
+					return
+				}
+			}
+
+			n += len(list)
+
+			select {
+			case <-changed:
+			case <-stop:
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// candidateLine adds the attribute name the browsers expect to the candidate.
+func candidateLine(c string) string {
+	if c == "" || strings.HasPrefix(c, "candidate:") {
+		return c
+	}
+
+	return "candidate:" + c
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func TestIceCandidatesWatch(t *testing.T) {
+	c := newIceCandidates()
+	c.list = append(c.list, model.IceCandidate{Candidate: "candidate:1 1 udp 1 192.0.2.1 5000 typ host", Mid: "0"})
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	ch := c.watch(stop, done)
+
+	// the candidates gathered before the watcher are replayed
+	assert.Equal(t, "0", (<-ch).Mid)
+
+	c.add(nil)
+	assert.Equal(t, model.IceCandidate{}, <-ch)
+
+	close(done)
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	list, _ := c.since(5)
+	assert.Empty(t, list)
+	assert.Equal(t, "candidate:1 1", candidateLine("1 1"))
+}
+
+func TestTrickleNegotiate(t *testing.T) {
+	client, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	_, err = client.CreateDataChannel("events", nil)
+	require.NoError(t, err)
+
+	offer, err := client.CreateOffer(nil)
+	require.NoError(t, err)
+	require.NoError(t, client.SetLocalDescription(offer))
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+
+	s := &RtcUploadMediaSession{pc: pc, log: wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false}), candidates: newIceCandidates()}
+	pc.OnICECandidate(s.candidates.add)
+
+	require.NoError(t, s.negotiate(offer.SDP))
+	assert.NotContains(t, s.AnswerSDP(), "a=candidate:")
+
+	connected := make(chan struct{})
+	client.OnICEConnectionStateChange(func(state webrtc.ICEConnectionState) {
+		if state == webrtc.ICEConnectionStateConnected {
+			close(connected)
+		}
+	})
+
+	require.NoError(t, client.SetRemoteDescription(*s.answer))
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	ch := s.candidates.watch(stop, nil)
+	for {
+		select {
+		case c := <-ch:
+			if c.Candidate != "" {
+				assert.True(t, strings.HasPrefix(c.Candidate, "candidate:"), c.Candidate)
+			}
+
+			require.NoError(t, client.AddICECandidate(webrtc.ICECandidateInit{Candidate: c.Candidate, SDPMid: &c.Mid}))
+		case <-connected:
+			return
+		case <-time.After(10 * time.Second):
+			t.Fatal("ice is not connected")
+		}
+	}
+}
+
+func TestReconnectConcurrentCandidates(t *testing.T) {
+	client, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	_, err = client.CreateDataChannel("events", nil)
+	require.NoError(t, err)
+
+	offer, err := client.CreateOffer(nil)
+	require.NoError(t, err)
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	require.NoError(t, err)
+
+	next, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = next.Close() })
+
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+	s := &RtcUploadMediaSession{
+		pc:         pc,
+		log:        log,
+		rec:        &WebRtcRecorder{events: NewSessionEvents(log), reconnect: time.Hour},
+		candidates: newIceCandidates(),
+	}
+	require.NoError(t, s.negotiate(offer.SDP))
+	require.True(t, s.startGrace())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 100; i++ {
+			_ = s.addRemoteCandidate(model.IceCandidate{Candidate: "candidate:1 1 udp 1 192.0.2.1 5000 typ host", Mid: "0"})
+			_, _, _ = s.patchICE("", &model.IceFragment{})
+		}
+	}()
+
+	ok, err := s.reconnect(next, offer.SDP, true)
+	<-done
+
+	require.True(t, ok)
+	require.NoError(t, err)
+	assert.Same(t, next, s.peer())
+	assert.NotEmpty(t, s.AnswerSDP())
+}
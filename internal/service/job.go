@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
+
+	"github.com/jpillora/backoff"
 
 	"github.com/webitel/wlog"
 
+	"github.com/webitel/webrtc_recorder/config"
 	"github.com/webitel/webrtc_recorder/internal/model"
 )
 
@@ -13,6 +18,18 @@ type jobHandler struct {
 	ctx      context.Context
 	log      *wlog.Logger
 	tempFile *TempFileService
+	events   *SessionEvents
+	backoff  *backoff.Backoff
+	lease    *JobLease
+}
+
+func newBackoff(cfg config.BackoffSettings) *backoff.Backoff {
+	return &backoff.Backoff{
+		Min:    cfg.Min,
+		Max:    cfg.Max,
+		Factor: cfg.Factor,
+		Jitter: cfg.Jitter,
+	}
 }
 
 type baseJob struct {
@@ -21,20 +38,55 @@ type baseJob struct {
 	log *wlog.Logger
 }
 
+// fetch returns the queued jobs of the instance, filled up to the limit with the jobs taken over from other instances.
+func (svc *jobHandler) fetch(limit int, jobType string) ([]*model.Job, error) {
+	jobs, err := svc.jobStore.Fetch(limit, jobType)
+	if err != nil {
+		return nil, err
+	}
+
+	if svc.lease != nil {
+		jobs = append(jobs, svc.lease.claim(limit-len(jobs), jobType)...)
+	}
+
+	return jobs, nil
+}
+
 func (svc *jobHandler) errorJob(j *baseJob, maxRetry int, err error) {
 	j.log.Error(err.Error(), wlog.Err(err))
 
+	jobErr := &model.JobError{
+		Attempt:   j.job.Retry,
+		Type:      j.job.Type,
+		Error:     err.Error(),
+		CreatedAt: model.GetMillis(),
+	}
+
+	var delay time.Duration
+
+	state := model.JobIdle
 	if j.job.Retry >= maxRetry {
-		j.log.Error("max attempts reached")
-		svc.cleanup(j)
+		j.log.Error("max attempts reached, moving files to quarantine")
 
-		return
+		state = model.JobFailed
+		if qErr := svc.tempFile.Quarantine(j.job.File); qErr != nil {
+			j.log.Error(qErr.Error(), wlog.Err(qErr))
+		}
+
+		svc.jobEvent(j, model.JobEventFailed, err)
+	} else {
+		delay = svc.backoff.ForAttempt(float64(j.job.Retry - 1))
+		j.log.Debug(fmt.Sprintf("next attempt in %s", delay))
+		svc.jobEvent(j, model.JobEventRetry, err)
 	}
 
-	err = svc.jobStore.SetError(j.job.ID, err)
-	if err != nil {
+	if err = svc.jobStore.SetError(j.job, state, jobErr, delay); err != nil {
 		j.log.Error(err.Error(), wlog.Err(err))
 	}
+
+	if state == model.JobFailed {
+		svc.jobFinished(j)
+	}
 }
 
 func (svc *jobHandler) cleanup(j *baseJob) {
@@ -48,3 +100,28 @@ func (svc *jobHandler) cleanup(j *baseJob) {
 		j.log.Error(err.Error(), wlog.Err(err))
 	}
 }
+
+// jobEvent notifies the watchers of the recording session about the job state.
+func (svc *jobHandler) jobEvent(j *baseJob, state string, err error) {
+	ev := &model.JobEvent{
+		ID:      j.job.ID,
+		Type:    j.job.Type,
+		State:   state,
+		Attempt: j.job.Retry,
+		Segment: j.job.File.Segment,
+	}
+
+	if err != nil {
+		ev.Error = err.Error()
+	}
+
+	svc.events.Publish(&model.SessionEvent{
+		SessionID: j.job.File.SessionID,
+		Type:      model.EventJobStateChanged,
+		Job:       ev,
+	})
+}
+
+func (svc *jobHandler) jobFinished(j *baseJob) {
+	svc.events.jobFinished(j.job.File.SessionID)
+}
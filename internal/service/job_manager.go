@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/config"
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+// JobManager lets operators inspect and control the transcoding and upload jobs.
+type JobManager struct {
+	log      *wlog.Logger
+	jobStore FileJobStore
+	tempFile *TempFileService
+	uploader *Uploader
+	events   *SessionEvents
+	instance string
+	shared   bool
+}
+
+func NewJobManager(cfg *config.Config, log *wlog.Logger, fjs FileJobStore, tmp *TempFileService, upl *Uploader, ev *SessionEvents) *JobManager {
+	return &JobManager{
+		log:      log.With(wlog.String("service", "jobs")),
+		jobStore: fjs,
+		tempFile: tmp,
+		uploader: upl,
+		events:   ev,
+		instance: cfg.Service.ID,
+		shared:   cfg.SharedTemp,
+	}
+}
+
+// SearchJobs returns a page of the jobs and whether there is a next one.
+func (svc *JobManager) SearchJobs(filter *model.JobFilter) ([]*model.Job, bool, error) {
+	jobs, err := svc.jobStore.List(filter)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(jobs) > filter.Size {
+		return jobs[:filter.Size], true, nil
+	}
+
+	return jobs, false, nil
+}
+
+func (svc *JobManager) ReadJob(domainID, id int) (*model.Job, error) {
+	return svc.jobStore.Get(domainID, id)
+}
+
+// RetryJob runs the job again without waiting for the next attempt.
+func (svc *JobManager) RetryJob(domainID, id int) (*model.Job, error) {
+	if _, err := svc.idleJob(domainID, id); err != nil {
+		return nil, err
+	}
+
+	return svc.jobStore.Retry(domainID, id)
+}
+
+// TranscodeJob queues the transcoding of the job again. Only the transcoding jobs (waiting for the next attempt
+// or failed and quarantined) keep the raw tracks, the successful transcoding removes them and the uploaded
+// recordings leave no job, so the raw tracks must still be in the temp dir of this instance or in the shared one.
+func (svc *JobManager) TranscodeJob(domainID, id int) (*model.Job, error) {
+	j, err := svc.idleJob(domainID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if j.Type != TranscodingJobName {
+		return nil, fmt.Errorf("%w: %s job", model.ErrJobNoTracks, j.Type)
+	}
+
+	if j.Instance != svc.instance && !svc.shared {
+		return nil, fmt.Errorf("%w: %s", model.ErrJobOtherInstance, j.Instance)
+	}
+
+	if len(j.File.Track) == 0 {
+		return nil, model.ErrJobNoTracks
+	}
+
+	for _, t := range j.File.Track {
+		if _, err = os.Stat(t.Path); err != nil {
+			return nil, fmt.Errorf("%w: %s", model.ErrJobNoTracks, err.Error())
+		}
+	}
+
+	if j.Config == nil {
+		j.Config = &model.JobConfig{}
+	}
+
+	return svc.jobStore.Restart(domainID, j)
+}
+
+// DeleteJob cancels the job and removes its temp files when they are kept by this instance.
+func (svc *JobManager) DeleteJob(domainID, id int) (*model.Job, error) {
+	j, err := svc.idleJob(domainID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if j.Type == UploadJobName {
+		svc.uploader.cancel(j)
+	}
+
+	j, err = svc.jobStore.Cancel(domainID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// files of other instances are removed by their sweep
+	if j.Instance == svc.instance || svc.shared {
+		if err = svc.tempFile.DeleteFile(j.File); err != nil && !os.IsNotExist(err) {
+			svc.log.Error(err.Error(), wlog.Err(err), wlog.Int("job_id", id))
+		}
+	}
+
+	svc.events.jobFinished(j.File.SessionID)
+
+	return j, nil
+}
+
+func (svc *JobManager) idleJob(domainID, id int) (*model.Job, error) {
+	j, err := svc.jobStore.Get(domainID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if j.State == model.JobActive {
+		return nil, model.ErrJobActive
+	}
+
+	return j, nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func (s *fakeJobStore) Get(_, id int) (*model.Job, error) {
+	for _, j := range s.jobs {
+		if j.ID == id {
+			cp := *j
+
+			return &cp, nil
+		}
+	}
+
+	return nil, model.ErrJobNotFound
+}
+
+func (s *fakeJobStore) Restart(_ int, j *model.Job) (*model.Job, error) {
+	for i, v := range s.jobs {
+		if v.ID == j.ID {
+			s.jobs[i] = j
+
+			return j, nil
+		}
+	}
+
+	return nil, model.ErrJobNotFound
+}
+
+func TestJobManager_TranscodeJob(t *testing.T) {
+	dir := t.TempDir()
+	track := filepath.Join(dir, "track.raw")
+	transcoded := filepath.Join(dir, "out.mp4")
+
+	require.NoError(t, os.WriteFile(track, []byte("raw"), 0o644))
+	require.NoError(t, os.WriteFile(transcoded, []byte("mp4"), 0o644))
+
+	fjs := &fakeJobStore{
+		jobs: []*model.Job{
+			{ID: 1, Type: TranscodingJobName, Instance: "node-1", State: model.JobActive, File: &model.File{}},
+			{ID: 2, Type: TranscodingJobName, Instance: "node-2", File: &model.File{}},
+			{ID: 3, Type: TranscodingJobName, Instance: "node-1", File: &model.File{
+				Track: []model.MediaChannel{{Path: filepath.Join(dir, "missing.raw")}},
+			}},
+			// the raw tracks of the transcoded recording are removed
+			{ID: 4, Type: UploadJobName, Instance: "node-1", Config: &model.JobConfig{Sink: SinkLocal}, File: &model.File{
+				Path:     transcoded,
+				MimeType: "video/mp4",
+				Track:    []model.MediaChannel{{Path: track}},
+			}},
+			{ID: 5, Type: TranscodingJobName, Instance: "node-1", State: model.JobFailed, Config: &model.JobConfig{Sink: SinkLocal},
+				File: &model.File{Track: []model.MediaChannel{{Path: track}}},
+			},
+		},
+	}
+
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+	svc := &JobManager{log: log, jobStore: fjs, instance: "node-1"}
+
+	_, err := svc.TranscodeJob(1, 1)
+	require.ErrorIs(t, err, model.ErrJobActive)
+
+	_, err = svc.TranscodeJob(1, 2)
+	require.ErrorIs(t, err, model.ErrJobOtherInstance)
+
+	_, err = svc.TranscodeJob(1, 3)
+	require.ErrorIs(t, err, model.ErrJobNoTracks)
+
+	_, err = svc.TranscodeJob(1, 4)
+	require.ErrorIs(t, err, model.ErrJobNoTracks)
+	assert.FileExists(t, transcoded)
+
+	j, err := svc.TranscodeJob(1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, TranscodingJobName, j.Type)
+	assert.Equal(t, SinkLocal, j.Config.Sink)
+	assert.FileExists(t, track)
+}
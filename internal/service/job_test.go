@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/config"
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+type jobErrorCall struct {
+	state model.JobState
+	err   *model.JobError
+	delay time.Duration
+}
+
+type errorJobStore struct {
+	FileJobStore
+
+	calls []jobErrorCall
+}
+
+func (s *errorJobStore) SetError(_ *model.Job, state model.JobState, e *model.JobError, delay time.Duration) error {
+	s.calls = append(s.calls, jobErrorCall{state: state, err: e, delay: delay})
+
+	return nil
+}
+
+func TestJobHandler_errorJob(t *testing.T) {
+	dir := t.TempDir()
+	tmp := &TempFileService{dir: dir, quarantine: filepath.Join(dir, quarantineDir)}
+	require.NoError(t, os.MkdirAll(tmp.quarantine, 0o755))
+
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+	fjs := &errorJobStore{}
+	svc := &jobHandler{
+		jobStore: fjs,
+		log:      log,
+		tempFile: tmp,
+		events:   NewSessionEvents(log),
+		backoff: newBackoff(config.BackoffSettings{
+			Min:    time.Second,
+			Max:    time.Minute,
+			Factor: 2,
+		}),
+	}
+
+	track := filepath.Join(dir, "a.raw")
+	require.NoError(t, os.WriteFile(track, []byte("raw"), 0o644))
+
+	newJob := func(retry int) *baseJob {
+		return &baseJob{
+			log: log,
+			job: &model.Job{
+				ID:    1,
+				Type:  UploadJobName,
+				Retry: retry,
+				File:  &model.File{Track: []model.MediaChannel{{Path: track}}},
+			},
+		}
+	}
+
+	svc.errorJob(newJob(1), 3, errors.New("first"))
+	assert.FileExists(t, track)
+
+	svc.errorJob(newJob(3), 3, errors.New("last"))
+
+	require.Len(t, fjs.calls, 2)
+
+	assert.Equal(t, model.JobIdle, fjs.calls[0].state)
+	assert.Equal(t, time.Second, fjs.calls[0].delay)
+	assert.Equal(t, "first", fjs.calls[0].err.Error)
+	assert.Equal(t, 1, fjs.calls[0].err.Attempt)
+
+	assert.Equal(t, model.JobFailed, fjs.calls[1].state)
+	assert.Zero(t, fjs.calls[1].delay)
+	assert.NoFileExists(t, track)
+	assert.FileExists(t, filepath.Join(tmp.quarantine, "a.raw"))
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/config"
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+// Cluster tells which instances of the service are alive.
+type Cluster interface {
+	Instances() ([]string, error)
+}
+
+// JobLease keeps the jobs of the instance owned while it runs and takes over the jobs of the instances that are gone.
+type JobLease struct {
+	ctx      context.Context
+	log      *wlog.Logger
+	jobStore FileJobStore
+	cluster  Cluster
+	ttl      time.Duration
+	steal    bool
+	alive    atomic.Pointer[[]string]
+}
+
+func NewJobLease(ctx context.Context, cfg *config.Config, log *wlog.Logger, fjs FileJobStore, cl Cluster) (*JobLease, error) {
+	if cfg.Jobs.LeaseTTL <= 0 {
+		return nil, errors.New("jobs lease ttl must be positive")
+	}
+
+	// the files of the claimed jobs must be reachable by the same path
+	if cfg.Jobs.Steal && !cfg.SharedTemp {
+		return nil, errors.New("claiming jobs of other instances requires a shared temp dir")
+	}
+
+	l := &JobLease{
+		ctx:      ctx,
+		log:      log.With(wlog.String("service", "lease")),
+		jobStore: fjs,
+		cluster:  cl,
+		ttl:      cfg.Jobs.LeaseTTL,
+		steal:    cfg.Jobs.Steal,
+	}
+
+	l.refresh()
+
+	go l.renew()
+
+	return l, nil
+}
+
+func (l *JobLease) renew() {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C:
+			l.refresh()
+		}
+	}
+}
+
+// refresh extends the lease of the own jobs and reloads the alive instances.
+func (l *JobLease) refresh() {
+	if err := l.jobStore.Renew(); err != nil {
+		l.log.Error(err.Error(), wlog.Err(err))
+	}
+
+	if !l.steal {
+		return
+	}
+
+	ids, err := l.cluster.Instances()
+	if err != nil {
+		// without the cluster state only the expired leases are claimed
+		l.log.Error(err.Error(), wlog.Err(err))
+		ids = nil
+	}
+
+	l.alive.Store(&ids)
+}
+
+// claim returns up to limit jobs of the type taken over from other instances.
+func (l *JobLease) claim(limit int, jobType string) []*model.Job {
+	if !l.steal || limit <= 0 {
+		return nil
+	}
+
+	var alive []string
+	if ids := l.alive.Load(); ids != nil {
+		alive = *ids
+	}
+
+	jobs, err := l.jobStore.Claim(limit, jobType, alive)
+	if err != nil {
+		l.log.Error(err.Error(), wlog.Err(err))
+
+		return nil
+	}
+
+	for _, j := range jobs {
+		l.log.Info(fmt.Sprintf("claimed %s job %d of instance %s", j.Type, j.ID, j.Instance))
+	}
+
+	return jobs
+}
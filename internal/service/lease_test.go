@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/config"
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+type leaseJobStore struct {
+	FileJobStore
+
+	renewed int
+	alive   []string
+}
+
+func (s *leaseJobStore) Renew() error {
+	s.renewed++
+
+	return nil
+}
+
+func (s *leaseJobStore) Claim(limit int, jobType string, alive []string) ([]*model.Job, error) {
+	s.alive = alive
+
+	return []*model.Job{{ID: 1, Type: jobType, Instance: "gone"}}, nil
+}
+
+type staticCluster []string
+
+func (c staticCluster) Instances() ([]string, error) {
+	return c, nil
+}
+
+func TestJobLease_claim(t *testing.T) {
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+	cfg := &config.Config{Jobs: config.JobSettings{LeaseTTL: time.Minute, Steal: true}}
+
+	_, err := NewJobLease(context.Background(), cfg, log, &leaseJobStore{}, staticCluster{"1"})
+	require.Error(t, err, "steal without the shared temp dir")
+
+	cfg.SharedTemp = true
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fjs := &leaseJobStore{}
+	l, err := NewJobLease(ctx, cfg, log, fjs, staticCluster{"1", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, fjs.renewed)
+
+	assert.Empty(t, l.claim(0, UploadJobName))
+
+	jobs := l.claim(2, UploadJobName)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, []string{"1", "2"}, fjs.alive)
+
+	l.steal = false
+	assert.Empty(t, l.claim(2, UploadJobName))
+}
@@ -4,13 +4,19 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 	"github.com/pion/rtp/codecs"
 	"github.com/pion/webrtc/v4"
 	"github.com/pion/webrtc/v4/pkg/media"
 	"github.com/pion/webrtc/v4/pkg/media/h264writer"
+	"github.com/pion/webrtc/v4/pkg/media/h265writer"
 	"github.com/pion/webrtc/v4/pkg/media/ivfwriter"
 	"github.com/pion/webrtc/v4/pkg/media/oggwriter"
 	"github.com/pion/webrtc/v4/pkg/media/samplebuilder"
@@ -18,14 +24,65 @@ import (
 
 	"github.com/webitel/wlog"
 
+	webrtci "github.com/webitel/webrtc_recorder/infra/webrtc"
 	"github.com/webitel/webrtc_recorder/internal/model"
+	"github.com/webitel/webrtc_recorder/internal/utils"
 )
 
 type Track struct {
 	model.File
-	countPkg int
-	writer   io.WriteCloser `json:"-"`
-	encoder  media.Writer   `json:"-"`
+	mu         sync.Mutex
+	id         string
+	idx        int
+	codec      *webrtc.RTPCodecParameters
+	ssrc       webrtc.SSRC
+	countPkg   atomic.Int64
+	lostPkg    atomic.Int64
+	bytes      atomic.Int64
+	segmentPkg int
+	// clock is the last sender report of the track, guarded by the session mutex
+	clock *model.RtpClock
+	// lastTs and lastAt are the RTP timestamp and the arrival of the last packet, mediaMs is the media
+	// position of the segment, guarded by the session mutex
+	lastTs  uint32
+	lastAt  int
+	mediaMs int
+	// detached is the track of the failed connection waiting for the reconnect, gen counts the connections
+	// of the track and tsOffset moves the RTP timestamps of the reconnected sender to its timeline; guarded
+	// by the session mutex
+	detached bool
+	rebase   bool
+	gen      int
+	tsOffset uint32
+	// speechStart and speechAt are the start and the last voiced packet of the speech of the room audio,
+	// guarded by the session mutex
+	speechStart int
+	speechAt    int
+	writer      io.WriteCloser `json:"-"`
+	encoder     media.Writer   `json:"-"`
+}
+
+// countWriter counts the bytes written to the temp file of the track.
+type countWriter struct {
+	io.WriteCloser
+	n *atomic.Int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	w.n.Add(int64(n))
+
+	return n, err
+}
+
+// Seek lets the container writers patch their headers on close.
+func (w *countWriter) Seek(offset int64, whence int) (int64, error) {
+	s, ok := w.WriteCloser.(io.Seeker)
+	if !ok {
+		return 0, errNotSeekable
+	}
+
+	return s.Seek(offset, whence)
 }
 
 type RtcUploadMediaSession struct {
@@ -35,19 +92,32 @@ type RtcUploadMediaSession struct {
 	pc         *webrtc.PeerConnection
 	log        *wlog.Logger
 	fileConfig *model.File
+	jobConfig  *model.JobConfig
 	cancel     context.CancelFunc
 	ctx        context.Context
 	rec        *WebRtcRecorder
 	track      []*Track
 	tmp        *TempFileService
 	countTrack atomic.Int32
+	mu         sync.Mutex
+	segment    int
+	// candidates are the local candidates of the trickle ICE session, nil when the answer waits for the gathering
+	candidates *iceCandidates
+	// grace finalizes the disconnected session unless it reconnects, guarded by mu
+	grace *time.Timer
+	// room is the recording the session joined, nil for the own one
+	room *room
 }
 
-func NewWebRtcUploadSession(rec *WebRtcRecorder, pc *webrtc.PeerConnection, file *model.File) *RtcUploadMediaSession {
+func NewWebRtcUploadSession(rec *WebRtcRecorder, pc *webrtc.PeerConnection, file *model.File, cfg *model.JobConfig,
+	trickle bool,
+) *RtcUploadMediaSession {
 	id := model.NewID()
+	file.SessionID = id
 	session := &RtcUploadMediaSession{
 		id:         id,
 		fileConfig: file,
+		jobConfig:  cfg,
 		rec:        rec,
 		pc:         pc,
 		log:        rec.log.With(wlog.String("session", id)),
@@ -55,195 +125,1022 @@ func NewWebRtcUploadSession(rec *WebRtcRecorder, pc *webrtc.PeerConnection, file
 	}
 
 	session.ctx, session.cancel = context.WithCancel(context.Background())
-	pc.OnTrack(session.onTrack)
-	pc.OnDataChannel(session.onDataChannel)
-	pc.OnICEConnectionStateChange(session.onICEConnectionStateChange)
+	rec.events.open(id, file.DomainID)
+
+	if trickle {
+		session.candidates = newIceCandidates()
+	}
+
+	session.watchPeer(pc, session.candidates)
+
+	// the participants of the room record till they leave, the room is stored as a whole
+	if rec.segment > 0 && file.RoomID == "" {
+		go session.segmentLoop(rec.segment)
+	}
 
 	return session
 }
 
-func (s RtcUploadMediaSession) onDataChannel(dataChannel *webrtc.DataChannel) {
-	s.log.Debug(fmt.Sprintf("new DataChannel %s %d", dataChannel.Label(), dataChannel.ID()))
-	t := Track{
-		File:    *s.fileConfig,
-		writer:  nil,
-		encoder: nil,
+// watchPeer handles the events of the peer connection of the session, the local candidates of the trickle
+// connection are collected into candidates.
+func (s *RtcUploadMediaSession) watchPeer(pc *webrtc.PeerConnection, candidates *iceCandidates) {
+	pc.OnTrack(s.onTrack)
+	pc.OnDataChannel(s.onDataChannel)
+	pc.OnICEConnectionStateChange(func(state webrtc.ICEConnectionState) {
+		s.onICEConnectionStateChange(pc, state)
+	})
+
+	if candidates != nil {
+		pc.OnICECandidate(candidates.add)
 	}
-	var err error
+}
 
-	t.writer, err = s.rec.temp.NewWriter(&t.File, "raw")
-	if err != nil {
-		// TODO
-		s.log.Error(err.Error(), wlog.Err(err))
-		return
+func (s *RtcUploadMediaSession) onDataChannel(dataChannel *webrtc.DataChannel) {
+	s.log.Debug(fmt.Sprintf("new DataChannel %s %d", dataChannel.Label(), dataChannel.ID()))
+
+	t := s.reattach(dataChannel.Label(), model.DataMimeType, nil)
+	if t == nil {
+		var err error
+		if t, err = s.addTrack(dataChannel.Label(), model.DataMimeType, nil); err != nil {
+			// TODO
+			s.log.Error(err.Error(), wlog.Err(err))
+			return
+		}
 	}
-	t.Path = t.File.Path
 
-	s.track = append(s.track, &t)
-	s.fileConfig.Track = append(s.fileConfig.Track, model.MediaChannel{
-		Path:     t.Path,
-		MimeType: dataChannel.Label(),
-	})
+	s.mu.Lock()
+	gen := t.gen
+	s.mu.Unlock()
 
 	s.countTrack.Add(1)
 
+	dataChannel.OnOpen(func() {
+		s.rec.events.Publish(&model.SessionEvent{
+			SessionID:   s.id,
+			Type:        model.EventDataChannelOpened,
+			DataChannel: dataChannel.Label(),
+		})
+	})
+
 	dataChannel.OnClose(func() {
 		s.log.Debug(fmt.Sprintf("close DataChannel %s", dataChannel.Label()))
 		s.countTrack.Add(-1)
+		s.leave(t, gen)
 	})
 
 	dataChannel.OnMessage(func(msg webrtc.DataChannelMessage) {
-		t.writer.Write(msg.Data)
+		s.onDataMessage(t, msg.Data)
 	})
 }
 
-func (s *RtcUploadMediaSession) onTrack(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
-	codec := track.Codec()
-	t := Track{
-		File:    *s.fileConfig,
-		writer:  nil,
-		encoder: nil,
+// onDataMessage records the message of the data channel as a JSON line of its track,
+// the messages out of the protocol are counted as lost.
+func (s *RtcUploadMediaSession) onDataMessage(t *Track, data []byte) {
+	t.countPkg.Inc()
+
+	msg, err := model.ParseDataMessage(data)
+	if err != nil {
+		t.lostPkg.Inc()
+		s.log.Debug(err.Error(), wlog.String("channel", t.id))
+
+		return
 	}
 
-	var (
-		err error
-		pkt rtp.Depacketizer
-	)
+	msg.Channel = t.id
+
+	s.mu.Lock()
+	if s.fileConfig.Paused() {
+		s.mu.Unlock()
 
-	t.writer, err = s.rec.temp.NewWriter(&t.File, "raw")
-	if err != nil {
-		// TODO
-		s.log.Error(err.Error(), wlog.Err(err))
 		return
 	}
-	s.track = append(s.track, &t)
-	s.fileConfig.Track = append(s.fileConfig.Track, model.MediaChannel{
+
+	if t.idx < len(s.fileConfig.Track) && s.fileConfig.Track[t.idx].FirstPacketAt == 0 {
+		s.fileConfig.Track[t.idx].FirstPacketAt = model.GetMillis()
+	}
+	s.mu.Unlock()
+
+	if msg.Type == model.DataMarker {
+		if _, err = s.AddMarker(msg.Label, msg.Timestamp); err != nil {
+			s.log.Warn(err.Error(), wlog.Err(err))
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.writer != nil {
+		t.segmentPkg++
+		_, _ = t.writer.Write(append(msg.JSON(), '\n'))
+	}
+}
+
+// AddMarker records the marker of the recording at the session clock ts (unix ms), zero ts is now.
+func (s *RtcUploadMediaSession) AddMarker(label string, ts int) (*model.Marker, error) {
+	m, err := model.NewMarker(label, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.fileConfig.Markers = append(s.fileConfig.Markers, *m)
+	s.saveManifest()
+	s.mu.Unlock()
+
+	s.log.Debug(fmt.Sprintf("marker %q at %d", m.Label, m.Time))
+	s.rec.events.Publish(&model.SessionEvent{
+		SessionID: s.id,
+		Type:      model.EventMarkerAdded,
+		Marker:    m,
+	})
+
+	return m, nil
+}
+
+// addTrack registers a new channel of the recording and opens its temp file.
+func (s *RtcUploadMediaSession) addTrack(id, mimeType string, codec *webrtc.RTPCodecParameters) (*Track, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := &Track{
+		File:  *s.fileConfig,
+		id:    id,
+		codec: codec,
+	}
+	t.Track = nil
+	t.MimeType = mimeType
+
+	if err := t.open(s.rec.temp); err != nil {
+		return nil, err
+	}
+
+	t.idx = len(s.fileConfig.Track)
+	s.track = append(s.track, t)
+	ch := model.MediaChannel{
 		Path:     t.Path,
-		MimeType: codec.MimeType,
+		MimeType: t.MimeType,
+	}
+	if codec != nil {
+		ch.ClockRate = codec.ClockRate
+	} else {
+		ch.Label = id
+	}
+
+	if s.fileConfig.RoomID != "" {
+		ch.Peer, ch.Participant = s.id, s.fileConfig.Participant
+	}
+
+	s.fileConfig.Track = append(s.fileConfig.Track, ch)
+
+	s.saveManifest()
+
+	return t, nil
+}
+
+// saveManifest stores the current state of the recording for the crash recovery, s.mu must be held.
+func (s *RtcUploadMediaSession) saveManifest() {
+	err := s.rec.temp.SaveManifest(&model.SessionManifest{
+		ID:       s.id,
+		Instance: s.rec.instance,
+		File:     s.fileConfig,
+		Config:   s.jobConfig,
 	})
+	if err != nil {
+		s.log.Error(err.Error(), wlog.Err(err))
+	}
+}
+
+// open creates a new temp file of the track and the container writer for its codec.
+func (t *Track) open(tmp *TempFileService) error {
+	var err error
+
+	t.Path = ""
+	t.segmentPkg = 0
+
+	ext := "raw"
+	if t.codec == nil {
+		ext = "jsonl"
+	}
+
+	w, err := tmp.NewWriter(&t.File, ext)
+	if err != nil {
+		return err
+	}
+
+	t.writer = &countWriter{WriteCloser: w, n: &t.bytes}
+
+	if t.codec == nil {
+		return nil
+	}
+
+	switch t.codec.MimeType {
+	case webrtc.MimeTypeVP8, webrtc.MimeTypeVP9, webrtc.MimeTypeAV1:
+		t.encoder, err = ivfwriter.NewWith(t.writer, ivfwriter.WithCodec(t.codec.MimeType))
+	case webrtc.MimeTypeH264:
+		t.encoder = h264writer.NewWith(t.writer)
+	case webrtc.MimeTypeH265:
+		t.encoder = h265writer.NewWith(t.writer)
+	case webrtc.MimeTypeOpus:
+		t.encoder, err = oggwriter.NewWith(t.writer, t.codec.ClockRate, t.codec.Channels)
+	case webrtc.MimeTypePCMU, webrtc.MimeTypePCMA:
+		t.encoder, err = NewWAVWriter(t.writer, utils.NewG711Decoder(t.codec.MimeType == webrtc.MimeTypePCMA,
+			int(t.codec.ClockRate)), 1)
+	case webrtc.MimeTypeG722:
+		t.encoder, err = NewWAVWriter(t.writer, utils.NewG722Decoder(), 1)
+	default:
+		err = fmt.Errorf("unsupported codec: %s", t.codec.MimeType)
+	}
+
+	if err != nil {
+		_ = t.writer.Close()
+		t.writer = nil
+	}
+
+	return err
+}
+
+// close flushes the container writer and the temp file of the track.
+func (t *Track) close() error {
+	var err error
+
+	if t.encoder != nil {
+		if closeErr := t.encoder.Close(); closeErr != nil {
+			err = fmt.Errorf("closing encoder: %w", closeErr)
+		}
+
+		t.encoder = nil
+	}
+
+	if t.writer != nil {
+		if closeErr := t.writer.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("closing writer: %w", closeErr)
+		}
+
+		t.writer = nil
+	}
+
+	return err
+}
+
+func (s *RtcUploadMediaSession) onTrack(track *webrtc.TrackRemote, receiver *webrtc.RTPReceiver) {
+	codec := track.Codec()
+
+	var pkt rtp.Depacketizer
+
+	switch codec.MimeType {
+	case webrtc.MimeTypeVP8:
+		pkt = &codecs.VP8Packet{}
+	case webrtc.MimeTypeVP9:
+		pkt = &codecs.VP9Packet{}
+	case webrtc.MimeTypeAV1:
+		pkt = &codecs.AV1Depacketizer{}
+	case webrtc.MimeTypeH264:
+		pkt = &codecs.H264Packet{}
+	case webrtc.MimeTypeH265:
+		pkt = &codecs.H265Packet{}
+	case webrtc.MimeTypeOpus:
+		pkt = &codecs.OpusPacket{}
+	case webrtc.MimeTypePCMU, webrtc.MimeTypePCMA, webrtc.MimeTypeG722:
+		pkt = &sampleDepacketizer{}
+	default:
+		s.log.Error(fmt.Sprintf("unsupported codec: %s", codec.MimeType))
+		return // TODO
+	}
+
+	var err error
+
+	// the sender of the reconnected client continues the file of its track
+	t := s.reattach(track.ID(), codec.MimeType, &codec)
+	if t == nil {
+		t, err = s.addTrack(track.ID(), codec.MimeType, &codec)
+		if err != nil {
+			// TODO
+			s.log.Error(err.Error(), wlog.Err(err))
+			return
+		}
+
+		s.rec.events.Publish(&model.SessionEvent{
+			SessionID: s.id,
+			Type:      model.EventTrackAdded,
+			Track:     t.info(),
+		})
+	}
+
+	s.mu.Lock()
+	t.ssrc = track.SSRC()
+	gen := t.gen
+	// the layout places the screen share and the cameras by their msid
+	if strings.HasPrefix(codec.MimeType, "video") {
+		s.fileConfig.Track[t.idx].Role = model.TrackRole(track.StreamID(), track.ID())
+	}
+	s.mu.Unlock()
+
+	// the levels of the room audio find the active speaker
+	var levelID uint8
+	if s.fileConfig.RoomID != "" && strings.HasPrefix(codec.MimeType, "audio") {
+		levelID = audioLevelID(receiver)
+	}
+
+	go s.readRTCP(t, receiver)
 
 	s.log.Debug(fmt.Sprintf("got %s: %s@%d track, saving as %s", track.ID(), codec.MimeType, codec.ClockRate, t.Path))
+	s.mu.Lock()
 	if s.fileConfig.StartTime == 0 {
 		s.fileConfig.StartTime = model.GetMillis()
+		s.saveManifest()
 	}
+	s.mu.Unlock()
 
 	s.countTrack.Add(1)
 
 	defer func() {
 		s.countTrack.Add(-1)
+		if s.replaced(t, gen) || s.reconnecting() {
+			// the session is finalized by the grace period unless the client reconnects
+			s.log.Debug(fmt.Sprintf("reader of track %s of the lost connection stopped", t.id))
+			return
+		}
+
+		// the sender removed by the renegotiation, the session goes on with the other tracks
+		s.leave(t, gen)
+
 		s.log.Debug("closing writer")
 		s.close()
 	}()
 
-	switch codec.MimeType {
-	case webrtc.MimeTypeVP9:
-		t.MimeType = codec.MimeType
-		pkt = &codecs.VP9Packet{}
+	var (
+		rtpPacket *rtp.Packet
+		sample    *media.Sample
+		lsn       uint16 = 0
+	)
 
-		t.encoder, err = ivfwriter.NewWith(t.writer, ivfwriter.WithCodec(codec.MimeType))
-		if err != nil {
-			s.log.Error(fmt.Sprintf("failed to open ivf file: %s", err))
+	builder := samplebuilder.New(45, pkt, codec.ClockRate,
+		samplebuilder.WithRTPHeaders(true),
+		samplebuilder.WithPacketReleaseHandler(func(pkt *rtp.Packet) {
+			if lsn != 0 && pkt.SequenceNumber != lsn+1 {
+				s.log.Error(fmt.Sprintf("lost packets packet seq=%d, last=%d, count=%d", pkt.SequenceNumber,
+					lsn, pkt.SequenceNumber-(lsn+1)))
+				t.lostPkg.Add(int64(pkt.SequenceNumber - (lsn + 1)))
+			}
+
+			lsn = pkt.SequenceNumber
+
+			ts, ok := s.rtpTs(t, gen, pkt.Timestamp)
+			if !ok {
+				// the late packet of the replaced connection
+				return
+			}
+
+			pkt.Timestamp = ts
+
+			gap, write := s.setRtpTs(t, pkt.Timestamp)
+			if gap != nil {
+				s.log.Debug(fmt.Sprintf("track %s gap at %dms for %dms", t.id, gap.At, gap.Duration))
+				s.rec.events.Publish(&model.SessionEvent{
+					SessionID: s.id,
+					Type:      model.EventTrackGap,
+					Track:     t.info(),
+					Gap:       gap,
+				})
+			}
+
+			if !write {
+				// the recording is paused
+				return
+			}
+
+			if levelID != 0 {
+				s.speech(t, pkt, levelID)
+			}
+
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			if t.encoder == nil {
+				return
+			}
+
+			t.segmentPkg++
+
+			if err = t.encoder.WriteRTP(pkt); err != nil {
+				s.log.Error(fmt.Sprintf("failed to write rtp packet: %s", err))
+				s.cancel()
+			}
+		}),
+	)
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.log.Debug("context canceled, stopping rtp reader loop")
 
 			return
+		default:
+			rtpPacket, _, err = track.ReadRTP()
+			if err != nil {
+				if err != io.EOF {
+					s.log.Error(fmt.Sprintf("unhandled error reading rtp packet: %s", err))
+				}
+
+				return
+			}
+
+			if cnt := t.countPkg.Inc(); cnt%1000 == 0 {
+				s.log.Debug(fmt.Sprintf("receive rtc (%s) packet count %d", track.ID(), cnt))
+			}
+
+			builder.Push(rtpPacket)
+
+			for sample = builder.Pop(); sample != nil; sample = builder.Pop() {
+				// if _, err = wd.Write(sample.Data); err != nil {
+				//	log.Error(fmt.Sprintf("failed to write rtp packet: %s", err))
+				//	cancel()
+				//	return
+				//}
+			}
 		}
+	}
+}
 
-	case webrtc.MimeTypeH264:
-		t.MimeType = codec.MimeType
-		pkt = &codecs.H264Packet{}
-		t.encoder = h264writer.NewWith(t.writer)
-	case webrtc.MimeTypeOpus:
-		t.MimeType = codec.MimeType
-		pkt = &codecs.OpusPacket{}
-		t.encoder, _ = oggwriter.NewWith(t.writer, codec.ClockRate, codec.Channels)
+const (
+	// speechLevel is the audio level of the voice, -dBov
+	speechLevel = 50
+	// speechHangover is the silence that ends the speech, ms
+	speechHangover = 800
+)
 
-	default:
-		s.log.Error(fmt.Sprintf("unsupported codec: %s", codec.MimeType))
-		return // TODO
+// audioLevelID is the negotiated id of the audio level extension of the receiver, 0 when the sender has none.
+func audioLevelID(receiver *webrtc.RTPReceiver) uint8 {
+	for _, ext := range receiver.GetParameters().HeaderExtensions {
+		if ext.URI == webrtci.AudioLevelURI {
+			return uint8(ext.ID) //nolint:gosec
+		}
 	}
 
-	if t.encoder != nil {
-		var (
-			rtpPacket *rtp.Packet
-			sample    *media.Sample
-			lsn       uint16 = 0
-			isVideo          = strings.HasPrefix(codec.MimeType, "video")
-		)
-
-		trackIdx := len(s.fileConfig.Track) - 1
-
-		builder := samplebuilder.New(45, pkt, codec.ClockRate,
-			samplebuilder.WithRTPHeaders(true),
-			samplebuilder.WithPacketReleaseHandler(func(pkt *rtp.Packet) {
-				if lsn != 0 && pkt.SequenceNumber != lsn+1 {
-					s.log.Error(fmt.Sprintf("lost packets packet seq=%d, last=%d, count=%d", pkt.SequenceNumber,
-						lsn, pkt.SequenceNumber-(lsn+1)))
-				}
+	return 0
+}
 
-				lsn = pkt.SequenceNumber
+// speech tracks the voice activity of the audio track by the level of its packets.
+func (s *RtcUploadMediaSession) speech(t *Track, pkt *rtp.Packet, id uint8) {
+	ext := pkt.GetExtension(id)
+	if ext == nil {
+		return
+	}
 
-				if isVideo && trackIdx < len(s.fileConfig.Track) {
-					ch := &s.fileConfig.Track[trackIdx]
-					if ch.FirstRtpTs == 0 {
-						ch.FirstRtpTs = pkt.Timestamp
-					}
-					ch.LastRtpTs = pkt.Timestamp
-				}
+	var level rtp.AudioLevelExtension
+	if err := level.Unmarshal(ext); err != nil {
+		return
+	}
 
-				if err = t.encoder.WriteRTP(pkt); err != nil {
-					s.log.Error(fmt.Sprintf("failed to write rtp packet: %s", err))
-					s.cancel()
-				}
-			}),
-		)
+	now := model.GetMillis()
 
-		for {
-			select {
-			case <-s.ctx.Done():
-				s.log.Debug("context canceled, stopping rtp reader loop")
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-				return
-			default:
-				rtpPacket, _, err = track.ReadRTP()
-				if err != nil {
-					if err != io.EOF {
-						s.log.Error(fmt.Sprintf("unhandled error reading rtp packet: %s", err))
-					}
-
-					return
-				}
+	if level.Level <= speechLevel {
+		if t.speechStart == 0 {
+			t.speechStart = now
+		}
 
-				t.countPkg++
+		t.speechAt = now
 
-				if t.countPkg%1000 == 0 {
-					s.log.Debug(fmt.Sprintf("receive rtc (%s) packet count %d", track.ID(), t.countPkg))
-				}
+		return
+	}
+
+	if t.speechStart > 0 && now-t.speechAt > speechHangover {
+		s.endSpeech(t)
+	}
+}
+
+// endSpeech adds the speech to the channel of the track, s.mu must be held.
+func (s *RtcUploadMediaSession) endSpeech(t *Track) {
+	if t.idx < len(s.fileConfig.Track) {
+		ch := &s.fileConfig.Track[t.idx]
+		ch.Speech = append(ch.Speech, model.Speech{Start: t.speechStart, End: t.speechAt})
+	}
+
+	t.speechStart = 0
+}
+
+// flushSpeech ends the speech of the closed session, s.mu must be held.
+func (s *RtcUploadMediaSession) flushSpeech() {
+	for _, t := range s.track {
+		if t.speechStart > 0 {
+			s.endSpeech(t)
+		}
+	}
+}
+
+// leave marks the track removed from the session by the renegotiation, its file stays in the recording and
+// the compositor drops it from the layout. The tracks of the closed or the lost connection do not leave.
+func (s *RtcUploadMediaSession) leave(t *Track, gen int) {
+	if s.ctx.Err() != nil || s.reconnecting() {
+		return
+	}
+
+	s.mu.Lock()
+	if t.detached || t.gen != gen || t.idx >= len(s.fileConfig.Track) {
+		s.mu.Unlock()
+
+		return
+	}
+
+	s.fileConfig.Track[t.idx].LeftAt = model.GetMillis()
+	s.saveManifest()
+	s.mu.Unlock()
+
+	s.log.Debug(fmt.Sprintf("track %s left the session", t.id))
+	s.rec.events.Publish(&model.SessionEvent{
+		SessionID: s.id,
+		Type:      model.EventTrackRemoved,
+		Track:     t.info(),
+	})
+}
+
+// rtpTs moves the timestamp of the reconnected sender to the timeline of the track, so the outage becomes its gap.
+// It returns false for the packet of the replaced connection.
+func (s *RtcUploadMediaSession) rtpTs(t *Track, gen int, ts uint32) (uint32, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.detached || t.gen != gen {
+		return 0, false
+	}
+
+	if t.rebase {
+		t.rebase = false
+		t.tsOffset = 0
+
+		if t.lastAt > 0 && t.codec != nil {
+			elapsed := int64(model.GetMillis()-t.lastAt) * int64(t.codec.ClockRate) / 1000
+			t.tsOffset = t.lastTs + uint32(elapsed) - ts //nolint:gosec
+		}
+	}
 
-				builder.Push(rtpPacket)
+	return ts + t.tsOffset, true
+}
+
+// replaced tells the reader of the track belongs to the replaced connection.
+func (s *RtcUploadMediaSession) replaced(t *Track, gen int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return t.detached || t.gen != gen
+}
+
+// reattach binds the sender of the reconnected client to the detached track of the same codec, the data
+// channel to the one of the same label. It returns nil when there is no such track.
+func (s *RtcUploadMediaSession) reattach(id, mimeType string, codec *webrtc.RTPCodecParameters) *Track {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.track {
+		if !t.detached || !strings.EqualFold(t.MimeType, mimeType) {
+			continue
+		}
+
+		if codec == nil && t.id != id {
+			continue
+		}
+
+		if codec != nil && (t.codec == nil || t.codec.ClockRate != codec.ClockRate || t.codec.Channels != codec.Channels) {
+			continue
+		}
+
+		s.log.Debug(fmt.Sprintf("reattach %s to track %s", id, t.id))
+		t.detached = false
+		t.rebase = codec != nil
+		t.gen++
+
+		return t
+	}
+
+	return nil
+}
+
+// setRtpTs tracks the timing of the channel and returns the gap the packet ends,
+// the packets of the paused recording are not written.
+func (s *RtcUploadMediaSession) setRtpTs(t *Track, ts uint32) (*model.Gap, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fileConfig.Paused() {
+		return nil, false
+	}
+
+	if t.idx >= len(s.fileConfig.Track) {
+		return nil, true
+	}
+
+	now := model.GetMillis()
+	ch := &s.fileConfig.Track[t.idx]
+	delta := 0
+
+	if ch.ClockRate > 0 {
+		delta = int(int64(int32(ts-t.lastTs)) * 1000 / int64(ch.ClockRate)) //nolint:gosec
+	}
+
+	defer func() {
+		ch.LastRtpTs = ts
+		t.lastTs, t.lastAt = ts, now
+	}()
+
+	if ch.FirstPacketAt == 0 {
+		ch.FirstRtpTs = ts
+		ch.FirstPacketAt = now
+
+		return nil, true
+	}
+
+	threshold := s.rec.gap
+	redacted := s.fileConfig.PausedSince(t.lastAt)
+
+	if redacted {
+		// any pause of the recording is the gap
+		threshold = 1
+	}
+
+	d := rtpGap(delta, now-t.lastAt, threshold)
+	if d == 0 {
+		t.mediaMs += delta
+
+		return nil, true
+	}
+
+	gap := model.Gap{At: t.mediaMs, Duration: d, Redacted: redacted}
+	ch.Gaps = append(ch.Gaps, gap)
+
+	return &gap, true
+}
+
+// redactTail adds the paused end of the track to its channel, s.mu must be held.
+func (s *RtcUploadMediaSession) redactTail(t *Track, ch *model.MediaChannel, now int) {
+	if !s.fileConfig.Paused() || ch.FirstPacketAt == 0 || ch.ClockRate == 0 {
+		return
+	}
+
+	d := now - t.lastAt
+	if d <= 0 {
+		return
+	}
+
+	ch.Gaps = append(ch.Gaps, model.Gap{At: t.mediaMs, Duration: d, Redacted: true})
+	// the end of the track is the end of the pause
+	ch.LastRtpTs += uint32(int64(d) * int64(ch.ClockRate) / 1000) //nolint:gosec
+}
+
+// Pause stops writing the samples, the tracks keep their timing so the paused interval is redacted
+// in the recording. It returns false when the recording is already paused.
+func (s *RtcUploadMediaSession) Pause() bool {
+	s.mu.Lock()
+	if s.fileConfig.Paused() {
+		s.mu.Unlock()
+
+		return false
+	}
+
+	s.fileConfig.Pauses = append(s.fileConfig.Pauses, model.Pause{Start: model.GetMillis()})
+	s.saveManifest()
+	s.mu.Unlock()
+
+	s.log.Debug("recording paused")
+	s.rec.events.Publish(&model.SessionEvent{
+		SessionID: s.id,
+		Type:      model.EventRecordingPaused,
+	})
+
+	return true
+}
+
+// Resume continues writing the samples of the paused recording, it returns false when the recording is not paused.
+func (s *RtcUploadMediaSession) Resume() bool {
+	s.mu.Lock()
+	if !s.fileConfig.Paused() {
+		s.mu.Unlock()
+
+		return false
+	}
+
+	s.fileConfig.Pauses[len(s.fileConfig.Pauses)-1].End = model.GetMillis()
+	s.saveManifest()
+	s.mu.Unlock()
 
-				for sample = builder.Pop(); sample != nil; sample = builder.Pop() {
-					// if _, err = wd.Write(sample.Data); err != nil {
-					//	log.Error(fmt.Sprintf("failed to write rtp packet: %s", err))
-					//	cancel()
-					//	return
-					//}
+	s.log.Debug("recording resumed")
+	s.rec.events.Publish(&model.SessionEvent{
+		SessionID: s.id,
+		Type:      model.EventRecordingResumed,
+	})
+
+	return true
+}
+
+// closePause ends the pause of the stopped recording, s.mu must be held.
+func (s *RtcUploadMediaSession) closePause(now int) {
+	if !s.fileConfig.Paused() {
+		return
+	}
+
+	for _, t := range s.track {
+		if t.idx < len(s.fileConfig.Track) {
+			s.redactTail(t, &s.fileConfig.Track[t.idx], now)
+		}
+	}
+
+	s.fileConfig.Pauses[len(s.fileConfig.Pauses)-1].End = now
+}
+
+// maxRtpJump is the longest pause of the sender clock, the larger jumps are the discontinuities of the timestamps.
+const maxRtpJump = 6 * 60 * 60 * 1000
+
+// rtpGap returns the pause between the packets that is not in the media, 0 when it is shorter than the threshold.
+// The RTP clock runs through the pauses of the sender, the arrival time covers the sender clock discontinuities only,
+// so the network jitter is not mistaken for the pause.
+func rtpGap(tsMs, arrivalMs, threshold int) int {
+	if threshold <= 0 {
+		return 0
+	}
+
+	gap := tsMs
+	if tsMs < 0 || tsMs > maxRtpJump {
+		gap = arrivalMs
+	}
+
+	if gap < threshold {
+		return 0
+	}
+
+	return gap
+}
+
+// readRTCP maps the RTP clock of the track to the wall clock from the sender reports until the receiver stops.
+func (s *RtcUploadMediaSession) readRTCP(t *Track, receiver *webrtc.RTPReceiver) {
+	for {
+		pkts, _, err := receiver.ReadRTCP()
+		if err != nil {
+			return
+		}
+
+		for _, p := range pkts {
+			sr, ok := p.(*rtcp.SenderReport)
+			if !ok {
+				continue
+			}
+
+			s.mu.Lock()
+			// the reports of the reconnected sender wait for the offset of its timestamps
+			if sr.SSRC == uint32(t.ssrc) && !t.rebase && !t.detached {
+				t.clock = &model.RtpClock{
+					RtpTs:      sr.RTPTime + t.tsOffset,
+					Time:       ntpToMillis(sr.NTPTime),
+					ReceivedAt: model.GetMillis(),
 				}
+				if t.idx < len(s.fileConfig.Track) {
+					s.fileConfig.Track[t.idx].AddClock(*t.clock)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// ntpToMillis converts the 64-bit NTP timestamp to unix milliseconds.
+func ntpToMillis(ntp uint64) int {
+	const ntpEpochOffset = 2208988800
+
+	sec := int(ntp>>32) - ntpEpochOffset
+	frac := (ntp & 0xFFFFFFFF) * 1000 >> 32
+
+	return sec*1000 + int(frac) //nolint:gosec
+}
+
+// segmentLoop rolls the tracks over to new files, so a crash loses at most the current segment.
+func (s *RtcUploadMediaSession) segmentLoop(d time.Duration) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if f := s.rotate(); f != nil {
+				s.rec.storeSegment(s, f)
+			}
+
+			s.mu.Lock()
+			if s.ctx.Err() == nil {
+				s.saveManifest()
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// rotate closes the current segment and returns its file, nil when nothing was recorded.
+func (s *RtcUploadMediaSession) rotate() *model.File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil || s.fileConfig.StartTime == 0 || len(s.track) == 0 {
+		return nil
+	}
+
+	now := model.GetMillis()
+	s.segment++
+
+	segment := *s.fileConfig
+	segment.Track = make([]model.MediaChannel, 0, len(s.track))
+	segment.Segment = s.segment
+	segment.EndTime = now
+
+	for _, t := range s.track {
+		t.mu.Lock()
+
+		if t.writer == nil {
+			t.mu.Unlock()
+
+			continue
+		}
+
+		ch := s.fileConfig.Track[t.idx]
+		empty := t.segmentPkg == 0
+		s.redactTail(t, &ch, now)
+
+		if err := t.close(); err != nil {
+			s.log.Error(err.Error(), wlog.Err(err))
+		}
+
+		if empty {
+			_ = os.Remove(ch.Path)
+		} else {
+			segment.Track = append(segment.Track, ch)
+		}
+
+		if err := t.open(s.rec.temp); err != nil {
+			s.log.Error(err.Error(), wlog.Err(err))
+		}
+
+		next := model.MediaChannel{
+			Path:      t.Path,
+			MimeType:  ch.MimeType,
+			Label:     ch.Label,
+			ClockRate: ch.ClockRate,
+			Role:      ch.Role,
+		}
+		// the last sender report maps the next segment until a new one arrives
+		if t.clock != nil {
+			next.Clock = []model.RtpClock{*t.clock}
+		}
+
+		s.fileConfig.Track[t.idx] = next
+		t.mediaMs = 0
+
+		t.mu.Unlock()
+
+		if t.codec != nil && strings.HasPrefix(t.codec.MimeType, "video") {
+			// start the next segment from a key frame
+			if err := s.pc.WriteRTCP([]rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: uint32(t.ssrc)}}); err != nil {
+				s.log.Error(err.Error(), wlog.Err(err))
 			}
 		}
 	}
+
+	if s.fileConfig.Paused() {
+		// the pause goes on in the next segment
+		segment.Pauses = slices.Clone(s.fileConfig.Pauses)
+		segment.Pauses[len(segment.Pauses)-1].End = now
+		s.fileConfig.Pauses = []model.Pause{{Start: now}}
+	} else {
+		s.fileConfig.Pauses = nil
+	}
+
+	// the markers of the next segment
+	s.fileConfig.Markers = nil
+	s.fileConfig.StartTime = now
+	s.fileConfig.Segment = s.segment + 1
+
+	if len(segment.Track) == 0 {
+		return nil
+	}
+
+	return &segment
 }
 
-func (s *RtcUploadMediaSession) onICEConnectionStateChange(connectionState webrtc.ICEConnectionState) {
+func (s *RtcUploadMediaSession) onICEConnectionStateChange(pc *webrtc.PeerConnection, connectionState webrtc.ICEConnectionState) {
+	if s.peer() != pc {
+		// the connection replaced by the reconnect
+		return
+	}
+
 	s.log.Debug(fmt.Sprintf("connection state has changed to %s", connectionState.String()))
 
+	s.rec.events.Publish(&model.SessionEvent{
+		SessionID: s.id,
+		Type:      model.EventIceStateChanged,
+		ICEState:  connectionState.String(),
+	})
+
 	switch connectionState { //nolint:exhaustive
+	case webrtc.ICEConnectionStateConnected, webrtc.ICEConnectionStateCompleted:
+		s.stopGrace()
+	case webrtc.ICEConnectionStateDisconnected:
+		s.startGrace()
 	case webrtc.ICEConnectionStateFailed:
-		s.countTrack.Store(0) // TODO
-		s.close()
+		if !s.startGrace() {
+			s.countTrack.Store(0) // TODO
+			s.close()
+		}
 	default:
 
 	}
 }
 
+func (s *RtcUploadMediaSession) peer() *webrtc.PeerConnection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pc
+}
+
+// startGrace gives the disconnected client the time to restart ICE or to reconnect with the same uuid,
+// it returns false when the grace period is disabled.
+func (s *RtcUploadMediaSession) startGrace() bool {
+	if s.rec.reconnect <= 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.grace == nil {
+		s.log.Debug(fmt.Sprintf("wait reconnect %s", s.rec.reconnect))
+		s.grace = time.AfterFunc(s.rec.reconnect, s.expire)
+	}
+
+	return true
+}
+
+func (s *RtcUploadMediaSession) stopGrace() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.grace != nil {
+		s.grace.Stop()
+		s.grace = nil
+	}
+}
+
+// expire finalizes the session that did not reconnect in the grace period.
+func (s *RtcUploadMediaSession) expire() {
+	s.log.Debug("reconnect grace period expired")
+	s.countTrack.Store(0)
+	s.close()
+}
+
+// reconnect replaces the failed peer connection of the session in the grace period, the senders of the new
+// connection continue the files of the detached tracks. It returns false when the grace period is over.
+func (s *RtcUploadMediaSession) reconnect(pc *webrtc.PeerConnection, sdpOffer string, trickle bool) (bool, error) {
+	s.mu.Lock()
+	if s.grace == nil || !s.grace.Stop() {
+		s.mu.Unlock()
+
+		return false, nil
+	}
+
+	s.grace = nil
+	old := s.pc
+	s.pc = pc
+
+	for _, t := range s.track {
+		t.detached = true
+	}
+
+	var candidates *iceCandidates
+	if trickle {
+		candidates = newIceCandidates()
+	}
+
+	s.candidates = candidates
+	s.mu.Unlock()
+
+	s.log.Debug("reconnect")
+	s.watchPeer(pc, candidates)
+
+	if err := old.Close(); err != nil {
+		s.log.Error(fmt.Sprintf("closing peer connection: %s", err.Error()))
+	}
+
+	return true, s.negotiate(sdpOffer)
+}
+
+// reconnecting tells the session waits for its client in the grace period.
+func (s *RtcUploadMediaSession) reconnecting() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.grace != nil
+}
+
 func (s *RtcUploadMediaSession) close() {
 	s.log.Debug("close")
 	if s.countTrack.Load() != 0 {
@@ -253,40 +1150,60 @@ func (s *RtcUploadMediaSession) close() {
 
 	s.cancel()
 
+	s.mu.Lock()
 	for _, track := range s.track {
-		if track.encoder != nil {
-			if closeErr := track.encoder.Close(); closeErr != nil {
-				s.log.Error(fmt.Sprintf("closing encoder: %s", closeErr.Error()))
-			}
-
-			track.encoder = nil
-		}
-
-		if track.writer != nil {
-			if closeErr := track.writer.Close(); closeErr != nil {
-				s.log.Error(fmt.Sprintf("closing writer: %s", closeErr.Error()))
-			}
-
-			track.writer = nil
+		track.mu.Lock()
+		if err := track.close(); err != nil {
+			s.log.Error(err.Error())
 		}
+		track.mu.Unlock()
 	}
+	pc := s.pc
+	s.mu.Unlock()
 
 	// Gracefully shutdown the peer connection
-	if closeErr := s.pc.Close(); closeErr != nil {
+	if closeErr := pc.Close(); closeErr != nil {
 		s.log.Error(fmt.Sprintf("closing peer connection: %s", closeErr.Error()))
 	}
 
 	s.rec.stopVideoSession(s)
 }
 
+// dropEmptyTracks removes the channels that got no data since the last rotation.
+func (s *RtcUploadMediaSession) dropEmptyTracks() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channels := make([]model.MediaChannel, 0, len(s.fileConfig.Track))
+
+	for _, t := range s.track {
+		ch := s.fileConfig.Track[t.idx]
+		if t.segmentPkg == 0 {
+			_ = os.Remove(ch.Path)
+
+			continue
+		}
+
+		channels = append(channels, ch)
+	}
+
+	s.fileConfig.Track = channels
+}
+
+// negotiate applies the offer to the current peer connection, the connection is taken under the lock
+// since the reconnect replaces it.
 func (s *RtcUploadMediaSession) negotiate(sdpOffer string) error {
-	s.offer = webrtc.SessionDescription{
+	s.mu.Lock()
+	pc, trickle := s.pc, s.candidates != nil
+	s.mu.Unlock()
+
+	offer := webrtc.SessionDescription{
 		Type: webrtc.SDPTypeOffer,
 		SDP:  sdpOffer,
 	}
 
 	// Set the remote SessionDescription
-	err := s.pc.SetRemoteDescription(s.offer)
+	err := pc.SetRemoteDescription(offer)
 	if err != nil {
 		return err
 	}
@@ -294,26 +1211,94 @@ func (s *RtcUploadMediaSession) negotiate(sdpOffer string) error {
 	// Create answer
 	var answer webrtc.SessionDescription
 
-	answer, err = s.pc.CreateAnswer(nil)
+	answer, err = pc.CreateAnswer(nil)
 	if err != nil {
 		return err
 	}
 
-	gatherComplete := webrtc.GatheringCompletePromise(s.pc)
+	// the trickle client gets the candidates by WatchIceCandidates
+	var gatherComplete <-chan struct{}
+	if !trickle {
+		gatherComplete = webrtc.GatheringCompletePromise(pc)
+	}
 
-	err = s.pc.SetLocalDescription(answer)
+	err = pc.SetLocalDescription(answer)
 	if err != nil {
 		return err
 	}
 
-	<-gatherComplete
+	if gatherComplete != nil {
+		<-gatherComplete
+	}
 
-	s.answer = s.pc.LocalDescription()
+	s.mu.Lock()
+	s.offer = offer
+	s.answer = pc.LocalDescription()
+	s.mu.Unlock()
+
+	return nil
+}
+
+// patchICE adds the remote candidates of the fragment, its new credentials restart ICE with the previous offer.
+func (s *RtcUploadMediaSession) patchICE(ufrag string, frag *model.IceFragment) (*model.IceFragment, bool, error) {
+	local, err := model.ParseIceFragment(s.AnswerSDP())
+	if err != nil {
+		return nil, false, err
+	}
+
+	if ufrag != "" && ufrag != local.Ufrag {
+		return nil, false, model.ErrIceMismatch
+	}
+
+	offer := s.offerSDP()
+
+	remote, err := model.ParseIceFragment(offer)
+	if err != nil {
+		return nil, false, err
+	}
+
+	restarted := frag.Ufrag != "" && (frag.Ufrag != remote.Ufrag || frag.Pwd != remote.Pwd)
+	if restarted {
+		s.log.Debug("ice restart")
+
+		if err = s.negotiate(model.WithIceCredentials(offer, frag.Ufrag, frag.Pwd)); err != nil {
+			return nil, false, err
+		}
+
+		if local, err = model.ParseIceFragment(s.AnswerSDP()); err != nil {
+			return nil, false, err
+		}
+	}
+
+	for _, c := range frag.Candidates {
+		if err = s.addRemoteCandidate(c); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return local, restarted, nil
+}
+
+// addRemoteCandidate adds the candidate trickled by the client, the empty one ends its gathering.
+func (s *RtcUploadMediaSession) addRemoteCandidate(c model.IceCandidate) error {
+	cand := webrtc.ICECandidateInit{Candidate: c.Candidate}
+	if c.Mid != "" {
+		cand.SDPMid = &c.Mid
+	} else {
+		cand.SDPMLineIndex = &c.MLineIndex
+	}
+
+	if err := s.peer().AddICECandidate(cand); err != nil {
+		return fmt.Errorf("%w: %s", model.ErrInvalidIceFragment, err.Error())
+	}
 
 	return nil
 }
 
 func (s *RtcUploadMediaSession) AnswerSDP() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.answer != nil {
 		return s.answer.SDP
 	}
@@ -321,6 +1306,61 @@ func (s *RtcUploadMediaSession) AnswerSDP() string {
 	return ""
 }
 
+func (s *RtcUploadMediaSession) offerSDP() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.offer.SDP
+}
+
+// iceCandidates returns the local candidates of the current connection, nil when it does not trickle.
+func (s *RtcUploadMediaSession) iceCandidates() *iceCandidates {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.candidates
+}
+
+func (s *RtcUploadMediaSession) Info() *model.SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info := &model.SessionInfo{
+		ID:        s.id,
+		Name:      s.fileConfig.Name,
+		UUID:      s.fileConfig.UUID,
+		DomainID:  s.fileConfig.DomainID,
+		UserID:    s.fileConfig.UploadedBy,
+		ICEState:  s.pc.ICEConnectionState().String(),
+		Paused:    s.fileConfig.Paused(),
+		StartTime: s.fileConfig.StartTime,
+		CreatedAt: s.fileConfig.CreatedAt,
+		Tracks:    make([]*model.TrackInfo, 0, len(s.track)),
+	}
+
+	for _, t := range s.track {
+		info.Tracks = append(info.Tracks, t.info())
+	}
+
+	return info
+}
+
+func (t *Track) info() *model.TrackInfo {
+	ti := &model.TrackInfo{
+		ID:          t.id,
+		MimeType:    t.MimeType,
+		Packets:     t.countPkg.Load(),
+		LostPackets: t.lostPkg.Load(),
+		Bytes:       t.bytes.Load(),
+	}
+
+	if t.codec != nil {
+		ti.ClockRate = t.codec.ClockRate
+	}
+
+	return ti
+}
+
 func (s *RtcUploadMediaSession) ID() string {
 	return s.id
 }
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func TestRtpGap(t *testing.T) {
+	assert.Equal(t, 0, rtpGap(20, 20, 1000))
+	// jitter of the network
+	assert.Equal(t, 0, rtpGap(20, 1500, 1000))
+	assert.Equal(t, 3000, rtpGap(3000, 3010, 1000))
+	// the sender restarted its clock
+	assert.Equal(t, 2500, rtpGap(-90000, 2500, 1000))
+	assert.Equal(t, 0, rtpGap(3000, 3000, 0))
+}
+
+func TestSetRtpTs(t *testing.T) {
+	s := &RtcUploadMediaSession{
+		rec:        &WebRtcRecorder{gap: 1000},
+		fileConfig: &model.File{Track: []model.MediaChannel{{MimeType: "audio/opus", ClockRate: 48000}}},
+	}
+	tr := &Track{}
+
+	ts := uint32(0xFFFFFFFF - 960*10)
+	for i := 0; i < 50; i++ {
+		gap, write := s.setRtpTs(tr, ts)
+		assert.Nil(t, gap)
+		assert.True(t, write)
+		ts += 960
+	}
+
+	// muted for 5 seconds
+	ts += 48000 * 5
+	gap, _ := s.setRtpTs(tr, ts)
+	assert.Equal(t, &model.Gap{At: 980, Duration: 5020}, gap)
+
+	ts += 960
+	next, _ := s.setRtpTs(tr, ts)
+	assert.Nil(t, next)
+
+	ch := s.fileConfig.Track[0]
+	assert.Equal(t, []model.Gap{*gap}, ch.Gaps)
+	assert.Equal(t, ts, ch.LastRtpTs)
+	assert.Equal(t, 1000, tr.mediaMs)
+}
+
+func TestPauseRecording(t *testing.T) {
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+	s := &RtcUploadMediaSession{
+		id:  "s1",
+		log: log,
+		rec: &WebRtcRecorder{
+			gap:    1000,
+			temp:   &TempFileService{dir: t.TempDir()},
+			events: NewSessionEvents(log),
+		},
+		fileConfig: &model.File{Track: []model.MediaChannel{{MimeType: "audio/opus", ClockRate: 48000}}},
+	}
+	tr := &Track{}
+	s.track = []*Track{tr}
+
+	ts := uint32(1000)
+	for i := 0; i < 10; i++ {
+		_, write := s.setRtpTs(tr, ts)
+		assert.True(t, write)
+		ts += 960
+	}
+
+	assert.True(t, s.Pause())
+	assert.False(t, s.Pause())
+
+	_, write := s.setRtpTs(tr, ts+48000)
+	assert.False(t, write)
+
+	assert.True(t, s.Resume())
+	assert.False(t, s.Resume())
+
+	// the pause shorter than the gap threshold is redacted too
+	ts += 960 + 24000
+	gap, write := s.setRtpTs(tr, ts)
+	assert.True(t, write)
+	assert.Equal(t, &model.Gap{At: 180, Duration: 540, Redacted: true}, gap)
+
+	// stopped while paused
+	assert.True(t, s.Pause())
+	last := tr.lastAt
+	s.closePause(last + 3000)
+
+	ch := s.fileConfig.Track[0]
+	assert.Equal(t, model.Gap{At: 180, Duration: 3000, Redacted: true}, ch.Gaps[1])
+	assert.Equal(t, ts+48000*3, ch.LastRtpTs)
+	assert.False(t, s.fileConfig.Paused())
+	assert.Len(t, s.fileConfig.Pauses, 2)
+}
+
+func TestDataChannelMarker(t *testing.T) {
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+	s := &RtcUploadMediaSession{
+		id:  "s1",
+		log: log,
+		rec: &WebRtcRecorder{
+			temp:   &TempFileService{dir: t.TempDir()},
+			events: NewSessionEvents(log),
+		},
+		fileConfig: &model.File{Track: []model.MediaChannel{{MimeType: model.DataMimeType, Label: "events"}}},
+	}
+
+	w := &bufferWriter{}
+	tr := &Track{id: "events", writer: w}
+
+	s.onDataMessage(tr, []byte(`{"type":"marker","label":"payment step","timestamp":1000}`))
+	s.onDataMessage(tr, []byte(`{"type":"marker","label":""}`))
+	s.onDataMessage(tr, []byte(`{"type":"chat","label":"hello"}`))
+	s.onDataMessage(tr, []byte(`{"type":"chat","text":"hello","from":"agent","timestamp":2000}`))
+	s.onDataMessage(tr, []byte(`payment step`))
+
+	assert.Equal(t, []model.Marker{{Label: "payment step", Time: 1000}}, s.fileConfig.Markers)
+	assert.Equal(t, int64(5), tr.countPkg.Load())
+	assert.Equal(t, int64(3), tr.lostPkg.Load())
+	assert.Positive(t, s.fileConfig.Track[0].FirstPacketAt)
+	assert.Equal(t, `{"type":"marker","timestamp":1000,"channel":"events","label":"payment step"}`+"\n"+
+		`{"type":"chat","timestamp":2000,"channel":"events","text":"hello","from":"agent"}`+"\n", w.String())
+
+	// the messages of the pause are not recorded
+	s.fileConfig.Pauses = []model.Pause{{Start: 1}}
+	s.onDataMessage(tr, []byte(`{"type":"event","label":"hold"}`))
+	assert.Equal(t, 2, tr.segmentPkg)
+}
+
+func TestReattachTrack(t *testing.T) {
+	s := &RtcUploadMediaSession{log: wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})}
+	audio := &Track{
+		id:      "audio",
+		ssrc:    1,
+		lastTs:  48000,
+		lastAt:  model.GetMillis() - 2000,
+		codec:   &webrtc.RTPCodecParameters{RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: "audio/opus", ClockRate: 48000, Channels: 2}},
+		mediaMs: 1000,
+	}
+	audio.MimeType = "audio/opus"
+	events := &Track{id: "events"}
+	events.MimeType = model.DataMimeType
+	s.track = []*Track{audio, events}
+
+	ts, ok := s.rtpTs(audio, 0, 96000)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(96000), ts)
+
+	// the connection is lost
+	audio.detached, events.detached = true, true
+	assert.True(t, s.replaced(audio, 0))
+	assert.Nil(t, s.reattach("video", "video/VP8", &webrtc.RTPCodecParameters{RTPCodecCapability: webrtc.RTPCodecCapability{ClockRate: 90000}}))
+	assert.Nil(t, s.reattach("chat", model.DataMimeType, nil))
+
+	assert.Equal(t, audio, s.reattach("mic", "audio/OPUS", &webrtc.RTPCodecParameters{RTPCodecCapability: webrtc.RTPCodecCapability{ClockRate: 48000, Channels: 2}}))
+	assert.Equal(t, events, s.reattach("events", model.DataMimeType, nil))
+	assert.False(t, events.rebase)
+
+	// the late packet of the lost connection
+	_, ok = s.rtpTs(audio, 0, 96960)
+	assert.False(t, ok)
+
+	// the new sender continues after the outage
+	ts, ok = s.rtpTs(audio, 1, 1000)
+	assert.True(t, ok)
+	assert.InDelta(t, 48000+2*48000, float64(ts), 48000/10)
+	next, ok := s.rtpTs(audio, 1, 1960)
+	assert.True(t, ok)
+	assert.Equal(t, ts+960, next)
+	assert.False(t, s.replaced(audio, 1))
+}
+
+func TestTrackLeave(t *testing.T) {
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+	s := &RtcUploadMediaSession{
+		id:  "s1",
+		log: log,
+		rec: &WebRtcRecorder{
+			temp:   &TempFileService{dir: t.TempDir()},
+			events: NewSessionEvents(log),
+		},
+		fileConfig: &model.File{Track: []model.MediaChannel{{MimeType: "video/VP8"}, {MimeType: "video/VP8"}}},
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	cam, screen := &Track{id: "cam"}, &Track{id: "screen", idx: 1}
+	s.track = []*Track{cam, screen}
+
+	// the reader of the lost connection
+	screen.detached = true
+	s.leave(screen, 0)
+	assert.Zero(t, s.fileConfig.Track[1].LeftAt)
+
+	screen.detached = false
+	s.leave(screen, 0)
+	assert.Positive(t, s.fileConfig.Track[1].LeftAt)
+
+	// the session is closing
+	s.cancel()
+	s.leave(cam, 0)
+	assert.Zero(t, s.fileConfig.Track[0].LeftAt)
+}
+
+func TestReconnectGrace(t *testing.T) {
+	s := &RtcUploadMediaSession{
+		log: wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false}),
+		rec: &WebRtcRecorder{},
+	}
+
+	// the grace period is disabled
+	assert.False(t, s.startGrace())
+	assert.False(t, s.reconnecting())
+
+	s.rec.reconnect = time.Hour
+	assert.True(t, s.startGrace())
+	assert.True(t, s.reconnecting())
+
+	// ICE restart restored the connection
+	s.stopGrace()
+	assert.False(t, s.reconnecting())
+
+	ok, err := s.reconnect(nil, "", false)
+	assert.False(t, ok)
+	assert.NoError(t, err)
+}
+
+func TestRotate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		start   int
+		packets int
+		paused  bool
+		segment int
+	}{
+		{
+			name:    "not started",
+			packets: 1,
+		},
+		{
+			name:  "nothing recorded",
+			start: 1000,
+		},
+		{
+			name:    "recorded segment",
+			start:   1000,
+			packets: 3,
+			segment: 1,
+		},
+		{
+			name:    "paused segment",
+			start:   1000,
+			packets: 3,
+			paused:  true,
+			segment: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+			tmp := &TempFileService{dir: t.TempDir()}
+			s := &RtcUploadMediaSession{
+				id:  "s1",
+				log: log,
+				rec: &WebRtcRecorder{temp: tmp},
+				fileConfig: &model.File{
+					Name:      "call",
+					StartTime: tc.start,
+					Markers:   []model.Marker{{Label: "step", Time: 1500}},
+					Track:     []model.MediaChannel{{MimeType: model.DataMimeType, Label: "events"}},
+				},
+			}
+			s.ctx, s.cancel = context.WithCancel(context.Background())
+			defer s.cancel()
+
+			tr := &Track{id: "events"}
+			require.NoError(t, tr.open(tmp))
+			defer tr.close()
+
+			s.track = []*Track{tr}
+			s.fileConfig.Track[0].Path = tr.Path
+			tr.segmentPkg = tc.packets
+			first := tr.Path
+
+			if tc.paused {
+				s.fileConfig.Pauses = []model.Pause{{Start: 1200}}
+			}
+
+			f := s.rotate()
+			if tc.segment == 0 {
+				assert.Nil(t, f)
+
+				if tc.start > 0 {
+					// the empty file is dropped, the next segment is recorded into a new one
+					assert.NoFileExists(t, first)
+					assert.NotEqual(t, first, s.fileConfig.Track[0].Path)
+				}
+
+				return
+			}
+
+			require.NotNil(t, f)
+			assert.Equal(t, tc.segment, f.Segment)
+			assert.Equal(t, "call_part001", f.SegmentName())
+			assert.Equal(t, []model.MediaChannel{{Path: first, MimeType: model.DataMimeType, Label: "events"}}, f.Track)
+			assert.Len(t, f.Markers, 1)
+			assert.FileExists(t, first)
+
+			// the session goes on in the next segment
+			assert.Equal(t, tc.segment+1, s.fileConfig.Segment)
+			assert.Equal(t, f.EndTime, s.fileConfig.StartTime)
+			assert.Empty(t, s.fileConfig.Markers)
+			assert.FileExists(t, s.fileConfig.Track[0].Path)
+			assert.NotEqual(t, first, s.fileConfig.Track[0].Path)
+			assert.Zero(t, tr.segmentPkg)
+
+			if tc.paused {
+				assert.Equal(t, []model.Pause{{Start: 1200, End: f.EndTime}}, f.Pauses)
+				assert.True(t, s.fileConfig.Paused())
+			} else {
+				assert.Empty(t, s.fileConfig.Pauses)
+			}
+		})
+	}
+}
+
+type bufferWriter struct {
+	bytes.Buffer
+}
+
+func (*bufferWriter) Close() error {
+	return nil
+}
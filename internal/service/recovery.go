@@ -0,0 +1,218 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/config"
+	"github.com/webitel/webrtc_recorder/internal/model"
+	"github.com/webitel/webrtc_recorder/internal/utils"
+)
+
+// Recovery enqueues the recordings of the sessions interrupted by a crash and removes
+// the temp files nobody refers to anymore.
+type Recovery struct {
+	log         *wlog.Logger
+	instance    string
+	maxAge      time.Duration
+	shared      bool
+	jobStore    FileJobStore
+	temp        *TempFileService
+	transcoding *Transcoding
+}
+
+func NewRecovery(cfg *config.Config, log *wlog.Logger, fjs FileJobStore, tmp *TempFileService, tr *Transcoding) *Recovery {
+	r := &Recovery{
+		log:         log.With(wlog.String("service", "recovery")),
+		instance:    cfg.Service.ID,
+		maxAge:      cfg.TempMaxAge,
+		shared:      cfg.SharedTemp,
+		jobStore:    fjs,
+		temp:        tmp,
+		transcoding: tr,
+	}
+
+	r.Run()
+
+	return r
+}
+
+// Run must be called before the instance starts new sessions.
+func (r *Recovery) Run() {
+	// files of the shared temp dir may belong to the jobs of other instances
+	paths, err := r.jobStore.Paths(r.shared)
+	if err != nil {
+		r.log.Error(err.Error(), wlog.Err(err))
+
+		return
+	}
+
+	referenced := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		referenced[p] = struct{}{}
+	}
+
+	r.recoverSessions(referenced)
+	r.sweep(referenced)
+}
+
+func (r *Recovery) recoverSessions(referenced map[string]struct{}) {
+	manifests, err := r.temp.Manifests()
+	if err != nil {
+		r.log.Error(err.Error(), wlog.Err(err))
+
+		return
+	}
+
+	for _, p := range manifests {
+		m, err := r.temp.ReadManifest(p)
+		if err != nil {
+			r.log.Error(fmt.Sprintf("broken manifest %s: %s", p, err.Error()), wlog.Err(err))
+			_ = os.Remove(p)
+
+			continue
+		}
+
+		if m.Instance != r.instance {
+			// the temp dir is shared, the session belongs to another instance
+			for _, ch := range m.File.Track {
+				referenced[ch.Path] = struct{}{}
+			}
+
+			continue
+		}
+
+		log := r.log.With(wlog.String("session", m.ID))
+
+		if m.Config == nil {
+			m.Config = &model.JobConfig{}
+		}
+
+		f := r.repair(log, m.File, referenced)
+		if f != nil {
+			if err = r.transcoding.CreateJob(m.Config, f); err != nil {
+				log.Error(err.Error(), wlog.Err(err))
+
+				continue
+			}
+
+			for _, ch := range f.Track {
+				referenced[ch.Path] = struct{}{}
+			}
+
+			log.Info(fmt.Sprintf("recovered interrupted session, %d tracks", len(f.Track)))
+		}
+
+		if err = r.temp.RemoveManifest(m.ID); err != nil {
+			log.Error(err.Error(), wlog.Err(err))
+		}
+	}
+}
+
+// repair fixes the containers of the session tracks and returns the file to transcode,
+// nil when nothing usable was recorded.
+func (r *Recovery) repair(log *wlog.Logger, src *model.File, referenced map[string]struct{}) *model.File {
+	f := *src
+	f.Track = make([]model.MediaChannel, 0, len(src.Track))
+
+	var endTime int
+
+	for _, ch := range src.Track {
+		if _, ok := referenced[ch.Path]; ok {
+			// already enqueued before the crash
+			continue
+		}
+
+		st, err := os.Stat(ch.Path)
+		if err != nil || st.Size() == 0 {
+			_ = os.Remove(ch.Path)
+
+			continue
+		}
+
+		switch ch.MimeType {
+		case webrtc.MimeTypeVP8, webrtc.MimeTypeVP9, webrtc.MimeTypeAV1:
+			_, err = utils.RepairIVF(ch.Path)
+		case webrtc.MimeTypeOpus:
+			_, err = utils.RepairOgg(ch.Path)
+		case webrtc.MimeTypePCMU, webrtc.MimeTypePCMA, webrtc.MimeTypeG722:
+			_, err = utils.RepairWAV(ch.Path)
+		}
+
+		if err != nil {
+			log.Error(fmt.Sprintf("repair %s: %s", ch.Path, err.Error()), wlog.Err(err))
+			_ = os.Remove(ch.Path)
+
+			continue
+		}
+
+		if mt := int(st.ModTime().UnixMilli()); mt > endTime {
+			endTime = mt
+		}
+
+		f.Track = append(f.Track, ch)
+	}
+
+	if len(f.Track) == 0 {
+		return nil
+	}
+
+	if f.StartTime > 0 && f.EndTime == 0 {
+		f.EndTime = endTime
+	}
+
+	if f.Segment > 0 {
+		f.Name = f.SegmentName()
+	}
+
+	f.Align()
+
+	return &f
+}
+
+// sweep removes the temp files older than maxAge that are not referenced by any job.
+func (r *Recovery) sweep(referenced map[string]struct{}) {
+	if r.maxAge <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(r.temp.Dir())
+	if err != nil {
+		r.log.Error(err.Error(), wlog.Err(err))
+
+		return
+	}
+
+	deadline := time.Now().Add(-r.maxAge)
+
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		p := path.Join(r.temp.Dir(), e.Name())
+		if _, ok := referenced[p]; ok || IsManifest(p) {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil || info.ModTime().After(deadline) {
+			continue
+		}
+
+		if err = os.Remove(p); err != nil {
+			r.log.Error(err.Error(), wlog.Err(err))
+
+			continue
+		}
+
+		r.log.Debug("removed orphaned temp file " + p)
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/config"
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+type fakeJobStore struct {
+	FileJobStore
+
+	paths []string
+	jobs  []*model.Job
+}
+
+func (s *fakeJobStore) Create(jobType string, cfg *model.JobConfig, f *model.File) error {
+	s.jobs = append(s.jobs, &model.Job{Type: jobType, Config: cfg, File: f})
+
+	return nil
+}
+
+func (s *fakeJobStore) Paths(_ bool) ([]string, error) {
+	return s.paths, nil
+}
+
+func writeIVF(t *testing.T, p string, frames int) {
+	t.Helper()
+
+	data := make([]byte, 32)
+	copy(data, "DKIF")
+	binary.LittleEndian.PutUint16(data[6:], 32)
+
+	for i := 0; i < frames; i++ {
+		frame := make([]byte, 12+4)
+		binary.LittleEndian.PutUint32(frame, 4)
+		data = append(data, frame...)
+	}
+
+	// unfinished frame
+	data = append(data, 0xff, 0xff)
+
+	require.NoError(t, os.WriteFile(p, data, 0o644))
+}
+
+func TestRecovery_Run(t *testing.T) {
+	dir := t.TempDir()
+	tmp := &TempFileService{dir: dir}
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+
+	video := filepath.Join(dir, "video.raw")
+	done := filepath.Join(dir, "done.raw")
+	orphan := filepath.Join(dir, "orphan.raw")
+	fresh := filepath.Join(dir, "fresh.raw")
+
+	writeIVF(t, video, 2)
+	writeIVF(t, done, 2)
+	require.NoError(t, os.WriteFile(orphan, []byte("x"), 0o644))
+	require.NoError(t, os.WriteFile(fresh, []byte("x"), 0o644))
+
+	old := time.Now().Add(-time.Hour * 24)
+	require.NoError(t, os.Chtimes(orphan, old, old))
+	require.NoError(t, os.Chtimes(done, old, old))
+
+	require.NoError(t, tmp.SaveManifest(&model.SessionManifest{
+		ID:       "s1",
+		Instance: "1",
+		File: &model.File{
+			Name:      "rec",
+			StartTime: 1000,
+			Segment:   2,
+			Track: []model.MediaChannel{
+				{Path: video, MimeType: webrtc.MimeTypeVP9},
+				{Path: done, MimeType: webrtc.MimeTypeVP9},
+			},
+		},
+	}))
+
+	fjs := &fakeJobStore{paths: []string{done}}
+	cfg := &config.Config{TempMaxAge: time.Hour}
+	cfg.Service.ID = "1"
+
+	NewRecovery(cfg, log, fjs, tmp, &Transcoding{jobHandler: jobHandler{jobStore: fjs, log: log}})
+
+	require.Len(t, fjs.jobs, 1)
+	job := fjs.jobs[0]
+	assert.Equal(t, TranscodingJobName, job.Type)
+	assert.Equal(t, "rec_part002", job.File.Name)
+	assert.Equal(t, []model.MediaChannel{{Path: video, MimeType: webrtc.MimeTypeVP9}}, job.File.Track)
+	assert.Positive(t, job.File.EndTime)
+
+	st, err := os.Stat(video)
+	require.NoError(t, err)
+	assert.Equal(t, int64(32+2*16), st.Size())
+
+	assert.NoFileExists(t, filepath.Join(dir, "s1"+manifestExt))
+	assert.NoFileExists(t, orphan)
+	assert.FileExists(t, fresh)
+	assert.FileExists(t, done)
+}
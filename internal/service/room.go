@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+// room is the recording the participants with the same room id feed, every participant records its own tracks
+// and merges them into the room when it leaves. The last participant stores the recording of the room.
+type room struct {
+	key  string
+	file *model.File
+	cfg  *model.JobConfig
+	// peers are the participants in the room, left are the sessions whose manifests keep the merged tracks
+	// until the room recording is stored
+	peers int
+	left  []string
+}
+
+// rooms are the open room recordings by their keys.
+type rooms struct {
+	mu   sync.Mutex
+	list map[string]*room
+}
+
+func newRooms() *rooms {
+	return &rooms{list: make(map[string]*room)}
+}
+
+// join adds the session to the room of its file, the first participant opens the room and its name, uuid and
+// job config are the ones of the room recording.
+func (r *rooms) join(s *RtcUploadMediaSession) *room {
+	key := model.RoomKey(s.fileConfig.DomainID, s.fileConfig.RoomID)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rm, ok := r.list[key]
+	if !ok {
+		f := *s.fileConfig
+		f.Track, f.Markers, f.Pauses = nil, nil, nil
+		f.StartTime, f.Participant = 0, ""
+
+		rm = &room{key: key, file: &f, cfg: s.jobConfig}
+		r.list[key] = rm
+	}
+
+	rm.peers++
+
+	return rm
+}
+
+// leave merges the recording of the session into the room, it returns the room recording after the last participant.
+func (r *rooms) leave(rm *room, id string, f *model.File) *model.File {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rm.left = append(rm.left, id)
+
+	rm.file.Track = append(rm.file.Track, f.Track...)
+	rm.file.Markers = append(rm.file.Markers, f.Markers...)
+	rm.file.Pauses = append(rm.file.Pauses, f.Pauses...)
+
+	if f.StartTime > 0 && (rm.file.StartTime == 0 || f.StartTime < rm.file.StartTime) {
+		rm.file.StartTime = f.StartTime
+	}
+
+	rm.file.EndTime = max(rm.file.EndTime, f.EndTime)
+
+	rm.peers--
+	if rm.peers > 0 {
+		return nil
+	}
+
+	delete(r.list, rm.key)
+
+	return rm.file
+}
+
+// joinRoom adds the session to its room, the room recording is stored by the job config of the room.
+func (svc *WebRtcRecorder) joinRoom(s *RtcUploadMediaSession) {
+	rm := svc.rooms.join(s)
+
+	s.mu.Lock()
+	s.room = rm
+	s.jobConfig = rm.cfg
+	s.mu.Unlock()
+
+	s.log.Debug(fmt.Sprintf("joined room %s", rm.key))
+}
+
+// leaveRoom merges the tracks of the closed session into its room, the last participant stores the room recording.
+// The manifests of the participants are removed once the room job is created, a crash before that recovers
+// the tracks of every participant from its own manifest.
+func (svc *WebRtcRecorder) leaveRoom(s *RtcUploadMediaSession) {
+	f := svc.rooms.leave(s.room, s.id, s.fileConfig)
+	if f == nil {
+		s.log.Debug(fmt.Sprintf("left room %s", s.room.key))
+
+		return
+	}
+
+	s.log.Debug(fmt.Sprintf("room %s closed", s.room.key))
+
+	// the feeds of the participants that left are closed, the jobs of the room are watched on the last one
+	f.SessionID = s.id
+
+	if len(f.Track) != 0 {
+		svc.storeSegment(s, f)
+	}
+
+	for _, id := range s.room.left {
+		if err := svc.temp.RemoveManifest(id); err != nil && !os.IsNotExist(err) {
+			s.log.Error(err.Error(), wlog.Err(err))
+		}
+	}
+}
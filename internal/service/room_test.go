@@ -0,0 +1,153 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func TestRooms(t *testing.T) {
+	r := newRooms()
+
+	alice := &RtcUploadMediaSession{
+		id:         "a",
+		fileConfig: &model.File{DomainID: 1, Name: "standup", RoomID: "r1", Participant: "Alice"},
+		jobConfig:  &model.JobConfig{Layout: model.LayoutSpeaker},
+	}
+	bob := &RtcUploadMediaSession{
+		id:         "b",
+		fileConfig: &model.File{DomainID: 1, Name: "bob", RoomID: "r1", Participant: "Bob"},
+		jobConfig:  &model.JobConfig{},
+	}
+	other := &RtcUploadMediaSession{id: "c", fileConfig: &model.File{DomainID: 2, RoomID: "r1"}, jobConfig: &model.JobConfig{}}
+
+	rm := r.join(alice)
+	assert.Same(t, rm, r.join(bob))
+	assert.NotSame(t, rm, r.join(other))
+	assert.Equal(t, "standup", rm.file.Name)
+	assert.Empty(t, rm.file.Participant)
+	assert.Same(t, alice.jobConfig, rm.cfg)
+
+	alice.fileConfig.StartTime, alice.fileConfig.EndTime = 2000, 9000
+	alice.fileConfig.Track = []model.MediaChannel{{Path: "a_cam", Peer: "a"}}
+	assert.Nil(t, r.leave(rm, alice.id, alice.fileConfig))
+
+	bob.fileConfig.StartTime, bob.fileConfig.EndTime = 1000, 8000
+	bob.fileConfig.Track = []model.MediaChannel{{Path: "b_cam", Peer: "b"}, {Path: "b_mic", Peer: "b"}}
+	bob.fileConfig.Markers = []model.Marker{{Label: "agenda", Time: 1500}}
+
+	// the last participant closes the room
+	f := r.leave(rm, bob.id, bob.fileConfig)
+	require.NotNil(t, f)
+	assert.Len(t, f.Track, 3)
+	assert.Len(t, f.Markers, 1)
+	assert.Equal(t, 1000, f.StartTime)
+	assert.Equal(t, 9000, f.EndTime)
+	assert.NotContains(t, r.list, rm.key)
+	assert.Equal(t, []string{"a", "b"}, rm.left)
+}
+
+func TestLeaveRoomEvents(t *testing.T) {
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+	events := NewSessionEvents(log)
+	fjs := &fakeJobStore{}
+	handler := jobHandler{jobStore: fjs, log: log, events: events}
+	tmp := &TempFileService{dir: t.TempDir()}
+	svc := &WebRtcRecorder{
+		rooms:       newRooms(),
+		events:      events,
+		temp:        tmp,
+		transcoding: &Transcoding{jobHandler: handler},
+	}
+
+	newSession := func(id string) *RtcUploadMediaSession {
+		s := &RtcUploadMediaSession{
+			id:         id,
+			log:        log,
+			rec:        svc,
+			fileConfig: &model.File{DomainID: 1, SessionID: id, RoomID: "r1"},
+			jobConfig:  &model.JobConfig{},
+		}
+		events.open(id, 1)
+		svc.joinRoom(s)
+		require.NoError(t, tmp.SaveManifest(&model.SessionManifest{ID: id, File: s.fileConfig}))
+
+		return s
+	}
+
+	alice, bob := newSession("a"), newSession("b")
+
+	ch, cancel, err := events.Subscribe("b", 1, nil)
+	require.NoError(t, err)
+	defer cancel()
+
+	alice.fileConfig.Track = []model.MediaChannel{{Path: "a_cam", Peer: "a"}}
+	svc.leaveRoom(alice)
+	events.sessionClosed("a")
+
+	// the tracks merged into the room are recovered from the manifest of the participant until the room job
+	assert.FileExists(t, tmp.manifestPath("a"))
+
+	bob.fileConfig.Track = []model.MediaChannel{{Path: "b_cam", Peer: "b"}}
+	svc.leaveRoom(bob)
+	events.sessionClosed("b")
+
+	// the room recording is watched on the last participant
+	require.Len(t, fjs.jobs, 1)
+	assert.Equal(t, "b", fjs.jobs[0].File.SessionID)
+	assert.NoFileExists(t, tmp.manifestPath("a"))
+	assert.NoFileExists(t, tmp.manifestPath("b"))
+
+	upload := &UploadJob{svc: &Uploader{jobHandler: handler}, baseJob: &baseJob{log: log, job: fjs.jobs[0]}}
+	upload.saved(&model.UploadedFile{ID: 7})
+
+	assert.Equal(t, []model.SessionEventType{model.EventFileSaved}, drain(ch))
+}
+
+func TestSpeech(t *testing.T) {
+	s := &RtcUploadMediaSession{
+		log:        wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false}),
+		fileConfig: &model.File{Track: []model.MediaChannel{{MimeType: "audio/opus"}}},
+	}
+	tr := &Track{}
+	s.track = []*Track{tr}
+
+	packet := func(level uint8) *rtp.Packet {
+		pkt := &rtp.Packet{}
+		ext, err := rtp.AudioLevelExtension{Level: level, Voice: level < 127}.Marshal()
+		require.NoError(t, err)
+		require.NoError(t, pkt.SetExtension(1, ext))
+
+		return pkt
+	}
+
+	s.speech(tr, packet(127), 1)
+	assert.Zero(t, tr.speechStart)
+
+	s.speech(tr, packet(30), 1)
+	assert.Positive(t, tr.speechStart)
+
+	// the short silence is the part of the speech
+	s.speech(tr, packet(127), 1)
+	assert.Positive(t, tr.speechStart)
+
+	tr.speechAt -= speechHangover + 1
+	s.speech(tr, packet(127), 1)
+	assert.Zero(t, tr.speechStart)
+	assert.Len(t, s.fileConfig.Track[0].Speech, 1)
+
+	// the speech of the closed session
+	s.speech(tr, packet(20), 1)
+	s.flushSpeech()
+	assert.Len(t, s.fileConfig.Track[0].Speech, 2)
+
+	// the packet without the level
+	s.speech(tr, &rtp.Packet{}, 1)
+	assert.Zero(t, tr.speechStart)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+const (
+	SinkStorage = "storage"
+	SinkLocal   = "local"
+	SinkS3      = "s3"
+)
+
+var ErrSinkNotFound = errors.New("upload sink not found")
+
+// Sink is a destination the uploader delivers transcoded recordings to.
+type Sink interface {
+	// Upload writes src to the destination, resuming the previous attempt of the job when possible.
+	Upload(j *UploadJob, src io.ReadSeeker) (*model.UploadedFile, error)
+	// Cancel releases everything left by the job that will not be retried anymore.
+	Cancel(j *UploadJob)
+}
+
+// objectKey builds the domain/date/uuid_job layout shared by the file based sinks. The uuid comes from the client and
+// may repeat, so the job id keeps the recordings apart, the segments of one recording share the uuid and are told
+// apart by the part number.
+func objectKey(j *model.Job) string {
+	f := j.File
+
+	// uuid comes from the client, so it must not escape the domain dir
+	name := f.UUID
+	if name == "" || name == ".." || strings.ContainsAny(name, `/\`) {
+		name = filepath.Base(f.Path)
+	} else {
+		name += "_" + strconv.Itoa(j.ID)
+
+		if f.Segment > 0 {
+			name += fmt.Sprintf("_part%03d", f.Segment)
+		}
+
+		name += filepath.Ext(f.Path)
+	}
+
+	created := time.Now()
+	if f.CreatedAt > 0 {
+		created = time.UnixMilli(int64(f.CreatedAt))
+	}
+
+	return path.Join(strconv.Itoa(f.DomainID), created.UTC().Format(time.DateOnly), name)
+}
+
+// pauseLog is the "start-end" list of the paused intervals of the recording, unix ms.
+func pauseLog(pauses []model.Pause) string {
+	items := make([]string, 0, len(pauses))
+	for _, p := range pauses {
+		items = append(items, strconv.Itoa(p.Start)+"-"+strconv.Itoa(p.End))
+	}
+
+	return strings.Join(items, ",")
+}
+
+// markerLog is the "at:label" list of the markers of the recording, the labels are escaped for the ASCII metadata.
+func markerLog(markers []model.Marker) string {
+	items := make([]string, 0, len(markers))
+	for _, m := range markers {
+		items = append(items, strconv.Itoa(m.At)+":"+url.QueryEscape(m.Label))
+	}
+
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+const localPartExt = ".part"
+
+// localSink keeps recordings in a local (or mounted) directory tree.
+type localSink struct {
+	dir string
+}
+
+func newLocalSink(dir string) *localSink {
+	return &localSink{
+		dir: dir,
+	}
+}
+
+func (s *localSink) path(j *UploadJob) string {
+	return filepath.Join(s.dir, filepath.FromSlash(objectKey(j.job)))
+}
+
+// Upload appends to the partial file left by the previous attempt of the job and renames it once complete, a partial
+// file the job did not start for this src is written over.
+func (s *localSink) Upload(j *UploadJob, src io.ReadSeeker) (*model.UploadedFile, error) {
+	dst := s.path(j)
+
+	size, err := src.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(dst), 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	part, err := os.OpenFile(dst+localPartExt, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer part.Close()
+
+	offset, err := part.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if st := j.uploadState(); st == nil || st.ID != dst || st.Size != size || offset > size {
+		if offset > 0 {
+			j.log.Warn("drop foreign partial file", wlog.String("path", dst), wlog.Int64("size", offset))
+		}
+
+		if err = part.Truncate(0); err != nil {
+			return nil, err
+		}
+
+		if offset, err = part.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		j.setUploadState(&model.UploadState{
+			ID:   dst,
+			Size: size,
+		})
+	}
+
+	if offset > 0 {
+		j.log.Debug("resume local upload", wlog.String("path", dst), wlog.Int64("offset", offset))
+	}
+
+	if _, err = src.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	if _, err = io.Copy(part, src); err != nil {
+		return nil, err
+	}
+
+	if err = part.Sync(); err != nil {
+		return nil, err
+	}
+
+	if err = part.Close(); err != nil {
+		return nil, err
+	}
+
+	if err = os.Rename(dst+localPartExt, dst); err != nil {
+		return nil, err
+	}
+
+	return &model.UploadedFile{
+		Location: dst,
+	}, nil
+}
+
+func (s *localSink) Cancel(j *UploadJob) {
+	err := os.Remove(s.path(j) + localPartExt)
+	if err != nil && !os.IsNotExist(err) {
+		j.log.Error(err.Error(), wlog.Err(err))
+	}
+
+	j.setUploadState(nil)
+}
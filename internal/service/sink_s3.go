@@ -0,0 +1,96 @@
+package service
+
+import (
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/config"
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+// s3Sink puts recordings into an S3 compatible bucket.
+type s3Sink struct {
+	cli    *minio.Client
+	bucket string
+	prefix string
+}
+
+func newS3Sink(cfg *config.S3Settings) (*s3Sink, error) {
+	cli, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.Secure,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Sink{
+		cli:    cli,
+		bucket: cfg.Bucket,
+		prefix: cfg.Prefix,
+	}, nil
+}
+
+// Upload puts the whole object, the multipart upload of a failed attempt is aborted by the client.
+func (s *s3Sink) Upload(j *UploadJob, src io.ReadSeeker) (*model.UploadedFile, error) {
+	size, err := src.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	f := j.job.File
+
+	info, err := s.cli.PutObject(j.ctx, s.bucket, path.Join(s.prefix, objectKey(j.job)), src, size, minio.PutObjectOptions{
+		ContentType:  f.MimeType,
+		UserMetadata: s3Metadata(f),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	j.log.Debug("uploaded object "+info.Key, wlog.Int64("size", info.Size))
+
+	return &model.UploadedFile{
+		Location: info.Bucket + "/" + info.Key,
+	}, nil
+}
+
+func (s *s3Sink) Cancel(_ *UploadJob) {}
+
+// s3Metadata is the user metadata of the object, it is sent as the headers which allow ASCII only,
+// so the values set by the client are escaped.
+func s3Metadata(f *model.File) map[string]string {
+	meta := map[string]string{
+		"domain-id":   strconv.Itoa(f.DomainID),
+		"uploaded-by": strconv.Itoa(f.UploadedBy),
+		"name":        url.QueryEscape(f.Name),
+		"uuid":        url.QueryEscape(f.UUID),
+	}
+
+	if f.StartTime > 0 {
+		meta["start-time"] = strconv.Itoa(f.StartTime)
+		meta["end-time"] = strconv.Itoa(f.EndTime)
+	}
+
+	if len(f.Pauses) > 0 {
+		meta["pauses"] = pauseLog(f.Pauses)
+	}
+
+	if len(f.Markers) > 0 {
+		meta["markers"] = markerLog(f.Markers)
+	}
+
+	return meta
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/webitel/wlog"
+
+	spb "github.com/webitel/webrtc_recorder/gen/storage"
+	"github.com/webitel/webrtc_recorder/infra/storage"
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+const (
+	uploadChunkSize     = 1024 * 256
+	uploadSaveStep      = 1024 * 1024 * 4
+	uploadCancelTimeout = time.Second * 10
+)
+
+var (
+	errUploadNoPart     = errors.New("storage did not acknowledge upload part")
+	errUploadNoMetadata = errors.New("upload stream closed without file metadata")
+	errUploadFailed     = errors.New("storage rejected uploaded file")
+)
+
+type uploadResult struct {
+	file *model.UploadedFile
+	err  error
+}
+
+// storageSink uploads recordings to the Webitel storage through the resumable SafeUploadFile stream. SafeUploadFile
+// does not carry uploaded_by and created_at, so the files that have them are uploaded whole through UploadFile unless
+// resumable is set.
+type storageSink struct {
+	storage   *storage.Storage
+	resumable bool
+}
+
+func newStorageSink(st *storage.Storage, resumable bool) *storageSink {
+	return &storageSink{
+		storage:   st,
+		resumable: resumable,
+	}
+}
+
+func (s *storageSink) Upload(j *UploadJob, src io.ReadSeeker) (*model.UploadedFile, error) {
+	// the started upload is finished the same way
+	if j.uploadState() == nil && !s.safeUpload(j.job.File) {
+		return s.uploadFile(j, src)
+	}
+
+	ctx, cancel := context.WithCancel(j.ctx)
+	defer cancel()
+
+	stream, err := s.storage.API().SafeUploadFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resume := j.uploadState() != nil
+
+	part, err := s.open(j, stream)
+	if err != nil {
+		if resume && status.Code(err) == codes.NotFound {
+			j.log.Warn("upload session expired, restarting from the beginning", wlog.Err(err))
+			j.setUploadState(nil)
+			cancel()
+
+			return s.Upload(j, src)
+		}
+
+		return nil, err
+	}
+
+	if part.GetSize() > 0 {
+		j.log.Debug(fmt.Sprintf("resume upload %s from offset %d", part.GetUploadId(), part.GetSize()))
+	}
+
+	if _, err = src.Seek(part.GetSize(), io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	ack := make(chan uploadResult, 1)
+
+	go func() {
+		f, err := s.receive(j, stream)
+		ack <- uploadResult{file: f, err: err}
+	}()
+
+	var (
+		n       int
+		readErr error
+		buf     = make([]byte, uploadChunkSize)
+	)
+
+	for readErr == nil {
+		n, readErr = src.Read(buf)
+		if n == 0 {
+			continue
+		}
+
+		err = stream.Send(&spb.SafeUploadFileRequest{
+			Data: &spb.SafeUploadFileRequest_Chunk{
+				Chunk: buf[:n],
+			},
+		})
+		if err != nil {
+			// the stream is aborted, the reason is reported by Recv
+			break
+		}
+	}
+
+	if readErr != nil && readErr != io.EOF {
+		return nil, readErr
+	}
+
+	if err == nil {
+		err = stream.CloseSend()
+	}
+
+	res := <-ack
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res.file, nil
+}
+
+// open starts a new upload or resumes the stored one and returns the part acknowledged by the storage.
+func (s *storageSink) open(j *UploadJob, stream spb.FileService_SafeUploadFileClient) (*spb.SafeUploadFileResponse_Part, error) {
+	var err error
+
+	if st := j.uploadState(); st != nil {
+		err = stream.Send(&spb.SafeUploadFileRequest{
+			Data: &spb.SafeUploadFileRequest_UploadId{
+				UploadId: st.ID,
+			},
+		})
+	} else {
+		err = stream.Send(&spb.SafeUploadFileRequest{
+			Data: &spb.SafeUploadFileRequest_Metadata_{
+				Metadata: storageMetadata(j.job.File),
+			},
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	part := res.GetPart()
+	if part == nil || part.GetUploadId() == "" {
+		return nil, errUploadNoPart
+	}
+
+	j.setUploadState(&model.UploadState{
+		ID:     part.GetUploadId(),
+		Offset: part.GetSize(),
+	})
+
+	return part, nil
+}
+
+func (s *storageSink) receive(j *UploadJob, stream spb.FileService_SafeUploadFileClient) (*model.UploadedFile, error) {
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil, errUploadNoMetadata
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch data := res.GetData().(type) {
+		case *spb.SafeUploadFileResponse_Part_:
+			s.acknowledge(j, data.Part.GetSize())
+		case *spb.SafeUploadFileResponse_Progress_:
+			s.acknowledge(j, data.Progress.GetUploaded())
+		case *spb.SafeUploadFileResponse_Metadata_:
+			if data.Metadata.GetCode() == spb.UploadStatusCode_Failed {
+				return nil, errUploadFailed
+			}
+
+			j.log.Debug(fmt.Sprintf("uploaded file %d", data.Metadata.GetFileId()),
+				wlog.Int64("size", data.Metadata.GetSize()))
+
+			return &model.UploadedFile{
+				ID: data.Metadata.GetFileId(),
+			}, nil
+		}
+	}
+}
+
+// acknowledge stores the offset confirmed by the storage, at most once per uploadSaveStep bytes.
+func (s *storageSink) acknowledge(j *UploadJob, offset int64) {
+	st := j.uploadState()
+	if st == nil || offset-st.Offset < uploadSaveStep {
+		return
+	}
+
+	j.setUploadState(&model.UploadState{
+		ID:     st.ID,
+		Offset: offset,
+	})
+}
+
+// safeUpload reports whether SafeUploadFile keeps everything the storage has to know about the file.
+func (s *storageSink) safeUpload(f *model.File) bool {
+	return s.resumable || f.UploadedBy == 0 && f.CreatedAt == 0
+}
+
+// uploadFile sends the whole file through UploadFile, a failed attempt starts from the beginning.
+func (s *storageSink) uploadFile(j *UploadJob, src io.ReadSeeker) (*model.UploadedFile, error) {
+	stream, err := s.storage.API().UploadFile(j.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.Send(&spb.UploadFileRequest{
+		Data: &spb.UploadFileRequest_Metadata_{
+			Metadata: uploadMetadata(j.job.File),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		n       int
+		readErr error
+		buf     = make([]byte, uploadChunkSize)
+	)
+
+	for readErr == nil {
+		n, readErr = src.Read(buf)
+		if n == 0 {
+			continue
+		}
+
+		err = stream.Send(&spb.UploadFileRequest{
+			Data: &spb.UploadFileRequest_Chunk{
+				Chunk: buf[:n],
+			},
+		})
+		if err != nil {
+			// the stream is aborted, the reason is reported by CloseAndRecv
+			break
+		}
+	}
+
+	if readErr != nil && readErr != io.EOF {
+		return nil, readErr
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+
+	if res.GetCode() == spb.UploadStatusCode_Failed {
+		return nil, errUploadFailed
+	}
+
+	j.log.Debug(fmt.Sprintf("uploaded file %d", res.GetFileId()), wlog.Int64("size", res.GetSize()))
+
+	return &model.UploadedFile{
+		ID: res.GetFileId(),
+	}, nil
+}
+
+func storageProperties(f *model.File) *spb.CustomFileProperties {
+	if f.StartTime == 0 {
+		return nil
+	}
+
+	return &spb.CustomFileProperties{
+		StartTime: int64(f.StartTime),
+		EndTime:   int64(f.EndTime),
+	}
+}
+
+// storageMetadata describes the file of the resumable upload, the pauses and the markers are kept in the job file
+// and the chapters only.
+func storageMetadata(f *model.File) *spb.SafeUploadFileRequest_Metadata {
+	return &spb.SafeUploadFileRequest_Metadata{
+		DomainId:          int64(f.DomainID),
+		Name:              f.Name,
+		MimeType:          f.MimeType,
+		Uuid:              f.UUID,
+		StreamResponse:    false,
+		Progress:          true,
+		Channel:           spb.UploadFileChannel(f.Channel),
+		GenerateThumbnail: true,
+		Properties:        storageProperties(f),
+	}
+}
+
+func uploadMetadata(f *model.File) *spb.UploadFileRequest_Metadata {
+	return &spb.UploadFileRequest_Metadata{
+		DomainId:          int64(f.DomainID),
+		Name:              f.Name,
+		MimeType:          f.MimeType,
+		Uuid:              f.UUID,
+		CreatedAt:         int64(f.CreatedAt),
+		StreamResponse:    false,
+		Channel:           spb.UploadFileChannel(f.Channel),
+		GenerateThumbnail: true,
+		UploadedBy:        int64(f.UploadedBy),
+		Properties:        storageProperties(f),
+	}
+}
+
+// Cancel releases the storage upload session of the abandoned job.
+func (s *storageSink) Cancel(j *UploadJob) {
+	st := j.uploadState()
+	if st == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(j.ctx, uploadCancelTimeout)
+	defer cancel()
+
+	stream, err := s.storage.API().SafeUploadFile(ctx)
+	if err != nil {
+		j.log.Error(err.Error(), wlog.Err(err))
+
+		return
+	}
+
+	for _, req := range []*spb.SafeUploadFileRequest{
+		{Data: &spb.SafeUploadFileRequest_UploadId{UploadId: st.ID}},
+		{Data: &spb.SafeUploadFileRequest_Cancel{Cancel: true}},
+	} {
+		if err = stream.Send(req); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = stream.CloseSend()
+	}
+
+	for err == nil {
+		_, err = stream.Recv()
+	}
+
+	if err != io.EOF && status.Code(err) != codes.Canceled {
+		j.log.Error(err.Error(), wlog.Err(err))
+	}
+
+	j.log.Debug(fmt.Sprintf("upload %s canceled", st.ID))
+	j.setUploadState(nil)
+}
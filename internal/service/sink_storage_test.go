@@ -0,0 +1,204 @@
+package service
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	spb "github.com/webitel/webrtc_recorder/gen/storage"
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+type fakeUploadStream struct {
+	spb.FileService_SafeUploadFileClient
+
+	sent []*spb.SafeUploadFileRequest
+	recv []*spb.SafeUploadFileResponse
+}
+
+func (s *fakeUploadStream) Send(req *spb.SafeUploadFileRequest) error {
+	s.sent = append(s.sent, req)
+
+	return nil
+}
+
+func (s *fakeUploadStream) Recv() (*spb.SafeUploadFileResponse, error) {
+	if len(s.recv) == 0 {
+		return nil, io.EOF
+	}
+
+	res := s.recv[0]
+	s.recv = s.recv[1:]
+
+	return res, nil
+}
+
+func (s *fakeJobStore) SetConfig(_ int, _ *model.JobConfig) error {
+	return nil
+}
+
+func partResponse(id string, size int64) *spb.SafeUploadFileResponse {
+	return &spb.SafeUploadFileResponse{
+		Data: &spb.SafeUploadFileResponse_Part_{
+			Part: &spb.SafeUploadFileResponse_Part{UploadId: id, Size: size},
+		},
+	}
+}
+
+func newTestStorageJob(st *model.UploadState) *UploadJob {
+	j := newTestUploadJob(&model.File{DomainID: 1, Name: "call", UUID: "rec-1"})
+	j.job.Config.Upload = st
+
+	return j
+}
+
+func TestStorageSink_Open(t *testing.T) {
+	testCases := []struct {
+		name     string
+		state    *model.UploadState
+		recv     []*spb.SafeUploadFileResponse
+		resumed  bool
+		expected *model.UploadState
+		err      error
+	}{
+		{
+			name:     "new upload",
+			recv:     []*spb.SafeUploadFileResponse{partResponse("u1", 0)},
+			expected: &model.UploadState{ID: "u1"},
+		},
+		{
+			name:     "resume from the acknowledged part",
+			state:    &model.UploadState{ID: "u1", Offset: uploadSaveStep},
+			recv:     []*spb.SafeUploadFileResponse{partResponse("u1", uploadSaveStep+uploadChunkSize)},
+			resumed:  true,
+			expected: &model.UploadState{ID: "u1", Offset: uploadSaveStep + uploadChunkSize},
+		},
+		{
+			name:     "storage without upload id",
+			state:    &model.UploadState{ID: "u1"},
+			recv:     []*spb.SafeUploadFileResponse{partResponse("", 10)},
+			resumed:  true,
+			expected: &model.UploadState{ID: "u1"},
+			err:      errUploadNoPart,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			j := newTestStorageJob(tc.state)
+			stream := &fakeUploadStream{recv: tc.recv}
+
+			_, err := (&storageSink{}).open(j, stream)
+			require.ErrorIs(t, err, tc.err)
+			require.Len(t, stream.sent, 1)
+
+			if tc.resumed {
+				assert.Equal(t, tc.state.ID, stream.sent[0].GetUploadId())
+			} else {
+				assert.Equal(t, "call", stream.sent[0].GetMetadata().GetName())
+			}
+
+			assert.Equal(t, tc.expected, j.uploadState())
+		})
+	}
+}
+
+func TestStorageSink_Receive(t *testing.T) {
+	progress := func(n int64) *spb.SafeUploadFileResponse {
+		return &spb.SafeUploadFileResponse{
+			Data: &spb.SafeUploadFileResponse_Progress_{
+				Progress: &spb.SafeUploadFileResponse_Progress{Uploaded: n},
+			},
+		}
+	}
+	metadata := func(code spb.UploadStatusCode) *spb.SafeUploadFileResponse {
+		return &spb.SafeUploadFileResponse{
+			Data: &spb.SafeUploadFileResponse_Metadata_{
+				Metadata: &spb.SafeUploadFileResponse_Metadata{FileId: 7, Code: code},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		recv   []*spb.SafeUploadFileResponse
+		offset int64
+		file   *model.UploadedFile
+		err    error
+	}{
+		{
+			name:   "uploaded",
+			recv:   []*spb.SafeUploadFileResponse{progress(uploadSaveStep), metadata(spb.UploadStatusCode_Ok)},
+			offset: uploadSaveStep,
+			file:   &model.UploadedFile{ID: 7},
+		},
+		{
+			name: "progress below the save step is not stored",
+			recv: []*spb.SafeUploadFileResponse{progress(uploadChunkSize)},
+			err:  errUploadNoMetadata,
+		},
+		{
+			name:   "acknowledged part",
+			recv:   []*spb.SafeUploadFileResponse{partResponse("u1", uploadSaveStep*2)},
+			offset: uploadSaveStep * 2,
+			err:    errUploadNoMetadata,
+		},
+		{
+			name: "rejected by the storage",
+			recv: []*spb.SafeUploadFileResponse{metadata(spb.UploadStatusCode_Failed)},
+			err:  errUploadFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			j := newTestStorageJob(&model.UploadState{ID: "u1"})
+
+			f, err := (&storageSink{}).receive(j, &fakeUploadStream{recv: tc.recv})
+			require.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.file, f)
+			assert.Equal(t, tc.offset, j.uploadState().Offset)
+		})
+	}
+}
+
+func TestStorageSink_safeUpload(t *testing.T) {
+	testCases := []struct {
+		name      string
+		file      *model.File
+		resumable bool
+		expected  bool
+	}{
+		{
+			name:     "file without owner",
+			file:     &model.File{DomainID: 1, Name: "data.jsonl"},
+			expected: true,
+		},
+		{
+			name: "recording of the user",
+			file: &model.File{DomainID: 1, Name: "call", UploadedBy: 10, CreatedAt: 1000},
+		},
+		{
+			name:      "resumable uploads",
+			file:      &model.File{DomainID: 1, Name: "call", UploadedBy: 10, CreatedAt: 1000},
+			resumable: true,
+			expected:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, (&storageSink{resumable: tc.resumable}).safeUpload(tc.file))
+		})
+	}
+}
+
+func TestUploadMetadata(t *testing.T) {
+	md := uploadMetadata(&model.File{DomainID: 1, Name: "call", UUID: "rec-1", UploadedBy: 10, CreatedAt: 1000})
+
+	assert.Equal(t, int64(10), md.GetUploadedBy())
+	assert.Equal(t, int64(1000), md.GetCreatedAt())
+	assert.Equal(t, "rec-1", md.GetUuid())
+}
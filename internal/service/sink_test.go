@@ -0,0 +1,230 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func newTestUploadJob(f *model.File) *UploadJob {
+	return &UploadJob{
+		baseJob: &baseJob{
+			job: &model.Job{
+				ID:     1,
+				Type:   UploadJobName,
+				File:   f,
+				Config: &model.JobConfig{},
+			},
+			ctx: context.Background(),
+			log: wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false}),
+		},
+		svc: &Uploader{jobHandler: jobHandler{jobStore: &fakeJobStore{}}},
+	}
+}
+
+func TestObjectKey(t *testing.T) {
+	created := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		file     model.File
+		expected string
+	}{
+		{
+			name:     "uuid with temp file ext",
+			file:     model.File{DomainID: 1, UUID: "rec-1", Path: "/tmp/abc.mp4", CreatedAt: int(created.UnixMilli())},
+			expected: "1/2025-03-14/rec-1_7.mp4",
+		},
+		{
+			name:     "empty uuid uses temp file name",
+			file:     model.File{DomainID: 2, Path: "/tmp/abc.mp4", CreatedAt: int(created.UnixMilli())},
+			expected: "2/2025-03-14/abc.mp4",
+		},
+		{
+			name:     "uuid escaping the domain dir",
+			file:     model.File{DomainID: 3, UUID: "../../etc/passwd", Path: "/tmp/abc.mp4", CreatedAt: int(created.UnixMilli())},
+			expected: "3/2025-03-14/abc.mp4",
+		},
+		{
+			name:     "segment of the recording",
+			file:     model.File{DomainID: 1, UUID: "rec-1", Path: "/tmp/abc.mp4", Segment: 2, CreatedAt: int(created.UnixMilli())},
+			expected: "1/2025-03-14/rec-1_7_part002.mp4",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, objectKey(&model.Job{ID: 7, File: &tc.file}))
+		})
+	}
+}
+
+func TestLocalSink_Upload(t *testing.T) {
+	dir := t.TempDir()
+	sink := newLocalSink(dir)
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+
+	j := newTestUploadJob(&model.File{
+		DomainID:  1,
+		UUID:      "rec-1",
+		Path:      "/tmp/abc.mp4",
+		CreatedAt: int(time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC).UnixMilli()),
+	})
+	dst := filepath.Join(dir, "1", "2025-03-14", "rec-1_1.mp4")
+
+	testCases := []struct {
+		name     string
+		part     []byte
+		state    *model.UploadState
+		expected []byte
+	}{
+		{
+			name:     "resume from partial file",
+			part:     bytes.Repeat([]byte("x"), 4000),
+			state:    &model.UploadState{ID: dst, Size: int64(len(data))},
+			expected: append(bytes.Repeat([]byte("x"), 4000), data[4000:]...),
+		},
+		{
+			name: "partial file of other job",
+			part: bytes.Repeat([]byte("x"), 4000),
+		},
+		{
+			name:  "partial file of other content",
+			part:  bytes.Repeat([]byte("x"), 4000),
+			state: &model.UploadState{ID: dst, Size: int64(len(data)) + 1},
+		},
+		{
+			name:  "partial file larger than the source",
+			part:  bytes.Repeat([]byte("x"), len(data)+10),
+			state: &model.UploadState{ID: dst, Size: int64(len(data))},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755))
+			require.NoError(t, os.WriteFile(dst+localPartExt, tc.part, 0o644))
+			j.job.Config.Upload = tc.state
+
+			res, err := sink.Upload(j, bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, dst, res.Location)
+			assert.Equal(t, &model.UploadState{ID: dst, Size: int64(len(data))}, j.uploadState())
+
+			expected := tc.expected
+			if expected == nil {
+				expected = data
+			}
+
+			out, err := os.ReadFile(dst)
+			require.NoError(t, err)
+			assert.Equal(t, expected, out)
+			assert.NoFileExists(t, dst+localPartExt)
+		})
+	}
+
+	t.Run("Cancel removes partial file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(dst+localPartExt, data[:10], 0o644))
+
+		sink.Cancel(j)
+
+		assert.NoFileExists(t, dst+localPartExt)
+		assert.Nil(t, j.uploadState())
+	})
+}
+
+func TestLocalSink_UploadSegments(t *testing.T) {
+	dir := t.TempDir()
+	sink := newLocalSink(dir)
+	created := int(time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC).UnixMilli())
+
+	parts := map[string][]byte{
+		"rec-1_1_part001.mp4": []byte("first segment"),
+		"rec-1_1_part002.mp4": []byte("second segment"),
+	}
+
+	for i, name := range []string{"rec-1_1_part001.mp4", "rec-1_1_part002.mp4"} {
+		j := newTestUploadJob(&model.File{
+			DomainID:  1,
+			UUID:      "rec-1",
+			Path:      filepath.Join("/tmp", name),
+			Segment:   i + 1,
+			CreatedAt: created,
+		})
+
+		_, err := sink.Upload(j, bytes.NewReader(parts[name]))
+		require.NoError(t, err)
+	}
+
+	for name, data := range parts {
+		out, err := os.ReadFile(filepath.Join(dir, "1", "2025-03-14", name))
+		require.NoError(t, err)
+		assert.Equal(t, data, out)
+	}
+}
+
+func TestLocalSink_UploadSameUUID(t *testing.T) {
+	dir := t.TempDir()
+	sink := newLocalSink(dir)
+	created := int(time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC).UnixMilli())
+
+	recordings := map[int][]byte{
+		1: []byte("first recording"),
+		2: []byte("second recording"),
+	}
+
+	for _, id := range []int{1, 2} {
+		j := newTestUploadJob(&model.File{DomainID: 1, UUID: "rec-1", Path: "/tmp/abc.mp4", CreatedAt: created})
+		j.job.ID = id
+
+		_, err := sink.Upload(j, bytes.NewReader(recordings[id]))
+		require.NoError(t, err)
+	}
+
+	for id, data := range recordings {
+		out, err := os.ReadFile(filepath.Join(dir, "1", "2025-03-14", fmt.Sprintf("rec-1_%d.mp4", id)))
+		require.NoError(t, err)
+		assert.Equal(t, data, out)
+	}
+}
+
+func TestS3Metadata(t *testing.T) {
+	testCases := []struct {
+		name     string
+		file     model.File
+		expected map[string]string
+	}{
+		{
+			name: "ascii name",
+			file: model.File{DomainID: 1, UploadedBy: 10, Name: "call", UUID: "rec-1"},
+			expected: map[string]string{
+				"domain-id": "1", "uploaded-by": "10", "name": "call", "uuid": "rec-1",
+			},
+		},
+		{
+			name: "non-ascii name",
+			file: model.File{DomainID: 1, UploadedBy: 10, Name: "дзвінок 1", UUID: "rec-1", StartTime: 1000, EndTime: 2000},
+			expected: map[string]string{
+				"domain-id": "1", "uploaded-by": "10", "name": "%D0%B4%D0%B7%D0%B2%D1%96%D0%BD%D0%BE%D0%BA+1", "uuid": "rec-1",
+				"start-time": "1000", "end-time": "2000",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, s3Metadata(&tc.file))
+		})
+	}
+}
@@ -1,18 +1,27 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+	"syscall"
 
 	"github.com/webitel/webrtc_recorder/config"
 	"github.com/webitel/webrtc_recorder/internal/model"
 )
 
+const (
+	manifestExt   = ".session.json"
+	quarantineDir = "quarantine"
+)
+
 type TempFileService struct {
-	dir string
+	dir        string
+	quarantine string
 }
 
 func NewTempFileService(cfg *config.Config) *TempFileService {
@@ -28,13 +37,106 @@ func NewTempFileService(cfg *config.Config) *TempFileService {
 		panic(err)
 	}
 
+	quarantine := cfg.QuarantineDir
+	if quarantine == "" {
+		quarantine = path.Join(dir, quarantineDir)
+	}
+
+	if err = os.MkdirAll(quarantine, 0o755); err != nil {
+		panic(err)
+	}
+
+	quarantine, err = filepath.Abs(quarantine)
+	if err != nil {
+		panic(err)
+	}
+
 	return &TempFileService{
-		dir: dir,
+		dir:        dir,
+		quarantine: quarantine,
 	}
 }
 
 func (svc *TempFileService) Dir() string { return svc.dir }
 
+// Quarantine moves the files of the failed job out of the temp dir and updates their paths,
+// they are kept until an operator retries or deletes the job.
+func (svc *TempFileService) Quarantine(file *model.File) error {
+	var err error
+
+	for i := range file.Track {
+		if p, moveErr := svc.toQuarantine(file.Track[i].Path); moveErr != nil {
+			err = errors.Join(err, moveErr)
+		} else {
+			file.Track[i].Path = p
+		}
+	}
+
+	if file.Path != "" {
+		if p, moveErr := svc.toQuarantine(file.Path); moveErr != nil {
+			err = errors.Join(err, moveErr)
+		} else {
+			file.Path = p
+		}
+	}
+
+	return err
+}
+
+// toQuarantine returns the new path of the file, a file that does not exist anymore keeps its path.
+func (svc *TempFileService) toQuarantine(src string) (string, error) {
+	if src == "" || filepath.Dir(src) == svc.quarantine {
+		return src, nil
+	}
+
+	dst := path.Join(svc.quarantine, filepath.Base(src))
+
+	err := moveFile(src, dst)
+	if os.IsNotExist(err) {
+		return src, nil
+	}
+
+	if err != nil {
+		return src, err
+	}
+
+	return dst, nil
+}
+
+// moveFile renames the file, falling back to copying when the destination is on another device.
+func moveFile(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+
+		return err
+	}
+
+	if err = out.Close(); err != nil {
+		_ = os.Remove(dst)
+
+		return err
+	}
+
+	return os.Remove(src)
+}
+
 func (svc *TempFileService) DeleteFile(file *model.File) error {
 	if len(file.Track) == 0 {
 		return errors.New("file path is empty")
@@ -51,7 +153,7 @@ func (svc *TempFileService) DeleteFile(file *model.File) error {
 	return err
 }
 
-func (svc *TempFileService) NewReader(file model.File) (io.ReadCloser, error) {
+func (svc *TempFileService) NewReader(file model.File) (io.ReadSeekCloser, error) {
 	return os.Open(file.Path)
 }
 
@@ -78,3 +180,50 @@ func (svc *TempFileService) NewFilePath(file *model.File, ext string) error {
 
 	return nil
 }
+
+func (svc *TempFileService) manifestPath(id string) string {
+	return path.Join(svc.dir, id+manifestExt)
+}
+
+// SaveManifest atomically replaces the manifest of the recording session.
+func (svc *TempFileService) SaveManifest(m *model.SessionManifest) error {
+	p := svc.manifestPath(m.ID)
+
+	err := os.WriteFile(p+".tmp", m.JSON(), 0o644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(p+".tmp", p)
+}
+
+func (svc *TempFileService) RemoveManifest(id string) error {
+	return os.Remove(svc.manifestPath(id))
+}
+
+// Manifests returns the paths of all session manifests in the temp dir.
+func (svc *TempFileService) Manifests() ([]string, error) {
+	return filepath.Glob(path.Join(svc.dir, "*"+manifestExt))
+}
+
+func (svc *TempFileService) ReadManifest(p string) (*model.SessionManifest, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var m model.SessionManifest
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	if m.File == nil {
+		return nil, errors.New("manifest file is empty")
+	}
+
+	return &m, nil
+}
+
+func IsManifest(p string) bool {
+	return strings.HasSuffix(p, manifestExt) || strings.HasSuffix(p, manifestExt+".tmp")
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func TestTempFileService_Quarantine(t *testing.T) {
+	dir := t.TempDir()
+	tmp := &TempFileService{dir: dir, quarantine: filepath.Join(dir, quarantineDir)}
+	require.NoError(t, os.MkdirAll(tmp.quarantine, 0o755))
+
+	track := filepath.Join(dir, "a.raw")
+	require.NoError(t, os.WriteFile(track, []byte("raw"), 0o644))
+
+	f := &model.File{
+		Path: filepath.Join(dir, "deleted.mp4"),
+		Track: []model.MediaChannel{
+			{Path: track},
+		},
+	}
+
+	require.NoError(t, tmp.Quarantine(f))
+
+	assert.Equal(t, filepath.Join(tmp.quarantine, "a.raw"), f.Track[0].Path)
+	assert.FileExists(t, f.Track[0].Path)
+	assert.NoFileExists(t, track)
+	// missing files keep their path
+	assert.Equal(t, filepath.Join(dir, "deleted.mp4"), f.Path)
+
+	// quarantined files stay in place
+	require.NoError(t, tmp.Quarantine(f))
+	assert.Equal(t, filepath.Join(tmp.quarantine, "a.raw"), f.Track[0].Path)
+}
@@ -2,6 +2,12 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/webitel/wlog"
@@ -16,9 +22,18 @@ const TranscodingJobName = "transcoding"
 type FileJobStore interface {
 	Create(jobType string, cfg *model.JobConfig, f *model.File) error
 	Update(state model.JobState, j *model.Job) error
-	SetError(id int, err error) error
+	SetError(j *model.Job, state model.JobState, e *model.JobError, delay time.Duration) error
+	SetConfig(id int, cfg *model.JobConfig) error
 	Fetch(limit int, jobType string) ([]*model.Job, error)
 	Delete(id int) error
+	Paths(all bool) ([]string, error)
+	Claim(limit int, jobType string, alive []string) ([]*model.Job, error)
+	Renew() error
+	List(filter *model.JobFilter) ([]*model.Job, error)
+	Get(domainID, id int) (*model.Job, error)
+	Retry(domainID, id int) (*model.Job, error)
+	Restart(domainID int, j *model.Job) (*model.Job, error)
+	Cancel(domainID, id int) (*model.Job, error)
 }
 
 type Transcoding struct {
@@ -28,6 +43,9 @@ type Transcoding struct {
 	maxRetry int
 	pool     *utils.Pool
 	uploader *Uploader
+	// profile names of the recordings that do not set one
+	profile        string
+	domainProfiles map[int]string
 }
 
 type transcodingJob struct {
@@ -36,30 +54,98 @@ type transcodingJob struct {
 	svc *Transcoding
 }
 
-func NewTranscoding(ctx context.Context, cfg *config.Config, log *wlog.Logger, fjs FileJobStore, tmp *TempFileService, upl *Uploader) *Transcoding {
+func NewTranscoding(ctx context.Context, cfg *config.Config, log *wlog.Logger, fjs FileJobStore, tmp *TempFileService, upl *Uploader,
+	ev *SessionEvents, lease *JobLease,
+) (*Transcoding, error) {
+	domainProfiles, err := parseDomainProfiles(cfg.Transcoding.DomainProfiles.Value())
+	if err != nil {
+		return nil, err
+	}
+
+	profile := cfg.Transcoding.Profile
+	if profile == "" {
+		profile = model.DefaultTranscodingProfile
+	}
+
+	for _, name := range append([]string{profile}, slices.Collect(maps.Values(domainProfiles))...) {
+		if _, ok := model.TranscodingProfiles[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", model.ErrUnknownProfile, name)
+		}
+	}
+
 	tr := &Transcoding{
 		jobHandler: jobHandler{
 			ctx:      ctx,
 			jobStore: fjs,
 			log:      log,
 			tempFile: tmp,
+			events:   ev,
+			backoff:  newBackoff(cfg.Transcoding.Backoff),
+			lease:    lease,
 		},
 		uploader: upl,
 		maxRetry: cfg.Transcoding.MaxRetry,
 		limit:    cfg.Transcoding.Queue + cfg.Transcoding.Workers,
 		pool:     utils.NewPool(ctx, cfg.Transcoding.Workers, cfg.Transcoding.Queue),
+
+		profile:        profile,
+		domainProfiles: domainProfiles,
 	}
 
 	go tr.listen()
 
-	return tr
+	return tr, nil
 }
 
-func (svc *Transcoding) CreateJob(f *model.File) error {
-	return svc.jobStore.Create(TranscodingJobName, &model.JobConfig{}, f)
+// parseDomainProfiles reads the domain_id=profile pairs.
+func parseDomainProfiles(list []string) (map[int]string, error) {
+	res := make(map[int]string, len(list))
+
+	for _, v := range list {
+		domain, name, ok := strings.Cut(v, "=")
+		id, err := strconv.Atoi(domain)
+		if !ok || err != nil || name == "" {
+			return nil, fmt.Errorf("bad domain transcoding profile %q, expected domain_id=profile", v)
+		}
+
+		res[id] = name
+	}
+
+	return res, nil
 }
 
-func (svc *Transcoding) successJob(j *transcodingJob, trFile *model.File) {
+// Profile resolves the transcoding profile of the recording: the named one or the default of the domain,
+// overridden by the explicit parameters.
+func (svc *Transcoding) Profile(domainID int, p *model.TranscodingProfile) (*model.TranscodingProfile, error) {
+	name := svc.profile
+	if n, ok := svc.domainProfiles[domainID]; ok {
+		name = n
+	}
+
+	if p != nil && p.Name != "" {
+		name = p.Name
+	}
+
+	base, ok := model.TranscodingProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", model.ErrUnknownProfile, name)
+	}
+
+	res := base.With(p)
+	res.Name = name
+
+	if err := res.Validate(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (svc *Transcoding) CreateJob(cfg *model.JobConfig, f *model.File) error {
+	return svc.jobStore.Create(TranscodingJobName, cfg, f)
+}
+
+func (svc *Transcoding) successJob(j *transcodingJob, trFile, sidecar *model.File) {
 	var err error
 	if err = svc.tempFile.DeleteFile(j.job.File); err != nil {
 		j.log.Error(err.Error(), wlog.Err(err))
@@ -71,10 +157,35 @@ func (svc *Transcoding) successJob(j *transcodingJob, trFile *model.File) {
 	uploadJob.Type = UploadJobName
 	uploadJob.Retry = 0
 
+	// the upload left by the previous run of the job belongs to other content
+	if j.job.Config != nil && j.job.Config.Upload != nil {
+		cfg := *j.job.Config
+		cfg.Upload = nil
+		uploadJob.Config = &cfg
+	}
+
 	err = svc.jobStore.Update(model.JobIdle, &uploadJob)
 	if err != nil {
 		j.log.Error(err.Error(), wlog.Err(err))
 	}
+
+	if sidecar != nil {
+		cfg := &model.JobConfig{}
+		if j.job.Config != nil {
+			*cfg = *j.job.Config
+			cfg.Upload = nil
+		}
+
+		// the feed of the session waits for the sidecar as well
+		svc.events.jobCreated(sidecar.SessionID)
+
+		if err = svc.jobStore.Create(UploadJobName, cfg, sidecar); err != nil {
+			j.log.Error(err.Error(), wlog.Err(err))
+			svc.events.jobFinished(sidecar.SessionID)
+		}
+	}
+
+	svc.jobEvent(j.baseJob, model.JobEventDone, nil)
 }
 
 func (svc *Transcoding) listen() {
@@ -93,7 +204,7 @@ func (svc *Transcoding) listen() {
 		case <-svc.ctx.Done():
 			return
 		case <-ticker.C:
-			jobs, err := svc.jobStore.Fetch(svc.limit, TranscodingJobName)
+			jobs, err := svc.fetch(svc.limit, TranscodingJobName)
 			if err != nil {
 				svc.log.Error(err.Error(), wlog.Err(err))
 				time.Sleep(time.Second)
@@ -119,38 +230,131 @@ func (svc *Transcoding) listen() {
 
 func (j *transcodingJob) Execute() {
 	j.log.Debug("execute")
+	j.svc.jobEvent(j.baseJob, model.JobEventActive, nil)
 
 	var err error
 
-	mp4File := *j.job.File
-	mp4File.Path = ""
-	mp4File.MimeType = "video/mp4" // TODO
+	var (
+		profile *model.TranscodingProfile
+		layout  string
+	)
+
+	if j.job.Config != nil {
+		layout = j.job.Config.Layout
+	}
+
+	if j.job.Config != nil && j.job.Config.Profile != nil {
+		profile = j.job.Config.Profile
+	} else {
+		// jobs created before the profiles or by the recovery
+		profile, err = j.svc.Profile(j.job.File.DomainID, nil)
+	}
+
+	trFile := *j.job.File
+	trFile.Path = ""
+
+	var sidecar *model.File
 
 	now := time.Now()
 
 	defer func() {
 		if err != nil {
+			if sidecar != nil {
+				_ = os.Remove(sidecar.Path)
+			}
+
 			j.svc.errorJob(j.baseJob, j.svc.maxRetry, err)
 		} else {
 			j.log.Debug("success job", wlog.Duration("duration", time.Since(now)))
-			j.svc.successJob(j, &mp4File)
+			j.svc.successJob(j, &trFile, sidecar)
 		}
 	}()
 
-	err = j.svc.tempFile.NewFilePath(&mp4File, "mp4")
 	if err != nil {
 		return
 	}
+
+	media := make([]model.MediaChannel, 0, len(j.job.File.Track))
+	for _, ch := range j.job.File.Track {
+		if ch.IsMedia() {
+			media = append(media, ch)
+		}
+	}
+
+	var msgs []model.DataMessage
+
+	msgs, err = utils.ReadDataTracks(j.job.File.Track)
+	if err != nil {
+		return
+	}
+
+	// the tracks that fit a container are copied as they are
+	remux := false
+	if profile.Remux() {
+		if c, ok := utils.RemuxContainer(media); ok {
+			out := *profile
+			out.Container = c
+			profile, remux = &out, true
+		}
+	}
+
+	trFile.MimeType = profile.MimeType()
+
+	err = j.svc.tempFile.NewFilePath(&trFile, profile.Ext())
+	if err != nil {
+		return
+	}
+
+	var subtitles string
+	if profile.SubtitleCodec() != "" {
+		if subtitles, err = utils.WriteSubtitles(msgs, trFile.Path); err != nil {
+			return
+		}
+
+		if subtitles != "" {
+			defer os.Remove(subtitles)
+		}
+	}
+
 	actualDurationMs := j.job.File.EndTime - j.job.File.StartTime
 	var durationMs int
-	durationMs, err = utils.TranscodingByPath(j.job.File.Track, mp4File.Path, actualDurationMs)
+	if remux {
+		j.log.Debug("remux to " + profile.Container)
+		durationMs, err = utils.RemuxByPath(media, j.job.File.Markers, subtitles, trFile.Path, actualDurationMs, profile)
+	} else {
+		durationMs, err = utils.TranscodingByPath(media, j.job.File.Markers, subtitles, trFile.Path, actualDurationMs,
+			layout, profile)
+	}
 
 	if err != nil {
-		_ = j.svc.tempFile.DeleteFile(&mp4File)
+		// the raw tracks are kept for the next attempt
+		_ = os.Remove(trFile.Path)
 		return
 	}
 
-	if durationMs > 0 && mp4File.StartTime > 0 {
-		mp4File.EndTime = mp4File.StartTime + durationMs
+	if len(msgs) > 0 {
+		// the messages of the data channels are uploaded next to the recording
+		sidecar = &model.File{}
+		*sidecar = trFile
+		sidecar.Path, sidecar.Name = "", trFile.Name+".jsonl"
+		sidecar.Track, sidecar.Markers, sidecar.Pauses = nil, nil, nil
+		sidecar.MimeType = model.DataMimeType
+
+		if err = j.svc.tempFile.NewFilePath(sidecar, "jsonl"); err != nil {
+			_ = os.Remove(trFile.Path)
+			sidecar = nil
+
+			return
+		}
+
+		if err = utils.WriteDataSidecar(msgs, sidecar.Path); err != nil {
+			_ = os.Remove(trFile.Path)
+
+			return
+		}
+	}
+
+	if durationMs > 0 && trFile.StartTime > 0 {
+		trFile.EndTime = trFile.StartTime + durationMs
 	}
 }
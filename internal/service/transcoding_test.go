@@ -0,0 +1,122 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/wlog"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func TestTranscoding_Profile(t *testing.T) {
+	domainProfiles, err := parseDomainProfiles([]string{"2=archive"})
+	require.NoError(t, err)
+
+	_, err = parseDomainProfiles([]string{"archive"})
+	require.Error(t, err)
+
+	svc := &Transcoding{profile: model.DefaultTranscodingProfile, domainProfiles: domainProfiles}
+
+	p, err := svc.Profile(1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultTranscodingProfile, p.Name)
+	assert.Equal(t, 1920, p.Width)
+	assert.True(t, p.Remux())
+
+	p, err = svc.Profile(2, &model.TranscodingProfile{Width: 640, Height: 360})
+	require.NoError(t, err)
+	assert.Equal(t, "archive", p.Name)
+	assert.Equal(t, 640, p.Width)
+	assert.Equal(t, 15, p.FPS)
+	assert.False(t, p.Remux())
+
+	// the codecs follow the container
+	p, err = svc.Profile(1, &model.TranscodingProfile{Container: "webm"})
+	require.NoError(t, err)
+	assert.Equal(t, "libvpx-vp9", p.VideoCodec)
+	assert.Equal(t, "libopus", p.AudioCodec)
+	assert.Equal(t, "video/webm", p.MimeType())
+	// the explicit output turns the passthrough off
+	assert.False(t, p.Remux())
+
+	_, err = svc.Profile(1, &model.TranscodingProfile{Name: "unknown"})
+	require.ErrorIs(t, err, model.ErrUnknownProfile)
+
+	_, err = svc.Profile(1, &model.TranscodingProfile{Container: "webm", VideoCodec: "libx264"})
+	require.ErrorIs(t, err, model.ErrInvalidProfile)
+
+	_, err = svc.Profile(1, &model.TranscodingProfile{VideoBitrate: "1k; rm"})
+	require.ErrorIs(t, err, model.ErrInvalidProfile)
+}
+
+func (s *fakeJobStore) Update(_ model.JobState, j *model.Job) error {
+	s.jobs = append(s.jobs, j)
+
+	return nil
+}
+
+func (s *fakeJobStore) Delete(_ int) error {
+	return nil
+}
+
+func TestTranscoding_successJobSidecar(t *testing.T) {
+	dir := t.TempDir()
+	log := wlog.NewLogger(&wlog.LoggerConfiguration{EnableConsole: false})
+	events := NewSessionEvents(log)
+	fjs := &fakeJobStore{}
+	handler := jobHandler{
+		jobStore: fjs,
+		log:      log,
+		tempFile: &TempFileService{dir: dir},
+		events:   events,
+	}
+	tr := &Transcoding{jobHandler: handler}
+	upl := &Uploader{jobHandler: handler}
+
+	raw := filepath.Join(dir, "a.raw")
+	require.NoError(t, os.WriteFile(raw, []byte("raw"), 0o644))
+
+	events.open("s1", 1)
+	ch, cancel, err := events.Subscribe("s1", 1, nil)
+	require.NoError(t, err)
+	defer cancel()
+
+	// the session closed with its transcoding job pending
+	events.jobCreated("s1")
+	events.sessionClosed("s1")
+
+	recording := &model.File{SessionID: "s1", Path: filepath.Join(dir, "a.mp4")}
+	sidecar := &model.File{SessionID: "s1", Path: filepath.Join(dir, "a.jsonl")}
+	tr.successJob(&transcodingJob{
+		svc: tr,
+		baseJob: &baseJob{
+			log: log,
+			job: &model.Job{ID: 1, Type: TranscodingJobName, File: &model.File{SessionID: "s1", Track: []model.MediaChannel{{Path: raw}}}},
+		},
+	}, recording, sidecar)
+
+	require.Len(t, fjs.jobs, 2)
+
+	saved := func(j *model.Job) {
+		(&UploadJob{svc: upl, baseJob: &baseJob{log: log, job: j}}).saved(&model.UploadedFile{ID: int64(j.ID)})
+	}
+
+	// the sidecar is uploaded before the recording
+	fjs.jobs[1].ID = 2
+	saved(fjs.jobs[1])
+	saved(fjs.jobs[0])
+
+	var files []int64
+	for ev := range ch {
+		if ev.Type == model.EventFileSaved {
+			files = append(files, ev.Job.FileID)
+		}
+	}
+
+	assert.Equal(t, []int64{2, 1}, files)
+}
@@ -2,14 +2,15 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"time"
 
 	"github.com/webitel/wlog"
 
 	"github.com/webitel/webrtc_recorder/config"
-	spb "github.com/webitel/webrtc_recorder/gen/storage"
 	"github.com/webitel/webrtc_recorder/infra/storage"
+	"github.com/webitel/webrtc_recorder/internal/model"
 	"github.com/webitel/webrtc_recorder/internal/utils"
 )
 
@@ -21,7 +22,8 @@ type Uploader struct {
 	jobHandler
 
 	pool     *utils.Pool
-	storage  *storage.Storage
+	sinks    map[string]Sink
+	sink     string
 	limit    int
 	maxRetry int
 }
@@ -32,23 +34,85 @@ type UploadJob struct {
 	svc *Uploader
 }
 
-func NewUploader(ctx context.Context, cfg *config.Config, log *wlog.Logger, fjs FileJobStore, tmp *TempFileService, st *storage.Storage) *Uploader {
+func NewUploader(ctx context.Context, cfg *config.Config, log *wlog.Logger, fjs FileJobStore, tmp *TempFileService, st *storage.Storage,
+	ev *SessionEvents, lease *JobLease,
+) (*Uploader, error) {
+	sinks := map[string]Sink{
+		SinkStorage: newStorageSink(st, cfg.Uploader.Resumable),
+	}
+
+	if cfg.Uploader.LocalDir != "" {
+		sinks[SinkLocal] = newLocalSink(cfg.Uploader.LocalDir)
+	}
+
+	if cfg.Uploader.S3.Endpoint != "" {
+		s3, err := newS3Sink(&cfg.Uploader.S3)
+		if err != nil {
+			return nil, err
+		}
+
+		sinks[SinkS3] = s3
+	}
+
 	u := &Uploader{
 		jobHandler: jobHandler{
 			log:      log.With(wlog.String("service", "uploader")),
 			tempFile: tmp,
 			jobStore: fjs,
 			ctx:      ctx,
+			events:   ev,
+			backoff:  newBackoff(cfg.Uploader.Backoff),
+			lease:    lease,
 		},
-		storage:  st,
+		sinks:    sinks,
+		sink:     cfg.Uploader.Sink,
 		maxRetry: cfg.Uploader.MaxRetry,
 		limit:    cfg.Uploader.Queue + cfg.Uploader.Workers,
 		pool:     utils.NewPool(ctx, cfg.Uploader.Workers, cfg.Uploader.Queue),
 	}
 
+	if _, err := u.Sink(""); err != nil {
+		return nil, err
+	}
+
 	go u.listen()
 
-	return u
+	return u, nil
+}
+
+// Sink returns the upload destination by name, an empty name selects the default one.
+func (svc *Uploader) Sink(name string) (Sink, error) {
+	if name == "" {
+		name = svc.sink
+	}
+
+	if s, ok := svc.sinks[name]; ok {
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrSinkNotFound, name)
+}
+
+// cancel releases the partial upload of the job that will not be resumed.
+func (svc *Uploader) cancel(job *model.Job) {
+	var name string
+	if job.Config != nil {
+		name = job.Config.Sink
+	}
+
+	sink, err := svc.Sink(name)
+	if err != nil {
+		return
+	}
+
+	sink.Cancel(&UploadJob{
+		svc: svc,
+		baseJob: &baseJob{
+			job: job,
+			ctx: svc.ctx,
+			log: svc.log.With(wlog.Int("job_id", job.ID), wlog.String("job_type", job.Type)),
+		},
+	})
 }
 
 func (svc *Uploader) listen() {
@@ -67,7 +131,7 @@ func (svc *Uploader) listen() {
 		case <-svc.ctx.Done():
 			return
 		case <-ticker.C:
-			jobs, err := svc.jobStore.Fetch(svc.limit, UploadJobName)
+			jobs, err := svc.fetch(svc.limit, UploadJobName)
 			if err != nil {
 				svc.log.Error(err.Error(), wlog.Err(err))
 				time.Sleep(time.Second)
@@ -93,84 +157,84 @@ func (svc *Uploader) listen() {
 
 func (j *UploadJob) Execute() {
 	var (
-		err error
-		src io.ReadCloser
+		err  error
+		src  io.ReadSeekCloser
+		sink Sink
+		res  *model.UploadedFile
 	)
 
 	now := time.Now()
 
 	j.log.Debug("execute")
+	j.svc.jobEvent(j.baseJob, model.JobEventActive, nil)
 
 	defer func() {
 		if err != nil {
+			if sink != nil && j.job.Retry >= j.svc.maxRetry {
+				sink.Cancel(j)
+			}
+
 			j.svc.errorJob(j.baseJob, j.svc.maxRetry, err)
 		} else {
 			j.log.Debug("success job", wlog.Duration("duration", time.Since(now)))
 			j.svc.cleanup(j.baseJob)
+			j.saved(res)
 		}
 	}()
 
-	src, err = j.svc.tempFile.NewReader(*j.job.File)
+	var name string
+	if j.job.Config != nil {
+		name = j.job.Config.Sink
+	}
+
+	sink, err = j.svc.Sink(name)
 	if err != nil {
 		return
 	}
-	defer src.Close()
 
-	stream, err := j.svc.storage.API().UploadFile(j.ctx)
+	src, err = j.svc.tempFile.NewReader(*j.job.File)
 	if err != nil {
 		return
 	}
+	defer src.Close()
 
-	var cp *spb.CustomFileProperties
-	if j.job.File.StartTime > 0 {
-		cp = &spb.CustomFileProperties{
-			StartTime: int64(j.job.File.StartTime),
-			EndTime:   int64(j.job.File.EndTime),
-		}
-	}
+	res, err = sink.Upload(j, src)
+}
 
-	err = stream.Send(&spb.UploadFileRequest{
-		Data: &spb.UploadFileRequest_Metadata_{
-			Metadata: &spb.UploadFileRequest_Metadata{
-				DomainId:          int64(j.job.File.DomainID),
-				Name:              j.job.File.Name,
-				MimeType:          j.job.File.MimeType,
-				Uuid:              j.job.File.UUID,
-				CreatedAt:         int64(j.job.File.CreatedAt),
-				StreamResponse:    false,
-				Channel:           spb.UploadFileChannel(j.job.File.Channel),
-				GenerateThumbnail: true,
-				UploadedBy:        int64(j.job.File.UploadedBy),
-				Properties:        cp,
-			},
+// saved reports the delivered recording to the watchers of its session.
+func (j *UploadJob) saved(res *model.UploadedFile) {
+	j.svc.events.Publish(&model.SessionEvent{
+		SessionID: j.job.File.SessionID,
+		Type:      model.EventFileSaved,
+		Job: &model.JobEvent{
+			ID:       j.job.ID,
+			Type:     j.job.Type,
+			State:    model.JobEventDone,
+			Attempt:  j.job.Retry,
+			Segment:  j.job.File.Segment,
+			FileID:   res.ID,
+			Location: res.Location,
 		},
 	})
-	if err != nil {
-		return
-	}
+	j.svc.jobFinished(j.baseJob)
+}
 
-	buf := make([]byte, 1024*256)
+func (j *UploadJob) uploadState() *model.UploadState {
+	if j.job.Config == nil {
+		return nil
+	}
 
-	var n int
-	for {
-		n, err = src.Read(buf)
-		if err == io.EOF {
-			err = nil
-		}
+	return j.job.Config.Upload
+}
 
-		if n == 0 {
-			stream.CloseSend()
+func (j *UploadJob) setUploadState(st *model.UploadState) {
+	if j.job.Config == nil {
+		j.job.Config = &model.JobConfig{}
+	}
 
-			break
-		}
+	j.job.Config.Upload = st
 
-		err = stream.Send(&spb.UploadFileRequest{
-			Data: &spb.UploadFileRequest_Chunk{
-				Chunk: buf[:n],
-			},
-		})
-		if err != nil {
-			break
-		}
+	if err := j.svc.jobStore.SetConfig(j.job.ID, j.job.Config); err != nil {
+		j.log.Error(err.Error(), wlog.Err(err))
 	}
 }
@@ -2,15 +2,39 @@ package service
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
-	"github.com/pion/rtp"
 	"io"
 	"sync"
+
+	"github.com/pion/rtp"
+
+	"github.com/webitel/webrtc_recorder/internal/utils"
 )
 
-// WAVWriter handles writing PCM samples into a WAV container.
+const wavHeaderSize = 44
+
+var errNotSeekable = errors.New("wav writer is not seekable")
+
+// sampleDepacketizer passes the payload of the sample based audio codecs, every packet is a whole frame.
+type sampleDepacketizer struct{}
+
+func (*sampleDepacketizer) Unmarshal(payload []byte) ([]byte, error) {
+	return payload, nil
+}
+
+func (*sampleDepacketizer) IsPartitionHead([]byte) bool {
+	return true
+}
+
+func (*sampleDepacketizer) IsPartitionTail(bool, []byte) bool {
+	return true
+}
+
+// WAVWriter decodes the RTP payload to 16-bit PCM and writes it into a WAV container.
 type WAVWriter struct {
 	file          io.WriteCloser
+	decoder       utils.AudioDecoder
 	sampleRate    int
 	channels      int
 	bytesWritten  uint32
@@ -19,13 +43,13 @@ type WAVWriter struct {
 	mu            sync.Mutex
 }
 
-// NewWAVWriter creates a WAV writer and writes an initial header.
-func NewWAVWriter(file io.WriteCloser, sampleRate, channels int) (*WAVWriter, error) {
+// NewWAVWriter creates a WAV writer and writes an initial header, the sizes are patched on Close.
+func NewWAVWriter(file io.WriteCloser, dec utils.AudioDecoder, channels int) (*WAVWriter, error) {
 	if file == nil {
 		return nil, fmt.Errorf("nil file provided for WAV writer")
 	}
-	if sampleRate <= 0 {
-		sampleRate = 8000
+	if dec == nil {
+		return nil, fmt.Errorf("nil decoder provided for WAV writer")
 	}
 	if channels <= 0 {
 		channels = 1
@@ -33,7 +57,8 @@ func NewWAVWriter(file io.WriteCloser, sampleRate, channels int) (*WAVWriter, er
 
 	writer := &WAVWriter{
 		file:       file,
-		sampleRate: sampleRate,
+		decoder:    dec,
+		sampleRate: dec.SampleRate(),
 		channels:   channels,
 	}
 
@@ -47,12 +72,33 @@ func (w *WAVWriter) WriteRTP(packet *rtp.Packet) error {
 	if len(packet.Payload) == 0 {
 		return nil
 	}
-	_, err := w.file.Write(packet.Payload)
+
+	pcm := w.decoder.Decode(packet.Payload)
+	buf := make([]byte, len(pcm)*2)
+	for i, v := range pcm {
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(v)) //nolint:gosec
+	}
+
+	_, err := w.Write(buf)
 	return err
 }
 
+// Close stores the RIFF and data sizes in the header and closes the file.
 func (w *WAVWriter) Close() error {
-	return w.file.Close()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.finalized {
+		return nil
+	}
+	w.finalized = true
+
+	err := w.finalizeLocked()
+	if closeErr := w.file.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+
+	return err
 }
 
 // Write appends PCM samples to the WAV file.
@@ -60,6 +106,10 @@ func (w *WAVWriter) Write(p []byte) (int, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if w.finalized {
+		return 0, io.ErrClosedPipe
+	}
+
 	if !w.headerWritten {
 		if err := w.writeHeaderLocked(); err != nil {
 			return 0, err
@@ -67,10 +117,40 @@ func (w *WAVWriter) Write(p []byte) (int, error) {
 	}
 
 	n, err := w.file.Write(p)
-	w.bytesWritten += uint32(n)
+	w.bytesWritten += uint32(n) //nolint:gosec
 	return n, err
 }
 
+func (w *WAVWriter) finalizeLocked() error {
+	s, ok := w.file.(io.WriteSeeker)
+	if !ok {
+		return errNotSeekable
+	}
+
+	size := make([]byte, 4)
+
+	// ChunkSize
+	binary.LittleEndian.PutUint32(size, 36+w.bytesWritten)
+	if _, err := s.Seek(4, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := s.Write(size); err != nil {
+		return err
+	}
+
+	// Subchunk2Size
+	binary.LittleEndian.PutUint32(size, w.bytesWritten)
+	if _, err := s.Seek(40, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := s.Write(size); err != nil {
+		return err
+	}
+
+	_, err := s.Seek(0, io.SeekEnd)
+	return err
+}
+
 func (w *WAVWriter) writeHeader() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -78,11 +158,11 @@ func (w *WAVWriter) writeHeader() error {
 }
 
 func (w *WAVWriter) writeHeaderLocked() error {
-	header := make([]byte, 44)
+	header := make([]byte, wavHeaderSize)
 
 	// ChunkID "RIFF"
 	copy(header[0:], []byte("RIFF"))
-	// ChunkSize (placeholder, will be updated in Finalize)
+	// ChunkSize (placeholder, will be updated in Close)
 	binary.LittleEndian.PutUint32(header[4:], 36)
 	// Format "WAVE"
 	copy(header[8:], []byte("WAVE"))
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/webrtc_recorder/internal/utils"
+)
+
+func TestWAVWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.wav")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	w, err := NewWAVWriter(f, utils.NewG711Decoder(false, 8000), 1)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, w.WriteRTP(&rtp.Packet{Payload: make([]byte, 160)}))
+	}
+	require.NoError(t, w.Close())
+	// closed twice by the track
+	require.NoError(t, w.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, data, wavHeaderSize+3*160*2)
+
+	assert.Equal(t, uint32(36+3*160*2), binary.LittleEndian.Uint32(data[4:]))
+	assert.Equal(t, uint32(8000), binary.LittleEndian.Uint32(data[24:]))
+	assert.Equal(t, uint32(3*160*2), binary.LittleEndian.Uint32(data[40:]))
+}
@@ -2,12 +2,17 @@ package service
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pion/webrtc/v4"
 
 	"github.com/webitel/wlog"
 
+	"github.com/webitel/webrtc_recorder/config"
 	webrtci "github.com/webitel/webrtc_recorder/infra/webrtc"
 	"github.com/webitel/webrtc_recorder/internal/model"
 )
@@ -16,6 +21,7 @@ type SessionStore interface {
 	Get(id string) (model.RtcUploadVideoSession, error)
 	Add(id string, sess model.RtcUploadVideoSession) error
 	Remove(id string) bool
+	List() []model.RtcUploadVideoSession
 }
 
 type WebRtcRecorder struct {
@@ -24,25 +30,55 @@ type WebRtcRecorder struct {
 	sessions SessionStore
 
 	transcoding *Transcoding
+	events      *SessionEvents
 	temp        *TempFileService
+	segment     time.Duration
+	gap         int
+	reconnect   time.Duration
+	instance    string
+	rooms       *rooms
 }
 
-func NewWebRtcRecorder(log *wlog.Logger, api webrtci.API, sess SessionStore, tmp *TempFileService, tr *Transcoding) *WebRtcRecorder {
+func NewWebRtcRecorder(cfg *config.Config, log *wlog.Logger, api webrtci.API, sess SessionStore, tmp *TempFileService, tr *Transcoding,
+	ev *SessionEvents,
+) *WebRtcRecorder {
 	return &WebRtcRecorder{
 		api:         api,
 		log:         log.With(wlog.String("service", "webrtc")),
 		sessions:    sess,
 		temp:        tmp,
 		transcoding: tr,
+		events:      ev,
+		segment:     cfg.Rtc.SegmentDuration,
+		gap:         int(cfg.Rtc.GapThreshold.Milliseconds()),
+		reconnect:   cfg.Rtc.ReconnectGrace,
+		instance:    cfg.Service.ID,
+		rooms:       newRooms(),
 	}
 }
 
-func (svc *WebRtcRecorder) UploadP2PVideo(sdpOffer string, file model.File, ice []webrtci.ICEServer) (model.RtcUploadVideoSession, error) {
+// UploadP2PVideo starts the recording session of the offer. The answer of the trickle session has no candidates,
+// they are sent by WatchIceCandidates.
+func (svc *WebRtcRecorder) UploadP2PVideo(sdpOffer string, file model.File, cfg model.JobConfig, ice []webrtci.ICEServer,
+	trickle bool,
+) (model.RtcUploadVideoSession, error) {
 	var (
 		peerConnection *webrtc.PeerConnection
 		err            error
 	)
 
+	if _, err = svc.transcoding.uploader.Sink(cfg.Sink); err != nil {
+		return nil, err
+	}
+
+	if cfg.Profile, err = svc.transcoding.Profile(file.DomainID, cfg.Profile); err != nil {
+		return nil, err
+	}
+
+	if err = cfg.ValidateLayout(); err != nil {
+		return nil, err
+	}
+
 	config := webrtc.Configuration{
 		ICEServers: ice,
 	}
@@ -68,9 +104,28 @@ func (svc *WebRtcRecorder) UploadP2PVideo(sdpOffer string, file model.File, ice
 		}
 	}
 
+	// the client that lost the connection continues its recording, the name, the room and the job config
+	// of the new call are ignored, the recording keeps the ones it started with
+	if s := svc.reconnectSession(&file); s != nil {
+		ok, err := s.reconnect(peerConnection, sdpOffer, trickle)
+		if ok {
+			if err != nil {
+				s.countTrack.Store(0)
+				s.close()
+
+				return nil, err
+			}
+
+			return s, nil
+		}
+	}
+
 	writeFile := &file
 
-	session := NewWebRtcUploadSession(svc, peerConnection, writeFile)
+	session := NewWebRtcUploadSession(svc, peerConnection, writeFile, &cfg, trickle)
+	if file.RoomID != "" {
+		svc.joinRoom(session)
+	}
 
 	err = session.negotiate(sdpOffer)
 	if err != nil {
@@ -86,10 +141,29 @@ func (svc *WebRtcRecorder) UploadP2PVideo(sdpOffer string, file model.File, ice
 	return session, nil
 }
 
+// reconnectSession finds the session of the recording that waits for its client in the grace period,
+// only the user that started the recording takes it over.
+func (svc *WebRtcRecorder) reconnectSession(file *model.File) *RtcUploadMediaSession {
+	if file.UUID == "" {
+		return nil
+	}
+
+	for _, session := range svc.sessions.List() {
+		s := session.(*RtcUploadMediaSession)
+		if s.fileConfig.DomainID == file.DomainID && s.fileConfig.UploadedBy == file.UploadedBy &&
+			s.fileConfig.UUID == file.UUID && s.reconnecting() {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// RenegotiateP2P applies the new offer of the client, the offer with the new ICE credentials restarts ICE.
 func (svc *WebRtcRecorder) RenegotiateP2P(id, sdpOffer string) (model.RtcUploadVideoSession, error) {
 	session, err := svc.sessions.Get(id)
 	if err != nil {
-		return nil, fmt.Errorf("p2p session with id %s not found", id)
+		return nil, fmt.Errorf("%w: p2p %s", model.ErrSessionNotFound, id)
 	}
 
 	sess := session.(*RtcUploadMediaSession)
@@ -108,7 +182,7 @@ func (svc *WebRtcRecorder) RenegotiateP2P(id, sdpOffer string) (model.RtcUploadV
 func (svc *WebRtcRecorder) CloseP2P(id string) error {
 	session, err := svc.sessions.Get(id)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: p2p %s", model.ErrSessionNotFound, id)
 	}
 
 	// TODO singleflight
@@ -117,24 +191,260 @@ func (svc *WebRtcRecorder) CloseP2P(id string) error {
 	return nil
 }
 
+// PauseRecording stops writing the samples of the session until ResumeRecording, the peer connection stays alive.
+func (svc *WebRtcRecorder) PauseRecording(domainID int64, id string) error {
+	s, err := svc.domainSession(domainID, id)
+	if err != nil {
+		return err
+	}
+
+	s.Pause()
+
+	return nil
+}
+
+func (svc *WebRtcRecorder) ResumeRecording(domainID int64, id string) error {
+	s, err := svc.domainSession(domainID, id)
+	if err != nil {
+		return err
+	}
+
+	s.Resume()
+
+	return nil
+}
+
+// AddMarker records the marker of the session, zero ts is now.
+func (svc *WebRtcRecorder) AddMarker(domainID int64, id, label string, ts int) (*model.Marker, error) {
+	s, err := svc.domainSession(domainID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.AddMarker(label, ts)
+}
+
+// PatchICE applies the trickle ICE fragment of the client to the session. The new credentials of the fragment
+// restart ICE, the local fragment is returned with restarted true then. Non-empty ufrag must match the current
+// local credentials.
+func (svc *WebRtcRecorder) PatchICE(domainID int64, id, ufrag string, frag *model.IceFragment) (*model.IceFragment, bool, error) {
+	s, err := svc.domainSession(domainID, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return s.patchICE(ufrag, frag)
+}
+
+// AddIceCandidate adds the remote candidate of the client to the session.
+func (svc *WebRtcRecorder) AddIceCandidate(domainID int64, id string, c model.IceCandidate) error {
+	s, err := svc.domainSession(domainID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.addRemoteCandidate(c)
+}
+
+// WatchIceCandidates streams the local candidates of the trickle session from the first one, the empty candidate
+// ends the gathering. The channel is closed with the session or by the returned cancel.
+func (svc *WebRtcRecorder) WatchIceCandidates(domainID int64, id string) (<-chan model.IceCandidate, func(), error) {
+	s, err := svc.domainSession(domainID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidates := s.iceCandidates()
+	if candidates == nil {
+		return nil, nil, model.ErrTrickleDisabled
+	}
+
+	stop := make(chan struct{})
+	var once sync.Once
+
+	return candidates.watch(stop, s.ctx.Done()), func() {
+		once.Do(func() {
+			close(stop)
+		})
+	}, nil
+}
+
+// StopSession closes the session of the domain.
+func (svc *WebRtcRecorder) StopSession(domainID int64, id string) error {
+	s, err := svc.domainSession(domainID, id)
+	if err != nil {
+		return err
+	}
+
+	s.close()
+
+	return nil
+}
+
+func (svc *WebRtcRecorder) domainSession(domainID int64, id string) (*RtcUploadMediaSession, error) {
+	session, err := svc.sessions.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: p2p %s", model.ErrSessionNotFound, id)
+	}
+
+	s := session.(*RtcUploadMediaSession)
+	if int64(s.fileConfig.DomainID) != domainID {
+		return nil, fmt.Errorf("%w: p2p %s", model.ErrSessionNotFound, id)
+	}
+
+	return s, nil
+}
+
+// ListSessions returns the active sessions of the domain ordered by creation time.
+func (svc *WebRtcRecorder) ListSessions(domainID int64) []*model.SessionInfo {
+	list := make([]*model.SessionInfo, 0)
+
+	for _, s := range svc.sessions.List() {
+		info := s.Info()
+		if int64(info.DomainID) == domainID {
+			list = append(list, info)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt < list[j].CreatedAt
+	})
+
+	return list
+}
+
+func (svc *WebRtcRecorder) GetSession(domainID int64, id string) (*model.SessionInfo, error) {
+	session, err := svc.sessions.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: p2p %s", model.ErrSessionNotFound, id)
+	}
+
+	info := session.Info()
+	if int64(info.DomainID) != domainID {
+		return nil, fmt.Errorf("%w: p2p %s", model.ErrSessionNotFound, id)
+	}
+
+	return info, nil
+}
+
+// WatchSession returns the event feed of the session, it starts with the current state of an active session.
+func (svc *WebRtcRecorder) WatchSession(domainID int64, id string) (<-chan *model.SessionEvent, func(), error) {
+	var initial []*model.SessionEvent
+
+	if session, err := svc.sessions.Get(id); err == nil {
+		initial = sessionSnapshot(session.Info())
+	}
+
+	ch, cancel, err := svc.events.Subscribe(id, int(domainID), initial)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: p2p %s", model.ErrSessionNotFound, id)
+	}
+
+	return ch, cancel, nil
+}
+
+func sessionSnapshot(info *model.SessionInfo) []*model.SessionEvent {
+	now := model.GetMillis()
+	events := make([]*model.SessionEvent, 0, len(info.Tracks)+1)
+
+	for _, t := range info.Tracks {
+		events = append(events, &model.SessionEvent{
+			SessionID: info.ID,
+			Type:      model.EventTrackAdded,
+			Timestamp: now,
+			Track:     t,
+		})
+	}
+
+	return append(events, &model.SessionEvent{
+		SessionID: info.ID,
+		Type:      model.EventIceStateChanged,
+		Timestamp: now,
+		ICEState:  info.ICEState,
+	})
+}
+
 func (svc *WebRtcRecorder) stopVideoSession(s *RtcUploadMediaSession) {
 	if !svc.sessions.Remove(s.id) {
 		s.log.Debug("closing peer connection")
+		// the session that failed the negotiation was never watched
+		svc.events.sessionClosed(s.id)
+
+		if s.room != nil {
+			svc.leaveRoom(s)
+		}
 
 		return
 	}
 
+	now := model.GetMillis()
+
+	s.mu.Lock()
+	s.closePause(now)
+
 	if s.fileConfig.StartTime > 0 {
-		s.fileConfig.EndTime = model.GetMillis()
+		s.fileConfig.EndTime = now
 	}
+	s.mu.Unlock()
 
-	err := svc.transcoding.CreateJob(s.fileConfig)
+	if s.segment > 0 {
+		s.dropEmptyTracks()
+	}
+
+	s.mu.Lock()
+	s.flushSpeech()
+	s.mu.Unlock()
+
+	switch {
+	case s.room != nil:
+		// the manifest keeps the final tracks of the participant until the room recording is stored
+		s.mu.Lock()
+		s.saveManifest()
+		s.mu.Unlock()
+
+		svc.leaveRoom(s)
+	default:
+		if len(s.fileConfig.Track) != 0 {
+			svc.storeSegment(s, s.fileConfig)
+		}
+
+		if err := svc.temp.RemoveManifest(s.id); err != nil && !os.IsNotExist(err) {
+			s.log.Error(err.Error(), wlog.Err(err))
+		}
+	}
+
+	svc.events.Publish(&model.SessionEvent{
+		SessionID: s.id,
+		Type:      model.EventSessionClosed,
+	})
+	svc.events.sessionClosed(s.id)
+}
+
+// storeSegment enqueues transcoding of the recorded (part of the) session.
+func (svc *WebRtcRecorder) storeSegment(s *RtcUploadMediaSession, f *model.File) {
+	if f.Segment > 0 {
+		f.Name = f.SegmentName()
+		s.log.Debug(fmt.Sprintf("store segment %d", f.Segment))
+	}
+
+	// offsets of the tracks from their sender reports
+	s.mu.Lock()
+	f.Align()
+	s.mu.Unlock()
+
+	svc.events.jobCreated(s.id)
+
+	err := svc.transcoding.CreateJob(s.jobConfig, f)
 	if err != nil {
 		s.log.Error(err.Error(), wlog.Err(err))
+		svc.events.jobFinished(s.id)
 
-		err = svc.temp.DeleteFile(s.fileConfig)
+		// without a job the recording is only traced by the log
+		err = svc.temp.Quarantine(f)
 		if err != nil {
 			s.log.Error(err.Error(), wlog.Err(err))
 		}
+
+		s.log.Error("recording moved to quarantine", wlog.String("file", string(f.JSON())))
 	}
 }
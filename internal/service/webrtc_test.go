@@ -0,0 +1,159 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+type fakeSession struct {
+	info *model.SessionInfo
+}
+
+func (s *fakeSession) ID() string               { return s.info.ID }
+func (s *fakeSession) AnswerSDP() string        { return "" }
+func (s *fakeSession) Info() *model.SessionInfo { return s.info }
+
+type fakeSessionStore struct {
+	SessionStore
+
+	list []model.RtcUploadVideoSession
+}
+
+func (s *fakeSessionStore) Get(id string) (model.RtcUploadVideoSession, error) {
+	for _, sess := range s.list {
+		if sess.ID() == id {
+			return sess, nil
+		}
+	}
+
+	return nil, model.ErrSessionNotFound
+}
+
+func (s *fakeSessionStore) List() []model.RtcUploadVideoSession {
+	return s.list
+}
+
+func TestWebRtcRecorder_Sessions(t *testing.T) {
+	svc := &WebRtcRecorder{
+		sessions: &fakeSessionStore{list: []model.RtcUploadVideoSession{
+			&fakeSession{info: &model.SessionInfo{ID: "b", DomainID: 1, UserID: 10, CreatedAt: 2000}},
+			&fakeSession{info: &model.SessionInfo{ID: "other", DomainID: 2, UserID: 20, CreatedAt: 1000}},
+			&fakeSession{info: &model.SessionInfo{ID: "a", DomainID: 1, UserID: 11, CreatedAt: 1000}},
+		}},
+	}
+
+	testCases := []struct {
+		name     string
+		domainID int64
+		id       string
+		list     []string
+		found    bool
+	}{
+		{
+			name:     "own session",
+			domainID: 1,
+			id:       "a",
+			list:     []string{"a", "b"},
+			found:    true,
+		},
+		{
+			name:     "session of other domain",
+			domainID: 1,
+			id:       "other",
+			list:     []string{"a", "b"},
+		},
+		{
+			name:     "unknown session",
+			domainID: 2,
+			id:       "c",
+			list:     []string{"other"},
+		},
+		{
+			name:     "domain without sessions",
+			domainID: 3,
+			id:       "a",
+			list:     []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ids := make([]string, 0)
+			for _, info := range svc.ListSessions(tc.domainID) {
+				ids = append(ids, info.ID)
+			}
+
+			assert.Equal(t, tc.list, ids)
+
+			info, err := svc.GetSession(tc.domainID, tc.id)
+			if !tc.found {
+				require.ErrorIs(t, err, model.ErrSessionNotFound)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.id, info.ID)
+		})
+	}
+}
+
+func TestWebRtcRecorder_reconnectSession(t *testing.T) {
+	waiting := &RtcUploadMediaSession{
+		id:         "s1",
+		fileConfig: &model.File{DomainID: 1, UploadedBy: 10, UUID: "rec-1"},
+		grace:      time.AfterFunc(time.Hour, func() {}),
+	}
+	defer waiting.grace.Stop()
+
+	svc := &WebRtcRecorder{
+		sessions: &fakeSessionStore{list: []model.RtcUploadVideoSession{
+			waiting,
+			&RtcUploadMediaSession{id: "s2", fileConfig: &model.File{DomainID: 1, UploadedBy: 10, UUID: "rec-2"}},
+		}},
+	}
+
+	testCases := []struct {
+		name  string
+		file  model.File
+		found bool
+	}{
+		{
+			name:  "same user",
+			file:  model.File{DomainID: 1, UploadedBy: 10, UUID: "rec-1"},
+			found: true,
+		},
+		{
+			name: "other user of the domain",
+			file: model.File{DomainID: 1, UploadedBy: 11, UUID: "rec-1"},
+		},
+		{
+			name: "other domain",
+			file: model.File{DomainID: 2, UploadedBy: 10, UUID: "rec-1"},
+		},
+		{
+			name: "session is connected",
+			file: model.File{DomainID: 1, UploadedBy: 10, UUID: "rec-2"},
+		},
+		{
+			name: "without uuid",
+			file: model.File{DomainID: 1, UploadedBy: 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := svc.reconnectSession(&tc.file)
+			if tc.found {
+				assert.Same(t, waiting, s)
+			} else {
+				assert.Nil(t, s)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package store
 
 import (
 	"context"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
 
 	"github.com/webitel/wlog"
 
@@ -10,10 +13,14 @@ import (
 	"github.com/webitel/webrtc_recorder/internal/model"
 )
 
+const jobColumns = `id, type, instance, state, file, config, retry, error, coalesce(errors, '[]') as errors,
+    created_at, activity_at, next_attempt_at`
+
 type FileJobStore struct {
 	db       sql.Store
 	ctx      context.Context
 	instance string
+	lease    time.Duration
 	log      *wlog.Logger
 }
 
@@ -22,6 +29,7 @@ func NewFileJobStore(ctx context.Context, log *wlog.Logger, cfg *config.Config,
 		db:       db,
 		ctx:      ctx,
 		instance: cfg.Service.ID,
+		lease:    cfg.Jobs.LeaseTTL,
 		log:      log.With(wlog.String("store", "file_jobs")),
 	}
 
@@ -34,8 +42,9 @@ func NewFileJobStore(ctx context.Context, log *wlog.Logger, cfg *config.Config,
 }
 
 func (s *FileJobStore) Create(jobType string, cfg *model.JobConfig, f *model.File) error {
-	return s.db.Exec(s.ctx, `insert into webrtc_rec.file_jobs (type, instance, file, config)
-values (@type, @instance, @file, @config)`, map[string]any{
+	return s.db.Exec(s.ctx, `insert into webrtc_rec.file_jobs (type, instance, file, config, lease_until)
+values (@type, @instance, @file, @config, now() + make_interval(secs => @lease::float8))`, map[string]any{
+		"lease":    s.lease.Seconds(),
 		"type":     jobType,
 		"instance": s.instance,
 		"file":     f.JSON(),
@@ -51,6 +60,7 @@ set state = @state,
     config = @config,
     error = @error,
     retry = @retry,
+    next_attempt_at = null,
     activity_at = now()
 where id = @id`, map[string]any{
 		"id":     j.ID,
@@ -58,17 +68,30 @@ where id = @id`, map[string]any{
 		"type":   j.Type,
 		"file":   j.File.JSON(),
 		"config": j.Config.JSON(),
-		"error":  nil, // TODO
+		"error":  j.Error,
 		"retry":  j.Retry,
 	})
 }
 
+func (s *FileJobStore) SetConfig(id int, cfg *model.JobConfig) error {
+	return s.db.Exec(s.ctx, `update webrtc_rec.file_jobs
+set config = @config,
+    activity_at = now()
+where id = @id`, map[string]any{
+		"id":     id,
+		"config": cfg.JSON(),
+	})
+}
+
+// Reset returns the jobs interrupted by the previous run of the instance to the queue.
 func (s *FileJobStore) Reset() error {
 	return s.db.Exec(s.ctx, `update webrtc_rec.file_jobs
 set state = @state
-where instance = @instance;`, map[string]any{
+where instance = @instance
+    and state = @active;`, map[string]any{
 		"instance": s.instance,
 		"state":    model.JobIdle,
+		"active":   model.JobActive,
 	})
 }
 
@@ -78,6 +101,7 @@ func (s *FileJobStore) Fetch(limit int, jobType string) ([]*model.Job, error) {
 	err := s.db.Select(s.ctx, &jobs, `update webrtc_rec.file_jobs j
 set state = @state,
     activity_at = now(),
+    lease_until = now() + make_interval(secs => @lease::float8),
     retry = j.retry + 1
 from (
     select id, type, file, config, retry
@@ -85,14 +109,17 @@ from (
     where state = 0
         and instance = @instance
     	and type = @type
+        and (next_attempt_at isnull or next_attempt_at <= now())
     order by created_at
     limit @limit
+    for update skip locked
 ) x
 where x.id = j.id
 returning x.*`, map[string]any{
 		"limit":    limit,
 		"type":     jobType,
 		"instance": s.instance,
+		"lease":    s.lease.Seconds(),
 		"state":    model.JobActive,
 	})
 	if err != nil {
@@ -102,15 +129,83 @@ returning x.*`, map[string]any{
 	return jobs, nil
 }
 
-func (s *FileJobStore) SetError(id int, err error) error {
+// Claim takes over the jobs of the instances that stopped renewing their lease and the queued jobs of the instances
+// that are not alive anymore, an empty alive list leaves only the lease check. The returned jobs keep the id of the
+// previous owner.
+func (s *FileJobStore) Claim(limit int, jobType string, alive []string) ([]*model.Job, error) {
+	var jobs []*model.Job
+
+	err := s.db.Select(s.ctx, &jobs, `update webrtc_rec.file_jobs j
+set state = @state,
+    instance = @instance,
+    activity_at = now(),
+    lease_until = now() + make_interval(secs => @lease::float8),
+    retry = j.retry + 1
+from (
+    select id, type, instance, file, config, retry
+    from webrtc_rec.file_jobs
+    where instance <> @instance
+        and type = @type
+        and (state = @active or state = @idle and (next_attempt_at isnull or next_attempt_at <= now()))
+        and (lease_until < now()
+            or state = @idle and cardinality(@alive::varchar[]) > 0 and instance <> all (@alive::varchar[]))
+    order by created_at
+    limit @limit
+    for update skip locked
+) x
+where x.id = j.id
+returning x.*`, map[string]any{
+		"limit":    limit,
+		"type":     jobType,
+		"instance": s.instance,
+		"alive":    alive,
+		"lease":    s.lease.Seconds(),
+		"state":    model.JobActive,
+		"active":   model.JobActive,
+		"idle":     model.JobIdle,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Renew extends the lease of the jobs owned by the instance.
+func (s *FileJobStore) Renew() error {
+	return s.db.Exec(s.ctx, `update webrtc_rec.file_jobs
+set lease_until = now() + make_interval(secs => @lease::float8)
+where instance = @instance
+    and state <> @failed`, map[string]any{
+		"instance": s.instance,
+		"lease":    s.lease.Seconds(),
+		"failed":   model.JobFailed,
+	})
+}
+
+// SetError records the failed attempt of the job and moves it to the state, the file keeps the (quarantined) paths.
+// An idle job is not fetched again until the delay passes.
+func (s *FileJobStore) SetError(j *model.Job, state model.JobState, e *model.JobError, delay time.Duration) error {
+	var secs *float64
+	if state == model.JobIdle {
+		v := delay.Seconds()
+		secs = &v
+	}
+
 	return s.db.Exec(s.ctx, `update webrtc_rec.file_jobs
 set error = @error,
+    errors = coalesce(errors, '[]') || jsonb_build_array(@entry::jsonb),
+    file = @file,
     state = @state,
+    next_attempt_at = now() + make_interval(secs => @delay::float8),
     activity_at = now()
 where id = @id`, map[string]any{
-		"id":    id,
-		"error": err.Error(),
-		"state": model.JobIdle,
+		"id":    j.ID,
+		"error": e.Error,
+		"entry": e.JSON(),
+		"file":  j.File.JSON(),
+		"state": state,
+		"delay": secs,
 	})
 }
 
@@ -121,3 +216,175 @@ where id = @id`, map[string]any{
 		"id": id,
 	})
 }
+
+// Paths returns the temp files referenced by the jobs of the instance, or of all instances when the temp dir is shared.
+func (s *FileJobStore) Paths(all bool) ([]string, error) {
+	var paths []string
+
+	err := s.db.Select(s.ctx, &paths, `select distinct x.path
+from (
+    select j.file ->> 'path' as path
+    from webrtc_rec.file_jobs j
+    where (@all::bool or j.instance = @instance)
+    union all
+    select t ->> 'path'
+    from webrtc_rec.file_jobs j,
+         jsonb_array_elements(j.file -> 'track') t
+    where (@all::bool or j.instance = @instance)
+) x
+where coalesce(x.path, '') <> ''`, map[string]any{
+		"instance": s.instance,
+		"all":      all,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// List returns the jobs of the domain matching the filter, the newest first.
+func (s *FileJobStore) List(filter *model.JobFilter) ([]*model.Job, error) {
+	var (
+		jobs  []*model.Job
+		state []int
+	)
+
+	for _, st := range filter.State {
+		state = append(state, int(st))
+	}
+
+	err := s.db.Select(s.ctx, &jobs, `select `+jobColumns+`
+from webrtc_rec.file_jobs
+where (file ->> 'domain_id')::int8 = @domain_id
+    and (@type::varchar isnull or type = @type)
+    and (@state::int[] isnull or state = any (@state::int[]))
+    and (@instance::varchar isnull or instance = @instance)
+    and (@has_error::bool isnull or (coalesce(error, '') <> '') = @has_error)
+order by created_at desc, id desc
+limit @limit offset @offset`, map[string]any{
+		"domain_id": filter.DomainID,
+		"type":      nullString(filter.Type),
+		"state":     state,
+		"instance":  nullString(filter.Instance),
+		"has_error": filter.HasError,
+		"limit":     filter.Size + 1,
+		"offset":    (filter.Page - 1) * filter.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (s *FileJobStore) Get(domainID, id int) (*model.Job, error) {
+	var j model.Job
+
+	err := s.db.Get(s.ctx, &j, `select `+jobColumns+`
+from webrtc_rec.file_jobs
+where id = @id
+    and (file ->> 'domain_id')::int8 = @domain_id`, map[string]any{
+		"id":        id,
+		"domain_id": domainID,
+	})
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return &j, nil
+}
+
+// Retry makes the job that is not running available to the workers again.
+func (s *FileJobStore) Retry(domainID, id int) (*model.Job, error) {
+	var j model.Job
+
+	err := s.db.Get(s.ctx, &j, `update webrtc_rec.file_jobs
+set state = @state,
+    retry = 0,
+    next_attempt_at = null,
+    activity_at = now()
+where id = @id
+    and (file ->> 'domain_id')::int8 = @domain_id
+    and state <> @active
+returning `+jobColumns, map[string]any{
+		"id":        id,
+		"domain_id": domainID,
+		"state":     model.JobIdle,
+		"active":    model.JobActive,
+	})
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return &j, nil
+}
+
+// Restart queues the job that is not running as a new job of the type.
+func (s *FileJobStore) Restart(domainID int, j *model.Job) (*model.Job, error) {
+	var res model.Job
+
+	err := s.db.Get(s.ctx, &res, `update webrtc_rec.file_jobs
+set state = @state,
+    type = @type,
+    file = @file,
+    config = @config,
+    retry = 0,
+    error = null,
+    next_attempt_at = null,
+    activity_at = now()
+where id = @id
+    and (file ->> 'domain_id')::int8 = @domain_id
+    and state <> @active
+returning `+jobColumns, map[string]any{
+		"id":        j.ID,
+		"domain_id": domainID,
+		"type":      j.Type,
+		"file":      j.File.JSON(),
+		"config":    j.Config.JSON(),
+		"state":     model.JobIdle,
+		"active":    model.JobActive,
+	})
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return &res, nil
+}
+
+// Cancel deletes the job that is not running and returns it.
+func (s *FileJobStore) Cancel(domainID, id int) (*model.Job, error) {
+	var j model.Job
+
+	err := s.db.Get(s.ctx, &j, `delete
+from webrtc_rec.file_jobs
+where id = @id
+    and (file ->> 'domain_id')::int8 = @domain_id
+    and state <> @active
+returning `+jobColumns, map[string]any{
+		"id":        id,
+		"domain_id": domainID,
+		"active":    model.JobActive,
+	})
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return &j, nil
+}
+
+func notFound(err error) error {
+	if pgxscan.NotFound(err) {
+		return model.ErrJobNotFound
+	}
+
+	return err
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
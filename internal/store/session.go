@@ -55,6 +55,10 @@ func (s *SessionStore) Remove(id string) bool {
 	return ok
 }
 
+func (s *SessionStore) List() []model.RtcUploadVideoSession {
+	return s.sess.Values()
+}
+
 func (s *SessionStore) Add(id string, sess model.RtcUploadVideoSession) error {
 	s.log.Debug("adding new session to cache", wlog.String("session_id", id))
 	s.sess.Add(id, sess)
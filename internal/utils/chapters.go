@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+var metadataEscaper = strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", "\\\n")
+
+// chaptersMetadata is the ffmetadata of the markers, every chapter lasts until the next marker
+// and the last one until the end of the recording.
+func chaptersMetadata(markers []model.Marker, durationMs int) string {
+	sorted := slices.Clone(markers)
+	slices.SortStableFunc(sorted, func(a, b model.Marker) int {
+		return a.At - b.At
+	})
+
+	var b strings.Builder
+
+	b.WriteString(";FFMETADATA1\n")
+
+	for i, m := range sorted {
+		end := durationMs
+		if i+1 < len(sorted) {
+			end = sorted[i+1].At
+		}
+
+		end = max(end, m.At+1)
+
+		b.WriteString("[CHAPTER]\nTIMEBASE=1/1000\n")
+		b.WriteString("START=" + strconv.Itoa(m.At) + "\n")
+		b.WriteString("END=" + strconv.Itoa(end) + "\n")
+		b.WriteString("title=" + metadataEscaper.Replace(m.Label) + "\n")
+	}
+
+	return b.String()
+}
+
+// recordingDuration is the end of the latest track of the aligned recording, ms.
+func recordingDuration(src []model.MediaChannel, actualDurationMs int) int {
+	d := actualDurationMs
+	for _, ch := range src {
+		d = max(d, ch.Offset+ch.Duration)
+	}
+
+	return d
+}
+
+// writeChapters stores the chapters of the markers next to dst, it returns an empty path without markers.
+func writeChapters(markers []model.Marker, durationMs int, dst string) (string, error) {
+	if len(markers) == 0 {
+		return "", nil
+	}
+
+	p := dst + ".chapters"
+	if err := os.WriteFile(p, []byte(chaptersMetadata(markers, durationMs)), 0o600); err != nil {
+		return "", fmt.Errorf("write chapters: %w", err)
+	}
+
+	return p, nil
+}
+
+// chaptersInput opens the chapters file as the input idx and maps its chapters to the output.
+func chaptersInput(p string, idx int) ([]string, []string) {
+	if p == "" {
+		return nil, nil
+	}
+
+	return []string{"-f", "ffmetadata", "-i", p}, []string{"-map_chapters", strconv.Itoa(idx)}
+}
+
+// sideInputs are the chapters and the subtitles inputs after the n tracks and their output arguments,
+// the empty paths are skipped.
+func sideInputs(n int, chapters, subtitles, subtitleCodec string) ([]string, []string) {
+	in, out := chaptersInput(chapters, n)
+	if chapters != "" {
+		n++
+	}
+
+	subIn, subOut := subtitlesInput(subtitles, n, subtitleCodec)
+
+	return append(in, subIn...), append(out, subOut...)
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func TestChaptersMetadata(t *testing.T) {
+	markers := []model.Marker{
+		{Label: "payment step", At: 65000},
+		{Label: "customer escalated; a=b", At: 1000},
+	}
+
+	assert.Equal(t, ";FFMETADATA1\n"+
+		"[CHAPTER]\nTIMEBASE=1/1000\nSTART=1000\nEND=65000\ntitle=customer escalated\\; a\\=b\n"+
+		"[CHAPTER]\nTIMEBASE=1/1000\nSTART=65000\nEND=90000\ntitle=payment step\n",
+		chaptersMetadata(markers, 90000))
+
+	src := []model.MediaChannel{{MimeType: "video/H264", Path: "v", Offset: 500, Duration: 95000}}
+	assert.Equal(t, 95500, recordingDuration(src, 90000))
+
+	in, out := sideInputs(1, "v.chapters", "", "mov_text")
+	args := remuxArgs(src, []float64{1}, in, out, "mp4")
+	assert.Equal(t, []string{
+		"-itsoffset", "0.500", "-fflags", "+genpts", "-f", "h264", "-i", "v", "-f", "ffmetadata", "-i", "v.chapters",
+		"-map", "0:v", "-c", "copy", "-map_chapters", "1", "-movflags", "+faststart", "-f", "mp4",
+	}, args)
+
+	in, out = sideInputs(1, "v.chapters", "v.vtt", "mov_text")
+	assert.Equal(t, []string{"-f", "ffmetadata", "-i", "v.chapters", "-f", "webvtt", "-i", "v.vtt"}, in)
+	assert.Equal(t, []string{"-map_chapters", "1", "-map", "2:s", "-c:s", "mov_text"}, out)
+
+	in, out = sideInputs(2, "", "v.vtt", "")
+	assert.Empty(t, in)
+	assert.Empty(t, out)
+}
@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+// cueDuration is the longest time the subtitle of the message is shown.
+const cueDuration = 4000
+
+// ReadDataTracks reads the messages of the data channel tracks sorted by time and places them in the recording.
+func ReadDataTracks(src []model.MediaChannel) ([]model.DataMessage, error) {
+	var msgs []model.DataMessage
+
+	for _, ch := range src {
+		if ch.MimeType != model.DataMimeType {
+			continue
+		}
+
+		data, err := os.ReadFile(ch.Path)
+		if err != nil {
+			return nil, fmt.Errorf("read data track %s: %w", ch.Path, err)
+		}
+
+		// the start of the recording on the session clock
+		origin := ch.FirstPacketAt - ch.Offset
+
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			// the last line of the crashed session may be cut
+			m, err := model.ParseDataMessage(line)
+			if err != nil {
+				continue
+			}
+
+			if ch.Label != "" {
+				m.Channel = ch.Label
+			}
+
+			if origin > 0 {
+				m.At = max(m.Timestamp-origin, 0)
+			}
+
+			msgs = append(msgs, *m)
+		}
+	}
+
+	slices.SortStableFunc(msgs, func(a, b model.DataMessage) int {
+		return a.Timestamp - b.Timestamp
+	})
+
+	return msgs, nil
+}
+
+// WriteDataSidecar stores the messages as the JSON lines file.
+func WriteDataSidecar(msgs []model.DataMessage, dst string) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	for i := range msgs {
+		_, _ = w.Write(msgs[i].JSON())
+		_ = w.WriteByte('\n')
+	}
+
+	if err = w.Flush(); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
+
+// webVTT renders the chat lines and the events as the subtitles, a cue lasts until the next one.
+func webVTT(msgs []model.DataMessage) string {
+	type cue struct {
+		at   int
+		text string
+	}
+
+	var cues []cue
+
+	for _, m := range msgs {
+		switch m.Type {
+		case model.DataChat:
+			text := m.Text
+			if m.From != "" {
+				text = m.From + ": " + text
+			}
+
+			cues = append(cues, cue{at: m.At, text: text})
+		case model.DataEvent:
+			cues = append(cues, cue{at: m.At, text: "[" + m.Label + "]"})
+		}
+	}
+
+	if len(cues) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString("WEBVTT\n")
+
+	for i, c := range cues {
+		end := c.at + cueDuration
+		if i+1 < len(cues) && cues[i+1].at < end {
+			end = max(cues[i+1].at, c.at+1)
+		}
+
+		b.WriteString(fmt.Sprintf("\n%s --> %s\n%s\n", vttTime(c.at), vttTime(end), vttEscape(c.text)))
+	}
+
+	return b.String()
+}
+
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "-->", "--&gt;", "\r", "", "\n", " ")
+
+func vttEscape(s string) string {
+	return vttEscaper.Replace(s)
+}
+
+func vttTime(ms int) string {
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// WriteSubtitles stores the WebVTT of the messages next to dst, it returns an empty path when there is nothing to show.
+func WriteSubtitles(msgs []model.DataMessage, dst string) (string, error) {
+	vtt := webVTT(msgs)
+	if vtt == "" {
+		return "", nil
+	}
+
+	p := dst + ".vtt"
+	if err := os.WriteFile(p, []byte(vtt), 0o600); err != nil {
+		return "", fmt.Errorf("write subtitles: %w", err)
+	}
+
+	return p, nil
+}
+
+// subtitlesInput opens the WebVTT file as the input idx and encodes it with the subtitle codec of the container.
+func subtitlesInput(p string, idx int, codec string) ([]string, []string) {
+	if p == "" || codec == "" {
+		return nil, nil
+	}
+
+	return []string{"-f", "webvtt", "-i", p}, []string{"-map", fmt.Sprintf("%d:s", idx), "-c:s", codec}
+}
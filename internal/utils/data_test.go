@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func TestReadDataTracks(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "events.jsonl")
+	require.NoError(t, os.WriteFile(p, []byte(
+		`{"type":"chat","timestamp":13000,"text":"hello","from":"agent"}`+"\n"+
+			`{"type":"event","timestamp":11500,"label":"screen shared"}`+"\n"+
+			`{"type":"chat","timestamp":14`), 0o600))
+
+	src := []model.MediaChannel{
+		{MimeType: "video/VP8", Path: "v"},
+		{MimeType: model.DataMimeType, Path: p, Label: "events", FirstPacketAt: 11500, Offset: 1500},
+	}
+
+	msgs, err := ReadDataTracks(src)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, model.DataMessage{Type: model.DataEvent, Timestamp: 11500, At: 1500, Channel: "events", Label: "screen shared"}, msgs[0])
+	assert.Equal(t, 3000, msgs[1].At)
+
+	assert.Equal(t, "WEBVTT\n"+
+		"\n00:00:01.500 --> 00:00:03.000\n[screen shared]\n"+
+		"\n00:00:03.000 --> 00:00:07.000\nagent: hello\n", webVTT(msgs))
+
+	dst := filepath.Join(dir, "out.jsonl")
+	require.NoError(t, WriteDataSidecar(msgs, dst))
+	data, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"event","timestamp":11500,"at":1500,"channel":"events","label":"screen shared"}`+"\n"+
+		`{"type":"chat","timestamp":13000,"at":3000,"channel":"events","text":"hello","from":"agent"}`+"\n", string(data))
+}
+
+func TestWebVTT(t *testing.T) {
+	assert.Empty(t, webVTT([]model.DataMessage{{Type: model.DataCursor}}))
+	assert.Equal(t, "WEBVTT\n\n01:02:03.004 --> 01:02:07.004\na &lt;b&gt; --&gt; c d\n",
+		webVTT([]model.DataMessage{{Type: model.DataChat, At: 3723004, Text: "a <b> --> c\nd"}}))
+}
@@ -0,0 +1,75 @@
+package utils
+
+// AudioDecoder decodes the RTP payload of the narrow band codecs to 16-bit PCM.
+type AudioDecoder interface {
+	Decode(payload []byte) []int16
+	// SampleRate of the decoded PCM
+	SampleRate() int
+}
+
+// G711Decoder decodes PCMU (mu-law) or PCMA (A-law) samples.
+type G711Decoder struct {
+	alaw bool
+	rate int
+}
+
+func NewG711Decoder(alaw bool, rate int) *G711Decoder {
+	if rate <= 0 {
+		rate = 8000
+	}
+
+	return &G711Decoder{alaw: alaw, rate: rate}
+}
+
+func (d *G711Decoder) Decode(payload []byte) []int16 {
+	pcm := make([]int16, len(payload))
+
+	for i, v := range payload {
+		if d.alaw {
+			pcm[i] = alawToLinear(v)
+		} else {
+			pcm[i] = ulawToLinear(v)
+		}
+	}
+
+	return pcm
+}
+
+func (d *G711Decoder) SampleRate() int {
+	return d.rate
+}
+
+func ulawToLinear(u byte) int16 {
+	const bias = 0x84
+
+	u = ^u
+	t := (int(u&0x0F) << 3) + bias
+	t <<= (u & 0x70) >> 4
+
+	if u&0x80 != 0 {
+		return int16(bias - t)
+	}
+
+	return int16(t - bias)
+}
+
+func alawToLinear(a byte) int16 {
+	a ^= 0x55
+	t := int(a&0x0F) << 4
+
+	switch seg := (a & 0x70) >> 4; seg {
+	case 0:
+		t += 8
+	case 1:
+		t += 0x108
+	default:
+		t += 0x108
+		t <<= seg - 1
+	}
+
+	if a&0x80 != 0 {
+		return int16(t)
+	}
+
+	return int16(-t)
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestG711Decoder(t *testing.T) {
+	ulaw := NewG711Decoder(false, 0)
+	assert.Equal(t, 8000, ulaw.SampleRate())
+	assert.Equal(t, []int16{0, -32124, 32124}, ulaw.Decode([]byte{0xFF, 0x00, 0x80}))
+
+	alaw := NewG711Decoder(true, 16000)
+	assert.Equal(t, 16000, alaw.SampleRate())
+	assert.Equal(t, []int16{8, -8, 32256}, alaw.Decode([]byte{0xD5, 0x55, 0xAA}))
+}
+
+func TestG722Decoder(t *testing.T) {
+	dec := NewG722Decoder()
+	assert.Equal(t, 16000, dec.SampleRate())
+
+	// every code carries two samples of the 16 kHz signal
+	pcm := dec.Decode(make([]byte, 160))
+	assert.Len(t, pcm, 320)
+}
@@ -0,0 +1,246 @@
+package utils
+
+// G.722 64 kbit/s decoder, a port of the ITU-T G.722 reference algorithm.
+
+var (
+	g722Wl   = [8]int{-60, -30, 58, 172, 334, 538, 1198, 3042}
+	g722Rl42 = [16]int{0, 7, 6, 5, 4, 3, 2, 1, 7, 6, 5, 4, 3, 2, 1, 0}
+	g722Ilb  = [32]int{
+		2048, 2093, 2139, 2186, 2233, 2282, 2332, 2383,
+		2435, 2489, 2543, 2599, 2656, 2714, 2774, 2834,
+		2896, 2960, 3025, 3091, 3158, 3228, 3298, 3371,
+		3444, 3520, 3597, 3676, 3756, 3838, 3922, 4008,
+	}
+	g722Wh  = [3]int{0, -214, 798}
+	g722Rh2 = [4]int{2, 1, 2, 1}
+	g722Qm2 = [4]int{-7408, -1616, 7408, 1616}
+	g722Qm4 = [16]int{
+		0, -20456, -12896, -8968, -6288, -4240, -2584, -1200,
+		20456, 12896, 8968, 6288, 4240, 2584, 1200, 0,
+	}
+	g722Qm6 = [64]int{
+		-136, -136, -136, -136, -24808, -21904, -19008, -16704,
+		-14984, -13512, -12280, -11192, -10232, -9360, -8576, -7856,
+		-7192, -6576, -6000, -5456, -4944, -4464, -4008, -3576,
+		-3168, -2776, -2400, -2032, -1688, -1360, -1040, -728,
+		24808, 21904, 19008, 16704, 14984, 13512, 12280, 11192,
+		10232, 9360, 8576, 7856, 7192, 6576, 6000, 5456,
+		4944, 4464, 4008, 3576, 3168, 2776, 2400, 2032,
+		1688, 1360, 1040, 728, 432, 136, -432, -136,
+	}
+	g722Qmf = [12]int{3, -11, 12, 32, -210, 951, 3876, -805, 362, -156, 53, -11}
+)
+
+type g722Band struct {
+	s, sp, sz int
+	r, a, ap  [3]int
+	p         [3]int
+	d, b, bp  [7]int
+	sg        [7]int
+	nb, det   int
+}
+
+// G722Decoder decodes G.722 to 16 kHz PCM, the RTP clock rate of 8000 is the historic error of RFC 3551.
+type G722Decoder struct {
+	band [2]g722Band
+	x    [24]int
+}
+
+func NewG722Decoder() *G722Decoder {
+	d := &G722Decoder{}
+	d.band[0].det = 32
+	d.band[1].det = 8
+
+	return d
+}
+
+func (d *G722Decoder) SampleRate() int {
+	return 16000
+}
+
+func (d *G722Decoder) Decode(payload []byte) []int16 {
+	pcm := make([]int16, 0, len(payload)*2)
+
+	for _, code := range payload {
+		wd1 := int(code & 0x3F)
+		ihigh := int(code>>6) & 0x03
+		wd2 := g722Qm6[wd1]
+		wd1 >>= 2
+
+		low := &d.band[0]
+
+		// low band: invqbl, recons and limit
+		wd2 = (low.det * wd2) >> 15
+		rlow := clamp(low.s+wd2, -16384, 16383)
+
+		// invqal
+		dlowt := (low.det * g722Qm4[wd1]) >> 15
+
+		// logscl
+		wd2 = g722Rl42[wd1]
+		wd1 = (low.nb * 127) >> 7
+		low.nb = clamp(wd1+g722Wl[wd2], 0, 18432)
+
+		// scalel
+		low.det = g722Scale(low.nb, 8)
+
+		low.block4(dlowt)
+
+		high := &d.band[1]
+
+		// high band: invqah, recons and limit
+		dhigh := (high.det * g722Qm2[ihigh]) >> 15
+		rhigh := clamp(dhigh+high.s, -16384, 16383)
+
+		// logsch
+		wd1 = (high.nb * 127) >> 7
+		high.nb = clamp(wd1+g722Wh[g722Rh2[ihigh]], 0, 22528)
+
+		// scaleh
+		high.det = g722Scale(high.nb, 10)
+
+		high.block4(dhigh)
+
+		// receive QMF
+		copy(d.x[:22], d.x[2:])
+		d.x[22] = rlow + rhigh
+		d.x[23] = rlow - rhigh
+
+		var xout1, xout2 int
+		for i := 0; i < 12; i++ {
+			xout2 += d.x[2*i] * g722Qmf[i]
+			xout1 += d.x[2*i+1] * g722Qmf[11-i]
+		}
+
+		pcm = append(pcm, int16(saturate(xout1>>11)), int16(saturate(xout2>>11))) //nolint:gosec
+	}
+
+	return pcm
+}
+
+func g722Scale(nb, shift int) int {
+	wd1 := (nb >> 6) & 31
+	wd2 := shift - (nb >> 11)
+
+	var wd3 int
+	if wd2 < 0 {
+		wd3 = g722Ilb[wd1] << -wd2
+	} else {
+		wd3 = g722Ilb[wd1] >> wd2
+	}
+
+	return wd3 << 2
+}
+
+// block4 updates the adaptive predictor of the band.
+func (b *g722Band) block4(d int) {
+	// recons and parrec
+	b.d[0] = d
+	b.r[0] = saturate(b.s + d)
+	b.p[0] = saturate(b.sz + d)
+
+	// uppol2
+	for i := 0; i < 3; i++ {
+		b.sg[i] = b.p[i] >> 15
+	}
+
+	wd1 := saturate(b.a[1] << 2)
+
+	wd2 := wd1
+	if b.sg[0] == b.sg[1] {
+		wd2 = -wd1
+	}
+
+	if wd2 > 32767 {
+		wd2 = 32767
+	}
+
+	wd3 := wd2 >> 7
+	if b.sg[0] == b.sg[2] {
+		wd3 += 128
+	} else {
+		wd3 -= 128
+	}
+
+	wd3 += (b.a[2] * 32512) >> 15
+	b.ap[2] = clamp(wd3, -12288, 12288)
+
+	// uppol1
+	b.sg[0] = b.p[0] >> 15
+	b.sg[1] = b.p[1] >> 15
+
+	wd1 = -192
+	if b.sg[0] == b.sg[1] {
+		wd1 = 192
+	}
+
+	wd2 = (b.a[1] * 32640) >> 15
+	wd3 = saturate(15360 - b.ap[2])
+	b.ap[1] = clamp(saturate(wd1+wd2), -wd3, wd3)
+
+	// upzero
+	wd1 = 128
+	if d == 0 {
+		wd1 = 0
+	}
+
+	b.sg[0] = d >> 15
+	for i := 1; i < 7; i++ {
+		b.sg[i] = b.d[i] >> 15
+
+		wd2 = -wd1
+		if b.sg[i] == b.sg[0] {
+			wd2 = wd1
+		}
+
+		wd3 = (b.b[i] * 32640) >> 15
+		b.bp[i] = saturate(wd2 + wd3)
+	}
+
+	// delaya
+	for i := 6; i > 0; i-- {
+		b.d[i] = b.d[i-1]
+		b.b[i] = b.bp[i]
+	}
+
+	for i := 2; i > 0; i-- {
+		b.r[i] = b.r[i-1]
+		b.p[i] = b.p[i-1]
+		b.a[i] = b.ap[i]
+	}
+
+	// filtep
+	wd1 = saturate(b.r[1] + b.r[1])
+	wd1 = (b.a[1] * wd1) >> 15
+	wd2 = saturate(b.r[2] + b.r[2])
+	wd2 = (b.a[2] * wd2) >> 15
+	b.sp = saturate(wd1 + wd2)
+
+	// filtez
+	b.sz = 0
+	for i := 6; i > 0; i-- {
+		wd1 = saturate(b.d[i] + b.d[i])
+		b.sz += (b.b[i] * wd1) >> 15
+	}
+
+	b.sz = saturate(b.sz)
+
+	// predic
+	b.s = saturate(b.sp + b.sz)
+}
+
+func saturate(v int) int {
+	return clamp(v, -32768, 32767)
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+
+	if v > hi {
+		return hi
+	}
+
+	return v
+}
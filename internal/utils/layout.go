@@ -0,0 +1,416 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+// slot is the window of the video track on the frame, track is the position of the track in the shown ones.
+type slot struct {
+	track int
+	x, y  int
+	w, h  int
+}
+
+// layoutSlots places the shown video tracks of the roles on the frame. The screens take the main slots of the focus,
+// the picture-in-picture and the stacks, overlap tells the slots cover each other and are composed by the overlay.
+func layoutSlots(layout string, roles []string, width, height int) ([]slot, bool) {
+	n := len(roles)
+	if n == 0 {
+		return nil, false
+	}
+
+	if n == 1 {
+		return []slot{{w: width &^ 1, h: height &^ 1}}, false
+	}
+
+	order := make([]int, n)
+	for j := range order {
+		order[j] = j
+	}
+
+	switch layout {
+	case model.LayoutFocus, model.LayoutPiP, model.LayoutHStack, model.LayoutVStack:
+		slices.SortStableFunc(order, func(a, b int) int {
+			return screenFirst(roles[a]) - screenFirst(roles[b])
+		})
+	}
+
+	slots := make([]slot, n)
+
+	switch layout {
+	case model.LayoutFocus:
+		// the filmstrip of the other tracks is on the right of the main one
+		stripW := width / 5 &^ 1
+		thumbH := min(height/(n-1), stripW*9/16) &^ 1
+		top := (height - (n-1)*thumbH) / 2 &^ 1
+
+		slots[0] = slot{track: order[0], w: width - stripW, h: height &^ 1}
+		for k := 1; k < n; k++ {
+			slots[k] = slot{track: order[k], x: width - stripW, y: top + (k-1)*thumbH, w: stripW, h: thumbH}
+		}
+
+		return slots, false
+	case model.LayoutPiP:
+		// the corners are stacked up from the bottom right one, the next column starts on the left of the full one
+		cornerW, cornerH, margin := width/4&^1, height/4&^1, height/40&^1
+		perCol := max((height-margin)/(cornerH+margin), 1)
+
+		slots[0] = slot{track: order[0], w: width &^ 1, h: height &^ 1}
+		for k := 1; k < n; k++ {
+			col, row := (k-1)/perCol, (k-1)%perCol
+			slots[k] = slot{
+				track: order[k],
+				x:     max(width-(col+1)*(cornerW+margin), 0),
+				y:     height - (row+1)*(cornerH+margin),
+				w:     cornerW,
+				h:     cornerH,
+			}
+		}
+
+		return slots, true
+	case model.LayoutHStack:
+		w := width / n &^ 1
+		for k := range slots {
+			slots[k] = slot{track: order[k], x: k * w, w: w, h: height &^ 1}
+		}
+
+		return slots, false
+	case model.LayoutVStack:
+		h := height / n &^ 1
+		for k := range slots {
+			slots[k] = slot{track: order[k], y: k * h, w: width &^ 1, h: h}
+		}
+
+		return slots, false
+	}
+
+	// the grid, the windows are even for the yuv420p chroma
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	rows := int(math.Ceil(float64(n) / float64(cols)))
+	w, h := width/cols&^1, height/rows&^1
+
+	for k := range slots {
+		slots[k] = slot{track: order[k], x: k % cols * w, y: k / cols * h, w: w, h: h}
+	}
+
+	return slots, false
+}
+
+func screenFirst(role string) int {
+	if role == model.RoleScreen {
+		return 0
+	}
+
+	return 1
+}
+
+// composeFilter places the windows of the slots on the frame, the windows are the labels of the scaled tracks
+// in the order of the slots. The result is the start of the chain the caller pads to the frame and labels,
+// the overlapping slots are overlaid on the first one through the labels of the prefix.
+func composeFilter(windows []string, slots []slot, overlap bool, prefix string, width, height int) string {
+	if len(windows) == 1 {
+		return windows[0]
+	}
+
+	var f strings.Builder
+
+	if !overlap {
+		layout := make([]string, len(slots))
+		for k, s := range slots {
+			layout[k] = fmt.Sprintf("%d_%d", s.x, s.y)
+		}
+
+		f.WriteString(strings.Join(windows, ""))
+		f.WriteString(fmt.Sprintf("xstack=inputs=%d:layout=%s:fill=black,", len(windows), strings.Join(layout, "|")))
+
+		return f.String()
+	}
+
+	f.WriteString(fmt.Sprintf("%spad=%d:%d:%d:%d[%s_o0];", windows[0], width, height, slots[0].x, slots[0].y, prefix))
+	for k := 1; k < len(windows); k++ {
+		f.WriteString(fmt.Sprintf("[%s_o%d]%soverlay=%d:%d", prefix, k-1, windows[k], slots[k].x, slots[k].y))
+		if k+1 < len(windows) {
+			f.WriteString(fmt.Sprintf("[%s_o%d];", prefix, k))
+		}
+	}
+	f.WriteString(",")
+
+	return f.String()
+}
+
+// layoutThreshold is the join or the leave of the track close to the edge of the recording that does not
+// change the layout, ms.
+const layoutThreshold = 1000
+
+// layoutSpan is the part of the recording the set of the shown video tracks does not change in, ms.
+// The tracks are the positions in the video channels.
+type layoutSpan struct {
+	start  int
+	end    int
+	tracks []int
+}
+
+// layoutSpans splits the recording at the joins and the leaves of the video tracks, nil when all of them
+// are shown for the whole recording.
+func layoutSpans(src []model.MediaChannel, video []int, durationMs int) []layoutSpan {
+	window := func(ch model.MediaChannel) (int, int) {
+		start, end := min(ch.Offset, durationMs), durationMs
+		if start < layoutThreshold {
+			start = 0
+		}
+
+		if ch.Leave > 0 && ch.Leave < durationMs-layoutThreshold {
+			end = ch.Leave
+		}
+
+		return start, end
+	}
+
+	bounds := []int{0, durationMs}
+	changed := false
+
+	for _, idx := range video {
+		start, end := window(src[idx])
+		if start > 0 || end < durationMs {
+			changed = true
+		}
+
+		bounds = append(bounds, start, end)
+	}
+
+	if !changed || durationMs <= 0 {
+		return nil
+	}
+
+	slices.Sort(bounds)
+	bounds = slices.Compact(bounds)
+
+	var spans []layoutSpan
+
+	for i := 0; i+1 < len(bounds); i++ {
+		span := layoutSpan{start: bounds[i], end: bounds[i+1]}
+		for j, idx := range video {
+			if start, end := window(src[idx]); start <= span.start && end >= span.end {
+				span.tracks = append(span.tracks, j)
+			}
+		}
+
+		// the track that joins as the other one leaves keeps the layout
+		if n := len(spans); n > 0 && slices.Equal(spans[n-1].tracks, span.tracks) {
+			spans[n-1].end = span.end
+
+			continue
+		}
+
+		spans = append(spans, span)
+	}
+
+	return spans
+}
+
+// speakerThreshold is the shortest speech that makes the participant the active speaker, ms.
+const speakerThreshold = 1000
+
+// speakerSpans shows the video of the active speaker instead of the grid of the spans: the participant that started
+// to talk the last among the ones that talk. The grid stays until somebody talks and while the speaker has no video,
+// nil spans are the grid of the whole recording. The focus keeps the other tracks after the one of the speaker.
+func speakerSpans(src []model.MediaChannel, video []int, spans []layoutSpan, durationMs int, focus bool) []layoutSpan {
+	type speech struct {
+		peer       string
+		start, end int
+	}
+
+	var (
+		speeches []speech
+		points   []int
+	)
+
+	for _, ch := range src {
+		if ch.Peer == "" || !strings.HasPrefix(ch.MimeType, "audio") {
+			continue
+		}
+
+		for _, sp := range ch.Speech {
+			if sp.Duration >= speakerThreshold {
+				speeches = append(speeches, speech{peer: ch.Peer, start: sp.At, end: sp.At + sp.Duration})
+				points = append(points, sp.At, sp.At+sp.Duration)
+			}
+		}
+	}
+
+	if len(speeches) == 0 || durationMs <= 0 {
+		return spans
+	}
+
+	slices.Sort(points)
+	points = slices.Compact(points)
+
+	// the speaker changes at the starts and the ends of the speech only
+	type turn struct {
+		at   int
+		peer string
+	}
+
+	var turns []turn
+
+	for _, at := range points {
+		peer, start := "", -1
+		for _, sp := range speeches {
+			if sp.start <= at && at < sp.end && sp.start > start {
+				peer, start = sp.peer, sp.start
+			}
+		}
+
+		if peer != "" && (len(turns) == 0 || turns[len(turns)-1].peer != peer) {
+			turns = append(turns, turn{at: at, peer: peer})
+		}
+	}
+
+	if spans == nil {
+		all := make([]int, len(video))
+		for j := range video {
+			all[j] = j
+		}
+
+		spans = []layoutSpan{{start: 0, end: durationMs, tracks: all}}
+	}
+
+	var res []layoutSpan
+
+	add := func(span layoutSpan, start, end int, peer string) {
+		if end <= start {
+			return
+		}
+
+		tracks := span.tracks
+		// the camera of the speaker is its first video track
+		for k, j := range span.tracks {
+			if peer == "" || src[video[j]].Peer != peer {
+				continue
+			}
+
+			tracks = []int{j}
+			if focus {
+				tracks = append(tracks, slices.Delete(slices.Clone(span.tracks), k, k+1)...)
+			}
+
+			break
+		}
+
+		if n := len(res); n > 0 && slices.Equal(res[n-1].tracks, tracks) {
+			res[n-1].end = end
+
+			return
+		}
+
+		res = append(res, layoutSpan{start: start, end: end, tracks: tracks})
+	}
+
+	for _, span := range spans {
+		start, peer := span.start, ""
+		for _, t := range turns {
+			switch {
+			case t.at <= span.start:
+				peer = t.peer
+			case t.at < span.end:
+				add(span, start, t.at, peer)
+				start, peer = t.at, t.peer
+			}
+		}
+
+		add(span, start, span.end, peer)
+	}
+
+	return res
+}
+
+// caption draws the name of the room participant at the bottom of its window of the height, empty without the name.
+func caption(ch model.MediaChannel, height int) string {
+	if ch.Participant == "" {
+		return ""
+	}
+
+	size := max(height/24, 12)
+
+	return fmt.Sprintf(",drawtext=text='%s':expansion=none:fontsize=%d:fontcolor=white:box=1:boxcolor=black@0.5:"+
+		"boxborderw=%d:x=%d:y=h-th-%d", captionReplacer.Replace(ch.Participant), size, size/4, size/2, size/2)
+}
+
+// captionReplacer drops the characters the filter graph would parse from the caption.
+var captionReplacer = strings.NewReplacer("'", "’", "\\", "", ":", " ", ";", " ", ",", " ", "[", "(", "]", ")",
+	"\n", " ", "\r", " ")
+
+// timelineFilter composes the video tracks span by span: every span places its tracks by the layout and the spans
+// are joined in order, so the layout follows the tracks that join and leave the session.
+func timelineFilter(src []model.MediaChannel, scales []float64, video []int, spans []layoutSpan, layout string,
+	p *model.TranscodingProfile, fps string,
+) string {
+	var f strings.Builder
+
+	uses := make([]int, len(video))
+	for _, span := range spans {
+		for _, j := range span.tracks {
+			uses[j]++
+		}
+	}
+
+	for j, idx := range video {
+		if uses[j] == 0 {
+			continue
+		}
+
+		// the track holds its last frame up to its leave
+		f.WriteString(videoTiming(idx, src[idx], scales[idx]))
+		f.WriteString(fmt.Sprintf("tpad=stop_mode=clone:stop=-1,split=%d", uses[j]))
+		for n := 0; n < uses[j]; n++ {
+			f.WriteString(fmt.Sprintf("[v%d_s%d]", idx, n))
+		}
+		f.WriteString(";")
+	}
+
+	next := make([]int, len(video))
+
+	var segments strings.Builder
+
+	for i, span := range spans {
+		start, end := float64(span.start)/1000, float64(span.end)/1000
+
+		if len(span.tracks) == 0 {
+			f.WriteString(fmt.Sprintf("color=c=black:s=%dx%d:d=%.3f,", p.Width, p.Height, end-start))
+		} else {
+			roles := make([]string, len(span.tracks))
+			for k, j := range span.tracks {
+				roles[k] = src[video[j]].Role
+			}
+
+			slots, overlap := layoutSlots(layout, roles, p.Width, p.Height)
+
+			cells := make([]string, len(slots))
+			for k, sl := range slots {
+				j := span.tracks[sl.track]
+				f.WriteString(fmt.Sprintf(
+					"[v%d_s%d]trim=start=%.3f:end=%.3f,setpts=PTS-STARTPTS,"+
+						"scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2%s[seg%d_%d];",
+					video[j], next[j], start, end, sl.w, sl.h, sl.w, sl.h, caption(src[video[j]], sl.h), i, k,
+				))
+				cells[k] = fmt.Sprintf("[seg%d_%d]", i, k)
+				next[j]++
+			}
+
+			f.WriteString(composeFilter(cells, slots, overlap, fmt.Sprintf("seg%d", i), p.Width, p.Height))
+		}
+
+		// the parts of the concat have the same size and format
+		f.WriteString(fmt.Sprintf("pad=%d:%d,setsar=1,format=yuv420p[seg%d];", p.Width, p.Height, i))
+		segments.WriteString(fmt.Sprintf("[seg%d]", i))
+	}
+
+	f.WriteString(fmt.Sprintf("%sconcat=n=%d:v=1:a=0%s[v_out];", segments.String(), len(spans), fps))
+
+	return f.String()
+}
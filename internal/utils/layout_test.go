@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
+)
+
+func TestLayoutSpans(t *testing.T) {
+	// the camera of the whole call, the screen share from 10s to 20s and the late second camera
+	src := []model.MediaChannel{
+		{MimeType: "video/VP8", Path: "cam", Offset: 300},
+		{MimeType: "audio/opus", Path: "mic"},
+		{MimeType: "video/VP8", Path: "screen", Offset: 10000, Duration: 10000, Leave: 20000},
+		{MimeType: "video/VP8", Path: "cam2", Offset: 20000},
+	}
+
+	assert.Equal(t, []layoutSpan{
+		{start: 0, end: 10000, tracks: []int{0}},
+		{start: 10000, end: 20000, tracks: []int{0, 1}},
+		{start: 20000, end: 30000, tracks: []int{0}},
+	}, layoutSpans(src, []int{0, 2}, 30000))
+
+	assert.Equal(t, []layoutSpan{
+		{start: 0, end: 10000, tracks: []int{0}},
+		{start: 10000, end: 20000, tracks: []int{0, 1}},
+		{start: 20000, end: 30000, tracks: []int{0, 2}},
+	}, layoutSpans(src, []int{0, 2, 3}, 30000))
+
+	// the leave at the end of the recording
+	src[2].Leave = 29500
+	assert.Equal(t, []layoutSpan{
+		{start: 0, end: 10000, tracks: []int{0}},
+		{start: 10000, end: 30000, tracks: []int{0, 1}},
+	}, layoutSpans(src, []int{0, 2}, 30000))
+
+	// all the tracks from the start
+	assert.Nil(t, layoutSpans(src, []int{0}, 30000))
+
+	src[2].Leave = 20000
+	p := model.TranscodingProfiles["hd720"]
+	_, filter := transcodingArgs(src, []float64{1, 1, 1, 1}, 30000, "", &p)
+	assert.Equal(t, "[0:v]setpts=PTS-STARTPTS,tpad=start_duration=0.300,tpad=stop_mode=clone:stop=-1,split=3[v0_s0][v0_s1][v0_s2];"+
+		"[2:v]setpts=PTS-STARTPTS,tpad=start_duration=10.000,tpad=stop_mode=clone:stop=-1,split=1[v2_s0];"+
+		"[3:v]setpts=PTS-STARTPTS,tpad=start_duration=20.000,tpad=stop_mode=clone:stop=-1,split=1[v3_s0];"+
+		"[v0_s0]trim=start=0.000:end=10.000,setpts=PTS-STARTPTS,scale=1280:720:force_original_aspect_ratio=decrease,"+
+		"pad=1280:720:(ow-iw)/2:(oh-ih)/2[seg0_0];[seg0_0]pad=1280:720,setsar=1,format=yuv420p[seg0];"+
+		"[v0_s1]trim=start=10.000:end=20.000,setpts=PTS-STARTPTS,scale=640:720:force_original_aspect_ratio=decrease,"+
+		"pad=640:720:(ow-iw)/2:(oh-ih)/2[seg1_0];"+
+		"[v2_s0]trim=start=10.000:end=20.000,setpts=PTS-STARTPTS,scale=640:720:force_original_aspect_ratio=decrease,"+
+		"pad=640:720:(ow-iw)/2:(oh-ih)/2[seg1_1];"+
+		"[seg1_0][seg1_1]xstack=inputs=2:layout=0_0|640_0:fill=black,pad=1280:720,setsar=1,format=yuv420p[seg1];"+
+		"[v0_s2]trim=start=20.000:end=30.000,setpts=PTS-STARTPTS,scale=640:720:force_original_aspect_ratio=decrease,"+
+		"pad=640:720:(ow-iw)/2:(oh-ih)/2[seg2_0];"+
+		"[v3_s0]trim=start=20.000:end=30.000,setpts=PTS-STARTPTS,scale=640:720:force_original_aspect_ratio=decrease,"+
+		"pad=640:720:(ow-iw)/2:(oh-ih)/2[seg2_1];"+
+		"[seg2_0][seg2_1]xstack=inputs=2:layout=0_0|640_0:fill=black,pad=1280:720,setsar=1,format=yuv420p[seg2];"+
+		"[seg0][seg1][seg2]concat=n=3:v=1:a=0,fps=30[v_out];[1:a]amix=inputs=1[a_out]", filter[1])
+
+	// nobody shares the video in the middle of the call
+	src = []model.MediaChannel{
+		{MimeType: "video/VP8", Path: "cam", Duration: 5000, Leave: 5000},
+		{MimeType: "video/VP8", Path: "screen", Offset: 8000},
+	}
+	_, filter = transcodingArgs(src, []float64{1, 1}, 10000, "", &p)
+	assert.Contains(t, filter[1], "color=c=black:s=1280x720:d=3.000,pad=1280:720,setsar=1,format=yuv420p[seg1];")
+	assert.Contains(t, filter[1], "[seg0][seg1][seg2]concat=n=3:v=1:a=0,fps=30[v_out]")
+}
+
+func TestSpeakerSpans(t *testing.T) {
+	// two participants with cameras, the second one shares the screen from 20s
+	src := []model.MediaChannel{
+		{MimeType: "video/VP8", Path: "a_cam", Peer: "a", Participant: "Alice"},
+		{MimeType: "audio/opus", Path: "a_mic", Peer: "a", Speech: []model.Speech{
+			{At: 5000, Duration: 10000},
+			// the remark does not switch
+			{At: 25000, Duration: 500},
+		}},
+		{MimeType: "video/VP8", Path: "b_cam", Peer: "b", Participant: "Bob"},
+		{MimeType: "audio/opus", Path: "b_mic", Peer: "b", Speech: []model.Speech{{At: 10000, Duration: 2000}}},
+		{MimeType: "video/VP8", Path: "b_screen", Peer: "b", Offset: 20000},
+	}
+	video := []int{0, 2, 4}
+
+	// Alice talks again after the answer of Bob
+	assert.Equal(t, []layoutSpan{
+		{start: 0, end: 5000, tracks: []int{0, 1}},
+		{start: 5000, end: 10000, tracks: []int{0}},
+		{start: 10000, end: 12000, tracks: []int{1}},
+		{start: 12000, end: 30000, tracks: []int{0}},
+	}, speakerSpans(src, video[:2], nil, 30000, false))
+
+	// the speaker is shown over the grid spans
+	spans := layoutSpans(src, video, 30000)
+	assert.Equal(t, []layoutSpan{
+		{start: 0, end: 5000, tracks: []int{0, 1}},
+		{start: 5000, end: 10000, tracks: []int{0}},
+		{start: 10000, end: 12000, tracks: []int{1}},
+		{start: 12000, end: 30000, tracks: []int{0}},
+	}, speakerSpans(src, video, spans, 30000, false))
+
+	// the focus keeps the other camera in the filmstrip
+	assert.Equal(t, []layoutSpan{
+		{start: 0, end: 10000, tracks: []int{0, 1}},
+		{start: 10000, end: 12000, tracks: []int{1, 0}},
+		{start: 12000, end: 30000, tracks: []int{0, 1}},
+	}, speakerSpans(src, video[:2], nil, 30000, true))
+
+	// nobody talks
+	assert.Nil(t, speakerSpans(src[:1], video[:1], nil, 30000, false))
+
+	p := model.TranscodingProfiles["hd720"]
+	_, filter := transcodingArgs(src, []float64{1, 1, 1, 1, 1}, 30000, model.LayoutSpeaker, &p)
+	assert.Contains(t, filter[1], "[v2_s0]trim=start=0.000:end=5.000,setpts=PTS-STARTPTS,"+
+		"scale=640:720:force_original_aspect_ratio=decrease,pad=640:720:(ow-iw)/2:(oh-ih)/2,"+
+		"drawtext=text='Bob':expansion=none:fontsize=30:fontcolor=white:box=1:boxcolor=black@0.5:boxborderw=7:x=15:y=h-th-15[seg0_1];")
+	assert.Contains(t, filter[1], "[seg0][seg1][seg2][seg3]concat=n=4:v=1:a=0,fps=30[v_out]")
+	assert.Contains(t, filter[1], "amix=inputs=2[a_out]")
+
+	// the grid of the room
+	_, filter = transcodingArgs(src[:4], []float64{1, 1, 1, 1}, 30000, model.LayoutGrid, &p)
+	assert.Contains(t, filter[1], "pad=640:720:(ow-iw)/2:(oh-ih)/2,drawtext=text='Alice'")
+	assert.Contains(t, filter[1], "xstack=inputs=2:layout=0_0|640_0:fill=black,pad=1280:720,fps=30[v_out]")
+}
+
+func TestCaption(t *testing.T) {
+	assert.Empty(t, caption(model.MediaChannel{}, 720))
+	assert.Contains(t, caption(model.MediaChannel{Participant: "O'Brien: [ops], 2\n"}, 240), "text='O’Brien  (ops)  2 ':expansion=none:fontsize=12:")
+}
+
+func TestLayoutSlots(t *testing.T) {
+	cams := []string{model.RoleCamera, model.RoleCamera, model.RoleCamera}
+	shared := []string{model.RoleCamera, model.RoleScreen, model.RoleCamera}
+
+	slots, overlap := layoutSlots(model.LayoutGrid, cams, 1280, 720)
+	assert.False(t, overlap)
+	assert.Equal(t, []slot{
+		{track: 0, x: 0, y: 0, w: 640, h: 360},
+		{track: 1, x: 640, y: 0, w: 640, h: 360},
+		{track: 2, x: 0, y: 360, w: 640, h: 360},
+	}, slots)
+
+	// the screen is the main window and the cameras are in the filmstrip
+	slots, overlap = layoutSlots(model.LayoutFocus, shared, 1280, 720)
+	assert.False(t, overlap)
+	assert.Equal(t, []slot{
+		{track: 1, x: 0, y: 0, w: 1024, h: 720},
+		{track: 0, x: 1024, y: 216, w: 256, h: 144},
+		{track: 2, x: 1024, y: 360, w: 256, h: 144},
+	}, slots)
+
+	// the cameras are stacked up in the corner over the screen
+	slots, overlap = layoutSlots(model.LayoutPiP, shared, 1280, 720)
+	assert.True(t, overlap)
+	assert.Equal(t, []slot{
+		{track: 1, x: 0, y: 0, w: 1280, h: 720},
+		{track: 0, x: 942, y: 522, w: 320, h: 180},
+		{track: 2, x: 942, y: 324, w: 320, h: 180},
+	}, slots)
+
+	slots, _ = layoutSlots(model.LayoutHStack, cams, 1280, 720)
+	assert.Equal(t, []slot{
+		{track: 0, x: 0, y: 0, w: 426, h: 720},
+		{track: 1, x: 426, y: 0, w: 426, h: 720},
+		{track: 2, x: 852, y: 0, w: 426, h: 720},
+	}, slots)
+
+	slots, _ = layoutSlots(model.LayoutVStack, shared[:2], 1280, 720)
+	assert.Equal(t, []slot{
+		{track: 1, x: 0, y: 0, w: 1280, h: 360},
+		{track: 0, x: 0, y: 360, w: 1280, h: 360},
+	}, slots)
+
+	// the single track takes the frame in any layout
+	slots, overlap = layoutSlots(model.LayoutPiP, shared[:1], 1280, 720)
+	assert.False(t, overlap)
+	assert.Equal(t, []slot{{w: 1280, h: 720}}, slots)
+}
+
+func TestComposeFilter(t *testing.T) {
+	slots, overlap := layoutSlots(model.LayoutPiP, []string{model.RoleCamera, model.RoleScreen, model.RoleCamera}, 1280, 720)
+	assert.Equal(t, "[a]pad=1280:720:0:0[v_o0];[v_o0][b]overlay=942:522[v_o1];[v_o1][c]overlay=942:324,",
+		composeFilter([]string{"[a]", "[b]", "[c]"}, slots, overlap, "v", 1280, 720))
+
+	slots, overlap = layoutSlots(model.LayoutHStack, []string{model.RoleCamera, model.RoleCamera}, 1280, 720)
+	assert.Equal(t, "[a][b]xstack=inputs=2:layout=0_0|640_0:fill=black,",
+		composeFilter([]string{"[a]", "[b]"}, slots, overlap, "v", 1280, 720))
+
+	// the camera of the screen recording in the corner
+	src := []model.MediaChannel{
+		{MimeType: "video/VP8", Path: "cam", Role: model.RoleCamera},
+		{MimeType: "video/VP8", Path: "screen", Role: model.RoleScreen},
+	}
+	p := model.TranscodingProfiles["hd720"]
+	_, filter := transcodingArgs(src, []float64{1, 1}, 30000, model.LayoutPiP, &p)
+	assert.Equal(t, "[1:v]scale=1280:720:force_original_aspect_ratio=decrease[v_scaled_0]; "+
+		"[v_scaled_0]pad=1280:720:(ow-iw)/2:(oh-ih)/2[v_norm_0]; "+
+		"[0:v]scale=320:180:force_original_aspect_ratio=decrease[v_scaled_1]; "+
+		"[v_scaled_1]pad=320:180:(ow-iw)/2:(oh-ih)/2[v_norm_1]; "+
+		"[v_norm_0]pad=1280:720:0:0[v_o0];[v_o0][v_norm_1]overlay=942:522,pad=1280:720,fps=30[v_out]", filter[1])
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+const (
+	ivfHeaderSize      = 32
+	ivfFrameHeaderSize = 12
+	oggPageHeaderSize  = 27
+	wavHeaderSize      = 44
+)
+
+var (
+	ErrInvalidIVF = errors.New("invalid ivf header")
+	ErrInvalidOgg = errors.New("invalid ogg page")
+	ErrInvalidWAV = errors.New("invalid wav header")
+
+	oggCapturePattern = []byte("OggS")
+)
+
+// RepairIVF truncates the unfinished frame of an IVF file left by a crashed writer and
+// stores the real frame count in the header. It returns the number of complete frames.
+func RepairIVF(path string) (int, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	header := make([]byte, ivfHeaderSize)
+	if _, err = io.ReadFull(f, header); err != nil || !bytes.Equal(header[:4], []byte("DKIF")) {
+		return 0, ErrInvalidIVF
+	}
+
+	var (
+		count  int
+		offset int64 = ivfHeaderSize
+		frame        = make([]byte, ivfFrameHeaderSize)
+	)
+
+	for {
+		if _, err = f.ReadAt(frame, offset); err != nil {
+			break
+		}
+
+		end := offset + ivfFrameHeaderSize + int64(binary.LittleEndian.Uint32(frame))
+		if !complete(f, end) {
+			break
+		}
+
+		count++
+		offset = end
+	}
+
+	if err = f.Truncate(offset); err != nil {
+		return 0, err
+	}
+
+	binary.LittleEndian.PutUint32(header[24:], uint32(count)) //nolint:gosec
+
+	if _, err = f.WriteAt(header[24:28], 24); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// RepairOgg truncates the unfinished page of an Ogg file left by a crashed writer.
+// It returns the number of complete pages.
+func RepairOgg(path string) (int, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var (
+		count  int
+		offset int64
+		header = make([]byte, oggPageHeaderSize)
+	)
+
+	for {
+		if _, err = f.ReadAt(header, offset); err != nil {
+			break
+		}
+
+		if !bytes.Equal(header[:4], oggCapturePattern) {
+			if count == 0 {
+				return 0, ErrInvalidOgg
+			}
+
+			break
+		}
+
+		segments := make([]byte, header[26])
+		if _, err = f.ReadAt(segments, offset+oggPageHeaderSize); err != nil {
+			break
+		}
+
+		end := offset + oggPageHeaderSize + int64(len(segments))
+		for _, s := range segments {
+			end += int64(s)
+		}
+
+		if !complete(f, end) {
+			break
+		}
+
+		count++
+		offset = end
+	}
+
+	if count == 0 {
+		return 0, ErrInvalidOgg
+	}
+
+	return count, f.Truncate(offset)
+}
+
+// RepairWAV stores the RIFF and data sizes a crashed writer did not patch and truncates the unfinished sample.
+// It returns the size of the PCM data.
+func RepairWAV(path string) (int, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	header := make([]byte, wavHeaderSize)
+	if _, err = io.ReadFull(f, header); err != nil || !bytes.Equal(header[:4], []byte("RIFF")) ||
+		!bytes.Equal(header[36:40], []byte("data")) {
+		return 0, ErrInvalidWAV
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	size := st.Size() - wavHeaderSize
+	if align := int64(binary.LittleEndian.Uint16(header[32:])); align > 0 {
+		size -= size % align
+	}
+
+	if err = f.Truncate(wavHeaderSize + size); err != nil {
+		return 0, err
+	}
+
+	binary.LittleEndian.PutUint32(header[4:], uint32(36+size)) //nolint:gosec
+	binary.LittleEndian.PutUint32(header[40:], uint32(size))   //nolint:gosec
+
+	if _, err = f.WriteAt(header[4:8], 4); err != nil {
+		return 0, err
+	}
+
+	if _, err = f.WriteAt(header[40:], 40); err != nil {
+		return 0, err
+	}
+
+	return int(size), nil
+}
+
+func complete(f *os.File, end int64) bool {
+	st, err := f.Stat()
+
+	return err == nil && end <= st.Size()
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media/oggwriter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairIVF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.ivf")
+
+	header := make([]byte, ivfHeaderSize)
+	copy(header, "DKIF")
+	binary.LittleEndian.PutUint16(header[6:], ivfHeaderSize)
+	copy(header[8:], "VP90")
+	binary.LittleEndian.PutUint32(header[24:], 900)
+
+	data := header
+	for i := 0; i < 3; i++ {
+		frame := make([]byte, ivfFrameHeaderSize+10)
+		binary.LittleEndian.PutUint32(frame, 10)
+		binary.LittleEndian.PutUint64(frame[4:], uint64(i))
+		data = append(data, frame...)
+	}
+	size := len(data)
+
+	// unfinished frame of the crashed writer
+	broken := make([]byte, ivfFrameHeaderSize+3)
+	binary.LittleEndian.PutUint32(broken, 10)
+	data = append(data, broken...)
+
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	count, err := RepairIVF(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	out, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, out, size)
+	assert.Equal(t, uint32(3), binary.LittleEndian.Uint32(out[24:]))
+}
+
+func TestRepairIVF_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.ivf")
+	require.NoError(t, os.WriteFile(path, []byte("not an ivf"), 0o644))
+
+	_, err := RepairIVF(path)
+	assert.ErrorIs(t, err, ErrInvalidIVF)
+}
+
+func TestRepairOgg(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.ogg")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	w, err := oggwriter.NewWith(f, 48000, 2)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, w.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{Timestamp: uint32(i * 960)},
+			Payload: []byte{0x1, 0x2, 0x3, 0x4},
+		}))
+	}
+	require.NoError(t, f.Close())
+
+	st, err := os.Stat(path)
+	require.NoError(t, err)
+
+	// 2 header pages + 5 audio pages, cut in the middle of the last one
+	require.NoError(t, os.Truncate(path, st.Size()-2))
+
+	count, err := RepairOgg(path)
+	require.NoError(t, err)
+	assert.Equal(t, 6, count)
+
+	repaired, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, st.Size()-int64(oggPageHeaderSize+1+4), repaired.Size())
+}
+
+func TestRepairWAV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.wav")
+
+	header := make([]byte, wavHeaderSize)
+	copy(header, "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], 36)
+	copy(header[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint16(header[32:], 2)
+	copy(header[36:], "data")
+
+	// the writer crashed in the middle of a sample
+	require.NoError(t, os.WriteFile(path, append(header, make([]byte, 101)...), 0o644))
+
+	size, err := RepairWAV(path)
+	require.NoError(t, err)
+	assert.Equal(t, 100, size)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, data, wavHeaderSize+100)
+	assert.Equal(t, uint32(136), binary.LittleEndian.Uint32(data[4:]))
+	assert.Equal(t, uint32(100), binary.LittleEndian.Uint32(data[40:]))
+}
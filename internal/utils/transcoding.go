@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -61,19 +62,31 @@ func NewTranscoding(src io.ReadCloser, writer io.Writer) (*Transcoding, error) {
 	}, nil
 }
 
+// trackInput opens the track, the raw elementary streams have no header ffmpeg could probe reliably.
+func trackInput(ch model.MediaChannel) []string {
+	switch strings.ToLower(ch.MimeType) {
+	case "video/h264":
+		return []string{"-f", "h264", "-i", ch.Path}
+	case "video/h265":
+		return []string{"-f", "hevc", "-i", ch.Path}
+	default:
+		return []string{"-i", ch.Path}
+	}
+}
+
 // probeActualDuration returns the real playback duration of a media file by
 // decoding it through ffmpeg. This is necessary for IVF files where the
 // container header stores frame-count metadata, not actual last-PTS duration.
-func probeActualDuration(path string) (float64, error) {
-	var stderr bytes.Buffer
-	cmd := exec.Command("ffmpeg",
-		"-v", "quiet",
-		"-stats",
-		"-i", path,
+func probeActualDuration(ch model.MediaChannel) (float64, error) {
+	args := append([]string{"-v", "quiet", "-stats"}, trackInput(ch)...)
+	args = append(args,
 		"-c", "copy",
 		"-f", "null",
 		os.DevNull,
 	)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("ffmpeg", args...)
 	cmd.Stderr = &stderr
 	_ = cmd.Run()
 	ms := parseDurationFromFFmpeg(stderr.String())
@@ -83,7 +96,129 @@ func probeActualDuration(path string) (float64, error) {
 	return float64(ms) / 1000.0, nil
 }
 
-func transcodingArgs(src []model.MediaChannel, videoScale float64) ([]string, []string) {
+// rtpTimed tells the video track plays by the RTP timestamps of its frames: the IVF writer keeps them and the sender
+// reports place the track, the raw H.264 and H.265 streams have no timestamps and are read at a constant frame rate.
+func rtpTimed(ch model.MediaChannel) bool {
+	if len(ch.Clock) == 0 || !strings.HasPrefix(ch.MimeType, "video") {
+		return false
+	}
+
+	switch strings.ToLower(ch.MimeType) {
+	case "video/h264", "video/h265":
+		return false
+	default:
+		return true
+	}
+}
+
+// timestampGaps moves the gaps of the recorded media to the timestamps of the frames, where every gap is a hole.
+func timestampGaps(gaps []model.Gap) []model.Gap {
+	res := make([]model.Gap, len(gaps))
+	shift := 0
+
+	for i, g := range gaps {
+		res[i] = g
+		res[i].At += shift
+		shift += g.Duration
+	}
+
+	return res
+}
+
+// videoTiming is the head of the filter chain of the video input idx: it stretches the track to its wall clock
+// duration, holds the last frame through the gaps and delays the track by its offset.
+func videoTiming(idx int, ch model.MediaChannel, scale float64) string {
+	f := fmt.Sprintf("[%d:v]", idx)
+
+	switch {
+	case math.Abs(scale-1.0) > 0.001:
+		f += fmt.Sprintf("setpts=%.6f*(PTS-STARTPTS),", scale)
+	case ch.Offset > 0 || len(ch.Gaps) > 0:
+		f += "setpts=PTS-STARTPTS,"
+	}
+
+	gaps := ch.Gaps
+	if rtpTimed(ch) {
+		// the part after the gap starts from its first frame, the hole is filled by the pad
+		gaps = timestampGaps(gaps)
+	}
+
+	f += fillGaps(fmt.Sprintf("v%d", idx), gaps, "split", "trim", "setpts", "v=1:a=0", func(g model.Gap) string {
+		if g.Redacted {
+			// the placeholder of the paused recording
+			return fmt.Sprintf("tpad=stop_mode=add:color=black:stop_duration=%.3f", float64(g.Duration)/1000)
+		}
+
+		return fmt.Sprintf("tpad=stop_mode=clone:stop_duration=%.3f", float64(g.Duration)/1000)
+	})
+
+	if ch.Offset > 0 {
+		f += fmt.Sprintf("tpad=start_duration=%.3f,", float64(ch.Offset)/1000)
+	}
+
+	return f
+}
+
+// audioTiming is the filter chain of the audio input idx to the label: it fills the gaps with silence
+// and delays the track by its offset, empty when the track needs neither.
+func audioTiming(idx int, ch model.MediaChannel, label string) string {
+	if ch.Offset <= 0 && len(ch.Gaps) == 0 {
+		return ""
+	}
+
+	f := fmt.Sprintf("[%d:a]", idx)
+	f += fillGaps(fmt.Sprintf("a%d", idx), ch.Gaps, "asplit", "atrim", "asetpts", "v=0:a=1", func(g model.Gap) string {
+		return fmt.Sprintf("apad=pad_dur=%.3f", float64(g.Duration)/1000)
+	})
+
+	if ch.Offset > 0 {
+		// the late track starts after the silence
+		f += fmt.Sprintf("adelay=%d:all=1,", ch.Offset)
+	}
+
+	return strings.TrimSuffix(f, ",") + fmt.Sprintf("[%s];", label)
+}
+
+// fillGaps cuts the stream at the gaps, pads every part by its gap and joins them back,
+// the names are the split, trim and timestamps filters of the stream type, pad is the filter of the gap.
+func fillGaps(prefix string, gaps []model.Gap, split, trim, setpts, kind string, pad func(model.Gap) string) string {
+	if len(gaps) == 0 {
+		return ""
+	}
+
+	var f, parts strings.Builder
+
+	f.WriteString(fmt.Sprintf("%s=%d", split, len(gaps)+1))
+	for i := 0; i <= len(gaps); i++ {
+		f.WriteString(fmt.Sprintf("[%s_p%d]", prefix, i))
+	}
+	f.WriteString(";")
+
+	start := 0.0
+	for i := 0; i <= len(gaps); i++ {
+		f.WriteString(fmt.Sprintf("[%s_p%d]%s=start=%.3f", prefix, i, trim, start))
+
+		if i < len(gaps) {
+			start = float64(gaps[i].At) / 1000
+			f.WriteString(fmt.Sprintf(":end=%.3f,%s=PTS-STARTPTS,%s", start, setpts, pad(gaps[i])))
+		} else {
+			f.WriteString(fmt.Sprintf(",%s=PTS-STARTPTS", setpts))
+		}
+
+		f.WriteString(fmt.Sprintf("[%s_g%d];", prefix, i))
+		parts.WriteString(fmt.Sprintf("[%s_g%d]", prefix, i))
+	}
+
+	f.WriteString(fmt.Sprintf("%sconcat=n=%d:%s,", parts.String(), len(gaps)+1, kind))
+
+	return f.String()
+}
+
+// transcodingArgs builds the inputs and the filter graph of the tracks, scales holds the timestamp scale of each track,
+// durationMs is the length of the recording and layout places its video tracks.
+func transcodingArgs(src []model.MediaChannel, scales []float64, durationMs int, layout string,
+	p *model.TranscodingProfile,
+) ([]string, []string) {
 	if len(src) == 0 {
 		return nil, nil
 	}
@@ -94,6 +229,9 @@ func transcodingArgs(src []model.MediaChannel, videoScale float64) ([]string, []
 	// Розділяємо вхідні потоки на відео та аудіо
 	for i, s := range src {
 		if strings.HasPrefix(s.MimeType, "video") {
+			if p.AudioOnly {
+				continue
+			}
 			videoChannels = append(videoChannels, i)
 		} else if strings.HasPrefix(s.MimeType, "audio") {
 			audioChannels = append(audioChannels, i)
@@ -112,70 +250,64 @@ func transcodingArgs(src []model.MediaChannel, videoScale float64) ([]string, []
 	var finalMapArgs []string
 
 	for i := 0; i < len(src); i++ {
-		inputArgs = append(inputArgs, "-i", src[i].Path)
+		inputArgs = append(inputArgs, trackInput(src[i])...)
 	}
 
-	setPts := ""
-	if math.Abs(videoScale-1.0) > 0.001 {
-		setPts = fmt.Sprintf("setpts=%.6f*PTS,", videoScale)
+	fps := ""
+	if p.FPS > 0 {
+		fps = fmt.Sprintf(",fps=%d", p.FPS)
 	}
 
 	if videoCount > 0 {
+		spans := layoutSpans(src, videoChannels, durationMs)
+		if layout == model.LayoutSpeaker || layout == model.LayoutFocus {
+			spans = speakerSpans(src, videoChannels, spans, durationMs, layout == model.LayoutFocus)
+		}
+
 		if videoCount == 1 {
-			filterComplexBuilder.WriteString(fmt.Sprintf("[%d:v]%sscale=1920:1080:force_original_aspect_ratio=decrease,pad=1920:1080:(ow-iw)/2:(oh-ih)/2[v_out];", videoChannels[0], setPts))
+			filterComplexBuilder.WriteString(fmt.Sprintf("%sscale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2%s%s[v_out];",
+				videoTiming(videoChannels[0], src[videoChannels[0]], scales[videoChannels[0]]), p.Width, p.Height, p.Width, p.Height,
+				caption(src[videoChannels[0]], p.Height), fps))
+		} else if spans != nil {
+			filterComplexBuilder.WriteString(timelineFilter(src, scales, videoChannels, spans, layout, p, fps))
 		} else {
-			cols := int(math.Ceil(math.Sqrt(float64(videoCount))))
-			rows := int(math.Ceil(float64(videoCount) / float64(cols)))
-
-			const finalWidth = 1920
-			const finalHeight = 1080
+			roles := make([]string, videoCount)
+			for i, videoIdx := range videoChannels {
+				roles[i] = src[videoIdx].Role
+			}
 
-			windowWidth := finalWidth / cols
-			windowHeight := finalHeight / rows
+			slots, overlap := layoutSlots(layout, roles, p.Width, p.Height)
 
-			var inputStreamsForXstack []string
-			for i, videoIdx := range videoChannels {
-				streamName := fmt.Sprintf("v_norm_%d", i)
+			windows := make([]string, len(slots))
+			for k, sl := range slots {
+				videoIdx := videoChannels[sl.track]
 				filter := fmt.Sprintf(
-					"[%d:v]%sscale=%d:%d:force_original_aspect_ratio=decrease[v_scaled_%d]; "+
-						"[v_scaled_%d]pad=%d:%d:(ow-iw)/2:(oh-ih)/2[%s]; ",
-					videoIdx, setPts, windowWidth, windowHeight, i,
-					i, windowWidth, windowHeight, streamName,
+					"%sscale=%d:%d:force_original_aspect_ratio=decrease[v_scaled_%d]; "+
+						"[v_scaled_%d]pad=%d:%d:(ow-iw)/2:(oh-ih)/2%s[v_norm_%d]; ",
+					videoTiming(videoIdx, src[videoIdx], scales[videoIdx]), sl.w, sl.h, k,
+					k, sl.w, sl.h, caption(src[videoIdx], sl.h), k,
 				)
 				filterComplexBuilder.WriteString(filter)
-				inputStreamsForXstack = append(inputStreamsForXstack, fmt.Sprintf("[%s]", streamName))
-			}
-
-			var layout []string
-			for r := 0; r < rows; r++ {
-				for c := 0; c < cols; c++ {
-					index := r*cols + c
-					if index >= videoCount {
-						break
-					}
-					posX := c * windowWidth
-					posY := r * windowHeight
-					layout = append(layout, fmt.Sprintf("%d_%d", posX, posY))
-				}
+				windows[k] = fmt.Sprintf("[v_norm_%d]", k)
 			}
 
-			inputStreams := strings.Join(inputStreamsForXstack, "")
-			layoutString := strings.Join(layout, "|")
-
-			xstackFilter := fmt.Sprintf(
-				"%sxstack=inputs=%d:layout=%s[v_out];",
-				inputStreams, videoCount, layoutString,
-			)
-			filterComplexBuilder.WriteString(xstackFilter)
+			filterComplexBuilder.WriteString(fmt.Sprintf("%spad=%d:%d%s[v_out];",
+				composeFilter(windows, slots, overlap, "v", p.Width, p.Height), p.Width, p.Height, fps))
 		}
 		finalMapArgs = append(finalMapArgs, "-map", "[v_out]")
 	}
 
 	if audioCount > 0 {
-		for _, audioIdx := range audioChannels {
-			filterComplexBuilder.WriteString(fmt.Sprintf("[%d:a]", audioIdx))
+		var mix strings.Builder
+		for i, audioIdx := range audioChannels {
+			if f := audioTiming(audioIdx, src[audioIdx], fmt.Sprintf("a_d%d", i)); f != "" {
+				filterComplexBuilder.WriteString(f)
+				mix.WriteString(fmt.Sprintf("[a_d%d]", i))
+			} else {
+				mix.WriteString(fmt.Sprintf("[%d:a]", audioIdx))
+			}
 		}
-		filterComplexBuilder.WriteString(fmt.Sprintf("amix=inputs=%d[a_out]", audioCount))
+		filterComplexBuilder.WriteString(fmt.Sprintf("%samix=inputs=%d[a_out]", mix.String(), audioCount))
 		finalMapArgs = append(finalMapArgs, "-map", "[a_out]")
 	}
 
@@ -185,50 +317,235 @@ func transcodingArgs(src []model.MediaChannel, videoScale float64) ([]string, []
 	return inputArgs, finalArgs
 }
 
-func TranscodingByPath(src []model.MediaChannel, dst string, actualDurationMs int) (int, error) {
-	var videoScale = 1.0
+// outputArgs are the encoder and muxer arguments of the profile.
+func outputArgs(p *model.TranscodingProfile) []string {
+	args := []string{"-c:a", p.AudioCodec}
+	if p.AudioBitrate != "" {
+		args = append(args, "-b:a", p.AudioBitrate)
+	}
 
-	for _, ch := range src {
-		dur, err := probeActualDuration(ch.Path)
-		if err == nil {
-			fmt.Printf("[Transcoding] actual duration %s (%s): %.3fs\n", ch.MimeType, ch.Path, dur)
-		} else {
-			fmt.Printf("[Transcoding] actual duration %s (%s): error: %v\n", ch.MimeType, ch.Path, err)
+	if p.AudioOnly {
+		args = append(args, "-vn")
+	} else {
+		args = append(args, "-c:v", p.VideoCodec)
+
+		if p.CRF != nil {
+			args = append(args, "-crf", strconv.Itoa(*p.CRF))
 		}
 
-		if actualDurationMs > 0 && strings.HasPrefix(ch.MimeType, "video") && err == nil && dur > 0.5 {
-			actualDurSec := float64(actualDurationMs) / 1000.0
-			videoScale = actualDurSec / dur
-			fmt.Printf("[Transcoding] video actual=%.3fs ivf=%.3fs scale=%.6f\n",
-				actualDurSec, dur, videoScale)
+		switch {
+		case p.VideoBitrate != "":
+			args = append(args, "-b:v", p.VideoBitrate)
+		case p.CRF != nil && p.VideoCodec == "libvpx-vp9":
+			// constant quality mode of vp9
+			args = append(args, "-b:v", "0")
+		}
+
+		if p.VideoCodec == "libx264" || p.VideoCodec == "libx265" {
+			if p.Preset != "" {
+				args = append(args, "-preset", p.Preset)
+			}
+
+			if p.Tune != "" {
+				args = append(args, "-tune", p.Tune)
+			}
 		}
 	}
 
+	if f := p.Format(); f == "mp4" || f == "ipod" {
+		args = append(args, "-movflags", "+faststart")
+	}
+
+	return append(args, "-f", p.Format())
+}
+
+// trackScales are the ratios of the wall clock duration of the video tracks to their played duration. The tracks
+// with the sender reports that keep the RTP timestamps are not scaled, the raw H.264 and H.265 streams have no
+// timestamps and are stretched to the duration of their sender reports, the tracks without the reports fall back
+// to the duration of the whole recording.
+func trackScales(src []model.MediaChannel, actualDurationMs int) []float64 {
+	scales := make([]float64, len(src))
+
+	for i, ch := range src {
+		scales[i] = 1.0
+
+		if !strings.HasPrefix(ch.MimeType, "video") || rtpTimed(ch) {
+			continue
+		}
+
+		// the track that can not be probed is played as it is
+		dur, err := probeActualDuration(ch)
+		if err != nil {
+			continue
+		}
+
+		scales[i] = timingScale(ch, actualDurationMs, dur)
+	}
+
+	return scales
+}
+
+// timingScale is the scale of the track played for dur seconds.
+func timingScale(ch model.MediaChannel, actualDurationMs int, dur float64) float64 {
+	wall := ch.Duration
+	if wall <= 0 {
+		wall = actualDurationMs
+	}
+
+	// the gaps are filled after the scale
+	wall -= ch.GapsDuration()
+
+	if wall <= 0 || dur <= 0.5 {
+		return 1.0
+	}
+
+	return float64(wall) / 1000.0 / dur
+}
+
+// TranscodingByPath encodes the tracks into dst by the profile and the layout, the markers are written as the chapters
+// and the optional WebVTT file as the subtitle track.
+func TranscodingByPath(src []model.MediaChannel, markers []model.Marker, subtitles, dst string, actualDurationMs int,
+	layout string, p *model.TranscodingProfile,
+) (int, error) {
 	args := []string{
 		"-nostdin",
 		"-threads", "1",
 	}
 
-	inputArgs, finalArgs := transcodingArgs(src, videoScale)
+	inputArgs, finalArgs := transcodingArgs(src, trackScales(src, actualDurationMs), recordingDuration(src, actualDurationMs),
+		layout, p)
 	args = append(args, inputArgs...)
 
 	if finalArgs == nil {
 		return 0, nil
 	}
+
+	chapters, err := writeChapters(markers, recordingDuration(src, actualDurationMs), dst)
+	if err != nil {
+		return 0, err
+	}
+
+	if chapters != "" {
+		defer os.Remove(chapters)
+	}
+
+	sideIn, sideOut := sideInputs(len(src), chapters, subtitles, p.SubtitleCodec())
+	args = append(args, sideIn...)
 	args = append(args, finalArgs...)
+	args = append(args, sideOut...)
+	args = append(args, outputArgs(p)...)
+	args = append(args, dst)
 
-	args = append(args,
-		"-c:a", "aac",
-		"-b:a", "192k",
+	return runFFmpeg(args)
+}
+
+// RemuxContainer returns the container the tracks fit without re-encoding:
+// a single VP8, VP9 or AV1 video with Opus goes to webm, a single H264 or H265 video with Opus or AAC goes to mp4.
+func RemuxContainer(src []model.MediaChannel) (string, bool) {
+	var video, audio []string
+
+	for _, s := range src {
+		// the gaps are filled by the encoder
+		if len(s.Gaps) > 0 {
+			return "", false
+		}
+
+		switch {
+		case strings.HasPrefix(s.MimeType, "video"):
+			video = append(video, strings.ToLower(s.MimeType))
+		case strings.HasPrefix(s.MimeType, "audio"):
+			audio = append(audio, strings.ToLower(s.MimeType))
+		}
+	}
+
+	// composition of several tracks needs the encoder
+	if len(video) != 1 || len(audio) > 1 {
+		return "", false
+	}
+
+	switch video[0] {
+	case "video/vp8", "video/vp9", "video/av1":
+		if len(audio) == 0 || audio[0] == "audio/opus" {
+			return "webm", true
+		}
+	case "video/h264", "video/h265":
+		if len(audio) == 0 || audio[0] == "audio/opus" || audio[0] == "audio/aac" {
+			return "mp4", true
+		}
+	}
+
+	return "", false
+}
+
+// remuxArgs copies the streams of the tracks into the container, the video timestamps are scaled to the track duration
+// and the late tracks are shifted by their offsets. The side inputs follow the tracks, see sideInputs.
+func remuxArgs(src []model.MediaChannel, scales []float64, sideIn, sideOut []string, format string) []string {
+	var inputArgs, mapArgs []string
 
-		"-c:v", "libx264",
-		"-tune", "animation",
-		"-preset", "fast",
-		"-movflags",
-		"+faststart",
-		"-f", "mp4",
-		dst)
+	for i, s := range src {
+		if s.Offset > 0 {
+			inputArgs = append(inputArgs, "-itsoffset", fmt.Sprintf("%.3f", float64(s.Offset)/1000))
+		}
+
+		if strings.HasPrefix(s.MimeType, "video") {
+			inputArgs = append(inputArgs, "-fflags", "+genpts")
+			if math.Abs(scales[i]-1.0) > 0.001 {
+				inputArgs = append(inputArgs, "-itsscale", fmt.Sprintf("%.6f", scales[i]))
+			}
+			mapArgs = append(mapArgs, "-map", fmt.Sprintf("%d:v", i))
+		} else {
+			mapArgs = append(mapArgs, "-map", fmt.Sprintf("%d:a", i))
+		}
+
+		inputArgs = append(inputArgs, trackInput(s)...)
+	}
+
+	inputArgs = append(inputArgs, sideIn...)
+
+	args := append(inputArgs, mapArgs...)
+	args = append(args, "-c", "copy")
+	args = append(args, sideOut...)
+
+	for _, s := range src {
+		if strings.EqualFold(s.MimeType, "video/h265") {
+			// the tag the apple players expect
+			args = append(args, "-tag:v", "hvc1")
+		}
+	}
+
+	if format == "mp4" {
+		args = append(args, "-movflags", "+faststart")
+	}
+
+	return append(args, "-f", format)
+}
+
+// RemuxByPath writes the tracks into the container with the stream copy, see RemuxContainer.
+func RemuxByPath(src []model.MediaChannel, markers []model.Marker, subtitles, dst string, actualDurationMs int,
+	p *model.TranscodingProfile,
+) (int, error) {
+	args := []string{
+		"-nostdin",
+		"-threads", "1",
+	}
+
+	chapters, err := writeChapters(markers, recordingDuration(src, actualDurationMs), dst)
+	if err != nil {
+		return 0, err
+	}
+
+	if chapters != "" {
+		defer os.Remove(chapters)
+	}
+
+	sideIn, sideOut := sideInputs(len(src), chapters, subtitles, p.SubtitleCodec())
+	args = append(args, remuxArgs(src, trackScales(src, actualDurationMs), sideIn, sideOut, p.Format())...)
+	args = append(args, dst)
+
+	return runFFmpeg(args)
+}
 
+func runFFmpeg(args []string) (int, error) {
 	cmd := exec.Command("ffmpeg", args...)
 
 	var stderr bytes.Buffer
@@ -3,6 +3,10 @@ package utils
 import (
 	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/webitel/webrtc_recorder/internal/model"
 )
 
 func TestTranscoding(t *testing.T) {
@@ -35,3 +39,158 @@ func testVP9(t *testing.T) {
 		panic(err)
 	}
 }
+
+func TestOutputArgs(t *testing.T) {
+	p := model.TranscodingProfiles["audio"]
+	assert.Equal(t, []string{"-c:a", "aac", "-b:a", "96k", "-vn", "-movflags", "+faststart", "-f", "ipod"}, outputArgs(&p))
+
+	crf := 30
+	vp9 := &model.TranscodingProfile{VideoCodec: "libvpx-vp9", AudioCodec: "libopus", CRF: &crf, Preset: "fast", Container: "webm"}
+	assert.Equal(t, []string{"-c:a", "libopus", "-c:v", "libvpx-vp9", "-crf", "30", "-b:v", "0", "-f", "webm"}, outputArgs(vp9))
+
+	src := []model.MediaChannel{{MimeType: "video/VP9", Path: "v"}, {MimeType: "audio/opus", Path: "a"}}
+	p = model.TranscodingProfiles["hd720"]
+	_, filter := transcodingArgs(src, []float64{1, 1}, 0, "", &p)
+	assert.Contains(t, filter[1], "scale=1280:720")
+	assert.Contains(t, filter[1], ",fps=30[v_out]")
+}
+
+func TestRemuxContainer(t *testing.T) {
+	c, ok := RemuxContainer([]model.MediaChannel{{MimeType: "video/VP9"}, {MimeType: "audio/opus"}})
+	assert.True(t, ok)
+	assert.Equal(t, "webm", c)
+
+	c, ok = RemuxContainer([]model.MediaChannel{{MimeType: "video/H264"}})
+	assert.True(t, ok)
+	assert.Equal(t, "mp4", c)
+
+	// composition
+	_, ok = RemuxContainer([]model.MediaChannel{{MimeType: "video/H264"}, {MimeType: "video/H264"}})
+	assert.False(t, ok)
+
+	_, ok = RemuxContainer([]model.MediaChannel{{MimeType: "audio/opus"}})
+	assert.False(t, ok)
+
+	c, ok = RemuxContainer([]model.MediaChannel{{MimeType: "video/AV1"}, {MimeType: "audio/opus"}})
+	assert.True(t, ok)
+	assert.Equal(t, "webm", c)
+
+	c, ok = RemuxContainer([]model.MediaChannel{{MimeType: "video/H265"}})
+	assert.True(t, ok)
+	assert.Equal(t, "mp4", c)
+
+	args := remuxArgs([]model.MediaChannel{{MimeType: "video/H264", Path: "v"}, {MimeType: "audio/opus", Path: "a"}}, []float64{1.5, 1}, nil, nil, "mp4")
+	assert.Equal(t, []string{
+		"-fflags", "+genpts", "-itsscale", "1.500000", "-f", "h264", "-i", "v", "-i", "a",
+		"-map", "0:v", "-map", "1:a", "-c", "copy", "-movflags", "+faststart", "-f", "mp4",
+	}, args)
+}
+
+func TestTrackTiming(t *testing.T) {
+	// the late audio and the video stretched to its wall clock duration
+	src := []model.MediaChannel{
+		{MimeType: "video/VP8", Path: "v", Duration: 12000},
+		{MimeType: "audio/opus", Path: "a", Offset: 1500},
+	}
+	p := model.TranscodingProfiles[model.DefaultTranscodingProfile]
+	_, filter := transcodingArgs(src, []float64{timingScale(src[0], 15000, 10), 1}, 0, "", &p)
+	assert.Contains(t, filter[1], "[0:v]setpts=1.200000*(PTS-STARTPTS),scale=")
+	assert.Contains(t, filter[1], "[1:a]adelay=1500:all=1[a_d0];[a_d0]amix=inputs=1[a_out]")
+
+	// the late video
+	src[0].Offset, src[1].Offset = 2000, 0
+	_, filter = transcodingArgs(src, []float64{1, 1}, 0, "", &p)
+	assert.Contains(t, filter[1], "[0:v]setpts=PTS-STARTPTS,tpad=start_duration=2.000,scale=")
+	assert.Contains(t, filter[1], "[1:a]amix=inputs=1[a_out]")
+
+	// without the sender reports
+	assert.InDelta(t, 1.5, timingScale(model.MediaChannel{}, 15000, 10), 0.0001)
+	assert.InDelta(t, 1.0, timingScale(model.MediaChannel{}, 0, 10), 0.0001)
+
+	args := remuxArgs(src, []float64{1, 1}, nil, nil, "webm")
+	assert.Equal(t, []string{
+		"-itsoffset", "2.000", "-fflags", "+genpts", "-i", "v", "-i", "a",
+		"-map", "0:v", "-map", "1:a", "-c", "copy", "-f", "webm",
+	}, args)
+}
+
+func TestFillGaps(t *testing.T) {
+	src := []model.MediaChannel{
+		{MimeType: "video/VP8", Path: "v", Gaps: []model.Gap{{At: 2000, Duration: 1500}}},
+		{MimeType: "audio/opus", Path: "a", Offset: 500, Gaps: []model.Gap{{At: 1000, Duration: 500}, {At: 3000, Duration: 250}}},
+	}
+	p := model.TranscodingProfiles[model.DefaultTranscodingProfile]
+	_, filter := transcodingArgs(src, []float64{1, 1}, 0, "", &p)
+
+	assert.Contains(t, filter[1], "[0:v]setpts=PTS-STARTPTS,split=2[v0_p0][v0_p1];"+
+		"[v0_p0]trim=start=0.000:end=2.000,setpts=PTS-STARTPTS,tpad=stop_mode=clone:stop_duration=1.500[v0_g0];"+
+		"[v0_p1]trim=start=2.000,setpts=PTS-STARTPTS[v0_g1];"+
+		"[v0_g0][v0_g1]concat=n=2:v=1:a=0,scale=")
+	assert.Contains(t, filter[1], "[1:a]asplit=3[a1_p0][a1_p1][a1_p2];"+
+		"[a1_p0]atrim=start=0.000:end=1.000,asetpts=PTS-STARTPTS,apad=pad_dur=0.500[a1_g0];"+
+		"[a1_p1]atrim=start=1.000:end=3.000,asetpts=PTS-STARTPTS,apad=pad_dur=0.250[a1_g1];"+
+		"[a1_p2]atrim=start=3.000,asetpts=PTS-STARTPTS[a1_g2];"+
+		"[a1_g0][a1_g1][a1_g2]concat=n=3:v=0:a=1,adelay=500:all=1[a_d0];[a_d0]amix=inputs=1[a_out]")
+
+	// the gaps are filled after the scale
+	assert.InDelta(t, 1.2, timingScale(model.MediaChannel{Duration: 13500, Gaps: src[0].Gaps}, 0, 10), 0.0001)
+
+	_, ok := RemuxContainer(src)
+	assert.False(t, ok)
+}
+
+func TestRedactedGap(t *testing.T) {
+	src := []model.MediaChannel{{MimeType: "video/VP8", Path: "v", Gaps: []model.Gap{{At: 1000, Duration: 2000, Redacted: true}}}}
+	p := model.TranscodingProfiles[model.DefaultTranscodingProfile]
+	_, filter := transcodingArgs(src, []float64{1}, 0, "", &p)
+
+	assert.Contains(t, filter[1], "[v0_p0]trim=start=0.000:end=1.000,setpts=PTS-STARTPTS,tpad=stop_mode=add:color=black:stop_duration=2.000[v0_g0];")
+}
+
+func TestRtpTimed(t *testing.T) {
+	clock := []model.RtpClock{{RtpTs: 0, Time: 1000}}
+
+	testCases := []struct {
+		name     string
+		ch       model.MediaChannel
+		expected bool
+	}{
+		{
+			name:     "ivf with the sender reports",
+			ch:       model.MediaChannel{MimeType: "video/VP8", Clock: clock},
+			expected: true,
+		},
+		{
+			name: "ivf without the sender reports",
+			ch:   model.MediaChannel{MimeType: "video/AV1"},
+		},
+		{
+			name: "raw h264 stream",
+			ch:   model.MediaChannel{MimeType: "video/H264", Clock: clock},
+		},
+		{
+			name: "audio",
+			ch:   model.MediaChannel{MimeType: "audio/opus", Clock: clock},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, rtpTimed(tc.ch))
+		})
+	}
+}
+
+func TestTimestampGaps(t *testing.T) {
+	src := []model.MediaChannel{{
+		MimeType: "video/VP8", Path: "v", Clock: []model.RtpClock{{RtpTs: 0, Time: 1000}},
+		Gaps: []model.Gap{{At: 1000, Duration: 500}, {At: 3000, Duration: 2000, Redacted: true}},
+	}}
+	p := model.TranscodingProfiles[model.DefaultTranscodingProfile]
+	_, filter := transcodingArgs(src, []float64{1}, 0, "", &p)
+
+	// the frames after the gap keep their timestamps
+	assert.Contains(t, filter[1], "[v0_p0]trim=start=0.000:end=1.000,setpts=PTS-STARTPTS,tpad=stop_mode=clone:stop_duration=0.500[v0_g0];"+
+		"[v0_p1]trim=start=1.000:end=3.500,setpts=PTS-STARTPTS,tpad=stop_mode=add:color=black:stop_duration=2.000[v0_g1];"+
+		"[v0_p2]trim=start=3.500,setpts=PTS-STARTPTS[v0_g2];")
+}